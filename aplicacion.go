@@ -1,10 +1,12 @@
 package aplicacion
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -14,8 +16,11 @@ import (
 	"github.com/hernanatn/aplicacion.go/consola"
 	"github.com/hernanatn/aplicacion.go/consola/cadena"
 	"github.com/hernanatn/aplicacion.go/consola/color"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"github.com/hernanatn/aplicacion.go/formato"
 	"github.com/hernanatn/aplicacion.go/menu"
 	"github.com/hernanatn/aplicacion.go/menu/multimenu"
+	"github.com/hernanatn/aplicacion.go/pregunta"
 	"github.com/hernanatn/aplicacion.go/utiles"
 )
 
@@ -37,17 +42,21 @@ const (
 )
 
 var (
-	NuevaConsola   = consola.NuevaConsola
-	NuevoComando   = comando.NuevoComando
-	NuevoMenu      = menu.NuevoMenu
-	NuevoMultiMenu = multimenu.NuevoMultiMenu
+	NuevaConsola            = consola.NuevaConsola
+	NuevaConsolaGrabada     = consola.NuevaConsolaGrabada
+	NuevaConsolaReproducida = consola.NuevaConsolaReproducida
+	LeerGrabacion           = consola.LeerGrabacion
+	NuevoComando            = comando.NuevoComando
+	NuevoMenu               = menu.NuevoMenu
+	NuevoMultiMenu          = multimenu.NuevoMultiMenu
 )
 
 type Aplicacion interface {
-	Consola
+	consola.ConsolaExtendida
 	Comando
 
 	Correr(args ...string) (r any, err error)
+	CorrerMenu(args ...string) (r any, err error)
 
 	Inicializar(...string) error
 	Limpiar(...string) error
@@ -62,6 +71,7 @@ type Aplicacion interface {
 	RegistrarLimpieza(f FUN) Aplicacion
 	RegistrarFinal(f FUN) Aplicacion
 	RegistrarComando(Comando) Aplicacion
+	ConfirmarPegado(bool) Aplicacion
 
 	DebeCerrar() bool
 }
@@ -74,12 +84,17 @@ type aplicacion struct {
 	Opciones    []string
 
 	prefijo    Cadena
-	consola    Consola
+	consola    consola.ConsolaExtendida
 	comandos   []Comando
 	debeCerrar bool
+	formato    string
+	plantilla  string // Plantilla de salida de la invocación en curso: la de --formato o la declarada por el comando
+	porUsuario bool   // La plantilla proviene de --formato
 	ini        FUN
 	lim        FUN
 	fin        FUN
+
+	confirmarPegado bool // Ver ConfirmarPegado
 }
 
 type FUN func(c Aplicacion, args ...string) error
@@ -95,29 +110,36 @@ func (a *aplicacion) Finalizar(args ...string) error {
 }
 
 func (a aplicacion) TextoAyuda() string {
-	return a.Nombre + cadena.TextoJustificado(a.Descripcion, 40, cadena.OpcionesFormato{Sangria: strings.Repeat(" ", 20-len(a.Nombre)-2), Prefijo: strings.Repeat(" ", 20), Color: color.GrisFuente}) + "\n"
+	return a.TextoAyudaTema(cadena.TemaPredeterminado)
+}
+
+func (a aplicacion) TextoAyudaTema(t cadena.Tema) string {
+	return a.Nombre + cadena.TextoJustificado(a.Descripcion, 40, cadena.OpcionesFormato{Sangria: strings.Repeat(" ", max(1, 20-cadena.Ancho(a.Nombre)-2)), Prefijo: strings.Repeat(" ", 20), Color: t.Estilo(cadena.ROL_ATENUADO).Secuencia()}) + "\n"
 }
 
 func (a *aplicacion) Ayuda(_ Consola, args ...string) {
+	a.consola.IniciarPaginado()
+	defer a.consola.FinalizarPaginado()
 	if len(args) > 0 {
 		c, existe := a.buscarComando(args[0])
 		if existe {
 			c.Ayuda(a, args[:1]...)
 		}
 	}
-	a.ImprimirCadena(Cadena(cadena.Titulo(a.Nombre)))
-	a.ImprimirCadena(Cadena(cadena.Subtitulo(a.Descripcion)))
-	a.consola.EscribirLinea(Cadena("Uso:").Subrayada())
+	tema := a.Tema()
+	a.ImprimirCadena(tema.Titulo(a.Nombre))
+	a.ImprimirCadena(tema.Subtitulo(a.Descripcion))
+	a.consola.EscribirLinea(tema.Aplicar(cadena.ROL_SUBTITULO, "Uso:"))
 	a.consola.EscribirLinea(Cadena("\t" + a.Uso))
 	//a.consola.EscribirLinea(Cadena("Ayuda").Negrita().Subrayada())
-	a.consola.EscribirLinea(Cadena("Comandos:").Subrayada())
+	a.consola.EscribirLinea(tema.Aplicar(cadena.ROL_SUBTITULO, "Comandos:"))
 	for _, c := range a.comandos {
 		if !c.EsOculto() && !slices.Contains(args, "-v") {
-			a.consola.EscribirCadena(Cadena("  " + c.TextoAyuda()))
+			a.consola.EscribirCadena(Cadena("  " + comando.TextoAyuda(c, tema)))
 		}
 	}
 	if len(a.Opciones) > 0 {
-		a.consola.EscribirLinea(Cadena("Opciones Generales:").Subrayada())
+		a.consola.EscribirLinea(tema.Aplicar(cadena.ROL_SUBTITULO, "Opciones Generales:"))
 		for _, o := range a.Opciones {
 			a.consola.EscribirCadena(Cadena("\t" + o))
 		}
@@ -148,6 +170,10 @@ func (a *aplicacion) RegistrarComando(sub Comando) Aplicacion {
 	return a
 }
 
+func (a aplicacion) DevolverSubcomandos() []Comando {
+	return a.comandos
+}
+
 func (a aplicacion) buscarComando(nombre string) (Comando, bool) {
 	for _, a := range a.comandos {
 		if a.DevolverNombre() == nombre || slices.Contains(a.DevolverAliases(), nombre) {
@@ -187,7 +213,133 @@ func (a aplicacion) DescifrarOpciones(opciones []string) (Parametros, Opciones,
 	return parametros, banderas, argumentos
 }
 
+// Opción general que selecciona el formato con el que se presenta el resultado de un comando.
+const OPCION_SALIDA = "--salida"
+
+// Quita "--salida <formato>" o "--salida=<formato>" de opciones y devuelve el formato indicado, o actual si no se indicó ninguno.
+func extraerFormato(actual string, opciones []string) (string, []string, error) {
+	elegido := actual
+	restantes := make([]string, 0, len(opciones))
+	for i := 0; i < len(opciones); i++ {
+		o := opciones[i]
+		switch {
+		case o == OPCION_SALIDA:
+			if i+1 >= len(opciones) {
+				return actual, opciones, fmt.Errorf("la opción %s requiere un formato (%s)", OPCION_SALIDA, strings.Join(formato.Formatos(), "|"))
+			}
+			elegido = opciones[i+1]
+			i++
+		case strings.HasPrefix(o, OPCION_SALIDA+"="):
+			elegido = strings.TrimPrefix(o, OPCION_SALIDA+"=")
+		default:
+			restantes = append(restantes, o)
+			continue
+		}
+		if !formato.Existe(elegido) {
+			return actual, opciones, fmt.Errorf("formato de salida desconocido: %q (disponibles: %s)", elegido, strings.Join(formato.Formatos(), "|"))
+		}
+	}
+	return elegido, restantes, nil
+}
+
+// Opción general que presenta el resultado de un comando con una plantilla de text/template, por ejemplo
+// --formato '{{.Nombre | negrita}}'. Ver formato.Plantilla y cadena.FuncionesPlantilla.
+const OPCION_FORMATO = "--formato"
+
+// Quita "--formato <plantilla>" o "--formato=<plantilla>" de opciones y devuelve la plantilla indicada, o "" si no se indicó ninguna.
+func extraerPlantilla(opciones []string) (string, []string, error) {
+	plantilla := ""
+	restantes := make([]string, 0, len(opciones))
+	for i := 0; i < len(opciones); i++ {
+		o := opciones[i]
+		switch {
+		case o == OPCION_FORMATO:
+			if i+1 >= len(opciones) {
+				return "", opciones, fmt.Errorf("la opción %s requiere una plantilla, por ejemplo '{{.Nombre}}'", OPCION_FORMATO)
+			}
+			plantilla = opciones[i+1]
+			i++
+		case strings.HasPrefix(o, OPCION_FORMATO+"="):
+			plantilla = strings.TrimPrefix(o, OPCION_FORMATO+"=")
+		default:
+			restantes = append(restantes, o)
+		}
+	}
+	return plantilla, restantes, nil
+}
+
+// Quita de opciones las opciones generales de formato (--salida y --formato), para encontrar el comando que se invoca.
+func quitarOpcionesFormato(opciones []string) ([]string, error) {
+	_, restantes, err := extraerFormato("", opciones)
+	if err != nil {
+		return opciones, err
+	}
+	_, restantes, err = extraerPlantilla(restantes)
+	return restantes, err
+}
+
+// Devuelve true si la invocación en curso usa un formato pensado para otros programas (json, yaml, csv...)
+// o una plantilla indicada con --formato.
+func (a aplicacion) esFormatoMaquina() bool {
+	return (a.porUsuario && a.plantilla != "") || formato.EsMaquina(a.formato)
+}
+
+// Devuelve el codificador de la invocación en curso: la plantilla de --formato, la del comando en formato texto,
+// o el del formato elegido.
+func (a aplicacion) codificador() formato.Codificador {
+	if a.plantilla != "" && (a.porUsuario || a.formato == formato.TEXTO) {
+		return formato.Plantilla(a.plantilla, a.Tema())
+	}
+	return func(w io.Writer, v any) error {
+		return formato.Codificar(w, a.formato, v)
+	}
+}
+
+// Presenta res en el formato de la invocación en curso.
+func (a aplicacion) renderizar(res any) error {
+	if res == nil {
+		return nil
+	}
+	if a.esFormatoMaquina() {
+		err := a.codificador()(a.consola, res)
+		return errors.Join(err, a.consola.Imprimir())
+	}
+	// Los resultados que no entran en la terminal se presentan paginados.
+	a.consola.IniciarPaginado()
+	err := a.codificador()(a.consola, res)
+	return errors.Join(err, a.consola.FinalizarPaginado())
+}
+
 func (a *aplicacion) Ejecutar(_ Consola, opciones ...string) (res any, cod comando.CodigoError, err error) {
+	formatoPrevio, plantillaPrevia, porUsuarioPrevio := a.formato, a.plantilla, a.porUsuario
+	defer func() { a.formato, a.plantilla, a.porUsuario = formatoPrevio, plantillaPrevia, porUsuarioPrevio }()
+	a.formato, opciones, err = extraerFormato(a.formato, opciones)
+	if err != nil {
+		a.ImprimirError("No se pudo interpretar la opción "+OPCION_SALIDA, err)
+		return nil, comando.ERROR, err
+	}
+	a.plantilla, opciones, err = extraerPlantilla(opciones)
+	if err != nil {
+		a.ImprimirError("No se pudo interpretar la opción "+OPCION_FORMATO, err)
+		return nil, comando.ERROR, err
+	}
+	a.porUsuario = a.plantilla != ""
+	if !a.porUsuario && len(opciones) > 0 {
+		if sc, existe := a.buscarComando(opciones[0]); existe {
+			a.plantilla = comando.Plantilla(comando.Resolver(sc, opciones[1:]...))
+		}
+	}
+
+	res, cod, err = a.ejecutar(opciones...)
+	if err == nil {
+		if errR := a.renderizar(res); errR != nil {
+			return res, comando.ERROR, errR
+		}
+	}
+	return res, cod, err
+}
+
+func (a *aplicacion) ejecutar(opciones ...string) (res any, cod comando.CodigoError, err error) {
 
 	if len(opciones) > 0 {
 		sc, existe := a.buscarComando(opciones[0])
@@ -220,6 +372,13 @@ func (a *aplicacion) RegistrarFinal(f FUN) Aplicacion {
 	return a
 }
 
+// Indica si Correr pide confirmación antes de ejecutar varios comandos pegados de una vez, uno por línea.
+// Por defecto la pide.
+func (a *aplicacion) ConfirmarPegado(confirmar bool) Aplicacion {
+	a.confirmarPegado = confirmar
+	return a
+}
+
 func (a aplicacion) Leer(c Cadena) (Cadena, error) {
 	if a.Prefijo().Limpiar().S() == "" {
 		return a.consola.Leer(c)
@@ -234,7 +393,13 @@ func (a aplicacion) LeerPrefijo(_ Cadena, c Cadena) (Cadena, error) {
 func (e aplicacion) Read(p []byte) (n int, err error) {
 	return e.consola.Read(p)
 }
+
+// En formatos de máquina lo que se escribe se descarta, como con los métodos Imprimir* y Escribir*: a la salida sólo llega
+// el resultado de la acción.
 func (s aplicacion) Write(p []byte) (n int, err error) {
+	if s.esFormatoMaquina() {
+		return len(p), nil
+	}
 	return s.consola.Write(p)
 }
 
@@ -265,16 +430,25 @@ func (s aplicacion) Write(p []byte) (n int, err error) {
 */
 
 func (a aplicacion) BorrarLinea() error {
+	if a.esFormatoMaquina() {
+		return nil
+	}
 	return a.consola.BorrarLinea()
 }
 func (a aplicacion) Imprimir() error {
 	return a.consola.Imprimir()
 }
 func (a aplicacion) ImprimirLinea(c Cadena) error {
+	if a.esFormatoMaquina() {
+		return nil
+	}
 	return a.consola.ImprimirLinea(c)
 }
 
 func (a aplicacion) ImprimirSeparador() {
+	if a.esFormatoMaquina() {
+		return
+	}
 	a.consola.ImprimirSeparador()
 
 }
@@ -285,12 +459,33 @@ func (a aplicacion) DebeCerrar() bool {
 	return a.debeCerrar
 }
 
+// Devuelve la ruta del archivo con la configuración de teclado de la aplicación nombre (ver teclado.Configuracion):
+// teclas.json en el directorio de configuración del usuario, por ejemplo ~/.config/nombre/teclas.json. Correr y
+// CorrerMenu lo cargan, si existe, antes de inicializar la aplicación.
+func RutaConfiguracionTeclado(nombre string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, nombre, "teclas.json")
+}
+
+func (a aplicacion) cargarConfiguracionTeclado() {
+	if ruta := RutaConfiguracionTeclado(a.Nombre); ruta != "" {
+		if err := teclado.CargarConfiguracion(ruta); err != nil {
+			a.ImprimirAdvertencia("No se pudo cargar la configuración de teclado", err)
+		}
+	}
+}
+
 func (a *aplicacion) Correr(args ...string) (r any, err error) {
 	var res any
+	a.cargarConfiguracionTeclado()
 	ctrlC := make(chan os.Signal, 1)
 	signal.Notify(ctrlC, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-ctrlC
+		a.UsarRaton(false)
 		a.Limpiar(args...)
 		a.ImprimirError("Programa terminado por el usuario: [CTRL+C]", nil)
 		os.Exit(1)
@@ -302,11 +497,15 @@ func (a *aplicacion) Correr(args ...string) (r any, err error) {
 		return nil, *new(error)
 	}
 
+	// El --salida de los argumentos de inicio queda como formato predeterminado de la sesión.
+	if f, _, err := extraerFormato(a.formato, args); err == nil {
+		a.formato = f
+	}
 	a.Ejecutar(a.consola, args...)
 	for !a.DebeCerrar() {
 		entrada, err := a.Leer("")
 		if err != nil {
-			if err == io.EOF {
+			if err == io.EOF || errors.Is(err, consola.ERROR_INTERRUMPIDO) {
 				a.debeCerrar = true
 				a.Limpiar(args...)
 				a.ImprimirError("Programa terminado por el usuario: [CTRL+C]", nil)
@@ -317,27 +516,101 @@ func (a *aplicacion) Correr(args ...string) (r any, err error) {
 			return nil, err
 		}
 
-		argumentos := strings.Split(entrada.Limpiar().S(), " ")
-		var com Comando
-		nombreComando := argumentos[0]
-		com, existe := a.buscarComando(nombreComando)
-		opciones := argumentos[1:]
-		switch {
-		case len(argumentos) < 1 || len(argumentos) == 1 && nombreComando == "":
-			continue
-		case !existe:
-			a.ImprimirError(cadena.Cadena(fmt.Sprintf("Se intento ejecutar el comando: %s. Pero el comando no existe", nombreComando)), nil)
-			a.Ayuda(a)
-			return nil, nil
+		// Un texto pegado puede traer varios comandos, uno por línea.
+		lineas := []Cadena{}
+		for _, l := range strings.Split(entrada.S(), "\n") {
+			if l := Cadena(l).Limpiar(); l != "" {
+				lineas = append(lineas, l)
+			}
 		}
-		var cod comando.CodigoError
-		res, cod, err = com.Ejecutar(a, opciones...)
+		if len(lineas) > 1 && a.confirmarPegado {
+			ejecutar, err := pregunta.Confirmar(a, Cadena(fmt.Sprintf("¿Ejecutar los %d comandos pegados?", len(lineas))), false)
+			if err != nil || !ejecutar {
+				continue
+			}
+		}
+
+		for _, linea := range lineas {
+			if len(lineas) > 1 {
+				a.ImprimirLinea(a.Tema().Señalador(">") + linea)
+			}
+			argumentos := strings.Split(linea.S(), " ")
+			restantes, err := quitarOpcionesFormato(argumentos)
+			if err != nil {
+				a.ImprimirError("No se pudo interpretar la línea", err)
+				continue
+			}
+			nombreComando := ""
+			if len(restantes) > 0 {
+				nombreComando = restantes[0]
+			}
+			if _, existe := a.buscarComando(nombreComando); !existe && nombreComando != "" {
+				a.ImprimirError(cadena.Cadena(fmt.Sprintf("Se intento ejecutar el comando: %s. Pero el comando no existe", nombreComando)), nil)
+				a.Ayuda(a)
+				return nil, nil
+			}
+			var cod comando.CodigoError
+			res, cod, err = a.Ejecutar(a, argumentos...)
 
+			if err != nil {
+				a.ImprimirFatal(cadena.Cadena(strconv.Itoa(int(cod))), err)
+				a.ImprimirFatal("No se pudo ejecutar correctamente la aplicacion", err)
+				return nil, err
+			}
+		}
+	}
+
+	err = a.Finalizar(args...)
+	if err != nil {
+		a.Limpiar(args...)
+		a.ImprimirFatal("No se pudo finalizar correctamente la aplicacion", err)
+		return nil, *new(error)
+	}
+
+	return res, nil
+}
+
+// Como Correr, pero en lugar de leer comandos de la entrada presenta un menú con los comandos registrados
+// (ver menu.DesdeComando). Se piden los argumentos del comando elegido, se lo ejecuta y se vuelve al menú,
+// hasta que se lo cierre con Esc.
+func (a *aplicacion) CorrerMenu(args ...string) (r any, err error) {
+	var res any
+	a.cargarConfiguracionTeclado()
+	err = a.Inicializar(args...)
+	if err != nil {
+		a.Limpiar(args...)
+		a.ImprimirFatal("No se pudo inicializar la aplicacion", err)
+		return nil, *new(error)
+	}
+
+	m := menu.DesdeComando(a, a)
+	for !a.DebeCerrar() {
+		o, err := m.Correr()
+		if errors.Is(err, menu.ERROR_SALIDA) {
+			break
+		}
 		if err != nil {
-			a.ImprimirFatal(cadena.Cadena(strconv.Itoa(int(cod))), err)
-			a.ImprimirFatal("No se pudo ejecutar correctamente la aplicacion", err)
+			a.Limpiar(args...)
+			a.ImprimirFatal("No se pudo presentar el menú de la aplicación", err)
 			return nil, err
 		}
+		if o == nil {
+			break
+		}
+		ruta, _ := menu.ValorDe[[]string](o)
+		entrada, err := a.Leer(Cadena("Argumentos de " + strings.Join(ruta, " ") + " (Enter para ninguno)"))
+		if err != nil {
+			break
+		}
+		argumentos := []any{}
+		for _, arg := range strings.Fields(entrada.Limpiar().S()) {
+			argumentos = append(argumentos, arg)
+		}
+		var cod comando.CodigoError
+		res, cod, err = o.Accion(a, nil, nil, argumentos...)
+		if err != nil {
+			a.ImprimirError(cadena.Cadena(fmt.Sprintf("No se pudo ejecutar %s (%d)", o.Nombre, cod)), err)
+		}
 	}
 
 	err = a.Finalizar(args...)
@@ -350,15 +623,20 @@ func (a *aplicacion) Correr(args ...string) (r any, err error) {
 	return res, nil
 }
 
-func NuevaAplicacion(nombre string, uso string, descripcion string, opciones []string, consola Consola) Aplicacion {
+// Crea una Aplicacion que lee y escribe a través de con. Si con no implementa consola.ConsolaExtendida, la Aplicacion
+// usa los comportamientos por defecto de consola.Extender.
+func NuevaAplicacion(nombre string, uso string, descripcion string, opciones []string, con Consola) Aplicacion {
 
 	a := &aplicacion{
 		Nombre:      nombre,
 		Uso:         uso,
 		Descripcion: descripcion,
 		Opciones:    opciones,
-		consola:     consola,
+		consola:     consola.Extender(con),
 		prefijo:     "",
+		formato:     formato.TEXTO,
+
+		confirmarPegado: true,
 	}
 
 	a.RegistrarComando(
@@ -397,54 +675,160 @@ func (a aplicacion) LeerContraseña(mensaje Cadena) (Cadena, error) {
 func (a aplicacion) LeerTecla(b *[]byte) (int, error) {
 	return a.consola.LeerTecla(b)
 }
+func (a aplicacion) LeerEvento() (teclado.Tecla, error) {
+	return a.consola.LeerEvento()
+}
+func (a aplicacion) PosicionCursor() (int, int, error) {
+	return a.consola.PosicionCursor()
+}
+func (a aplicacion) UsarRaton(usar bool) {
+	a.consola.UsarRaton(usar)
+}
+func (a aplicacion) HabilitarRaton() bool {
+	return a.consola.HabilitarRaton()
+}
+func (a aplicacion) DeshabilitarRaton() {
+	a.consola.DeshabilitarRaton()
+}
 
 // Escribe la Cadena al buffer y llama Imprimir()
 func (a aplicacion) ImprimirCadena(cadena Cadena) error {
+	if a.esFormatoMaquina() {
+		return nil
+	}
 	return a.consola.ImprimirCadena(cadena)
 }
 
 // Escribe los bytes al buffer y llama Imprimir()
 func (a aplicacion) ImprimirBytes(b []byte) error {
+	if a.esFormatoMaquina() {
+		return nil
+	}
 	return a.consola.ImprimirBytes(b)
 }
 
 // Escribe la Cadena al buffer
 func (a aplicacion) EscribirCadena(cadena Cadena) error {
+	if a.esFormatoMaquina() {
+		return nil
+	}
 	return a.consola.EscribirCadena(cadena)
 }
 
 // Escribe la Cadena +\r\n al buffer
 func (a aplicacion) EscribirLinea(cadena Cadena) error {
+	if a.esFormatoMaquina() {
+		return nil
+	}
 	return a.consola.EscribirLinea(cadena)
 }
 
 // Escribe los bytes al buffers
 func (a aplicacion) EscribirBytes(b []byte) error {
+	if a.esFormatoMaquina() {
+		return nil
+	}
 	return a.consola.EscribirBytes(b)
 }
 
 // Escribe la Cadena al buffer, la formatea como Advertencia y llama Imprimir()
-func (a aplicacion) ImprimirAdvertencia(cadena Cadena, e error) error {
-	return a.consola.ImprimirAdvertencia(cadena, e)
+func (a aplicacion) ImprimirAdvertencia(c Cadena, e error) error {
+	if a.esFormatoMaquina() {
+		return a.diagnostico(a.Tema().Advertencia(c.S(), e))
+	}
+	return a.consola.ImprimirAdvertencia(c, e)
 }
 
 // Escribe la Cadena al buffer, la formatea como Error y llama Imprimir()
-func (a aplicacion) ImprimirError(cadena Cadena, e error) error {
-	return a.consola.ImprimirError(cadena, e)
+func (a aplicacion) ImprimirError(c Cadena, e error) error {
+	if a.esFormatoMaquina() {
+		return a.diagnostico(a.Tema().Error(c.S(), e))
+	}
+	return a.consola.ImprimirError(c, e)
 }
 
 // Escribe la Cadena al buffer, la formatea como Fatal y llama Imprimir()
-func (a aplicacion) ImprimirFatal(cadena Cadena, e error) error {
-	return a.consola.ImprimirFatal(cadena, e)
+func (a aplicacion) ImprimirFatal(c Cadena, e error) error {
+	if a.esFormatoMaquina() {
+		return a.diagnostico(a.Tema().Fatal(c.S(), e))
+	}
+	return a.consola.ImprimirFatal(c, e)
+}
+
+// En formatos de máquina los mensajes de diagnóstico van a la salida de diagnóstico, para no mezclarse con el resultado.
+func (a aplicacion) diagnostico(s string) error {
+	return a.consola.ImprimirDiagnostico(Cadena(s))
 }
 
 func (a aplicacion) EsTerminal() bool {
 	return a.consola.EsTerminal()
 }
 
+func (a aplicacion) NivelColor() color.Nivel {
+	return a.consola.NivelColor()
+}
+func (a aplicacion) AsignarNivelColor(n color.Nivel) {
+	a.consola.AsignarNivelColor(n)
+}
+
+func (a aplicacion) Tema() cadena.Tema {
+	return a.consola.Tema()
+}
+func (a aplicacion) AsignarTema(t cadena.Tema) {
+	a.consola.AsignarTema(t)
+}
+
+func (a aplicacion) IniciarPaginado() {
+	a.consola.IniciarPaginado()
+}
+func (a aplicacion) FinalizarPaginado() error {
+	return a.consola.FinalizarPaginado()
+}
+func (a aplicacion) Paginar(c Cadena) error {
+	if a.esFormatoMaquina() {
+		return nil
+	}
+	return a.consola.Paginar(c)
+}
+
+func (a aplicacion) NuevaBarra(descripcion string, total int64) *consola.BarraProgreso {
+	return a.NuevoGrupoProgreso().Agregar(consola.OpcionesProgreso{Descripcion: descripcion, Total: total})
+}
+func (a aplicacion) NuevaEspera(descripcion string) *consola.BarraProgreso {
+	return a.NuevoGrupoProgreso().Agregar(consola.OpcionesProgreso{Descripcion: descripcion})
+}
+
+// En formatos de máquina el progreso se presenta en la salida de diagnóstico (ver SalidaDiagnostico).
+func (a aplicacion) NuevoGrupoProgreso() *consola.GrupoProgreso {
+	if a.esFormatoMaquina() {
+		return consola.NuevoGrupoProgreso(a.consola.SalidaDiagnostico(), a.Tema())
+	}
+	return a.consola.NuevoGrupoProgreso()
+}
+
+// Como NuevoGrupoProgreso, en formatos de máquina los pasos se presentan en la salida de diagnóstico.
+func (a aplicacion) NuevaListaPasos() *consola.ListaPasos {
+	if a.esFormatoMaquina() {
+		return consola.NuevaListaPasos(a.consola.SalidaDiagnostico(), a.Tema())
+	}
+	return a.consola.NuevaListaPasos()
+}
+
 func (a aplicacion) FEntrada() *os.File {
 	return a.consola.FEntrada()
 }
 func (a aplicacion) FSalida() *os.File {
 	return a.consola.FSalida()
 }
+func (a aplicacion) SalidaDiagnostico() *consola.Salida {
+	return a.consola.SalidaDiagnostico()
+}
+func (a aplicacion) AsignarSalidaDiagnostico(s *consola.Salida) {
+	a.consola.AsignarSalidaDiagnostico(s)
+}
+func (a aplicacion) ImprimirDiagnostico(c Cadena) error {
+	return a.consola.ImprimirDiagnostico(c)
+}
+func (a aplicacion) DevolverTamaño() (int, int, error) {
+	return a.consola.DevolverTamaño()
+}
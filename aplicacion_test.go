@@ -298,3 +298,169 @@ func TestComandosAnidados(t *testing.T) {
 	assert.False(t, padreEjecutado)
 	assert.True(t, hijoEjecutado)
 }
+
+// TestFormatoDeSalida prueba que --salida presente el resultado del comando en el formato pedido sin salida decorativa
+func TestFormatoDeSalida(t *testing.T) {
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	con := consola.NuevaConsola(os.Stdin, escritor)
+
+	app := aplicacion.NuevaAplicacion(
+		"app-prueba",
+		"uso de prueba",
+		"Descripción de Prueba",
+		[]string{},
+		con,
+	)
+
+	type item struct {
+		Nombre   string `json:"nombre"`
+		Cantidad int    `json:"cantidad"`
+	}
+	cmdPrueba := comando.NuevoComando(
+		"listar",
+		"listar",
+		[]string{},
+		"Lista ítems",
+		func(con comando.Consola, opt comando.Opciones, params comando.Parametros, args ...any) (any, comando.CodigoError, error) {
+			con.ImprimirCadena(aplicacion.Cadena("Listado").Negrita())
+			return []item{{"uno", 1}, {"dos", 2}}, comando.EXITO, nil
+		},
+		[]string{},
+	)
+	app.RegistrarComando(cmdPrueba)
+
+	_, _, err = app.Ejecutar(con, "listar", "--salida", "json")
+	require.NoError(t, err)
+	_, _, err = app.Ejecutar(con, "listar", "--salida=csv")
+	require.NoError(t, err)
+	_, _, err = app.Ejecutar(con, "listar", "--salida", "xml")
+	assert.Error(t, err)
+
+	escritor.Close()
+	salida, _ := io.ReadAll(lector)
+	assert.NotContains(t, string(salida), "Listado")
+	assert.Contains(t, string(salida), `"nombre": "uno"`)
+	assert.Contains(t, string(salida), "nombre,cantidad\nuno,1\ndos,2\n")
+}
+
+// TestDiagnosticoEnFormatoDeMaquina prueba que en formatos de máquina los avisos vayan a la salida de diagnóstico y lo
+// escrito con Write se descarte
+func TestDiagnosticoEnFormatoDeMaquina(t *testing.T) {
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	lectorDiagnostico, escritorDiagnostico, err := os.Pipe()
+	require.NoError(t, err)
+	defer lectorDiagnostico.Close()
+	con := consola.NuevaConsola(os.Stdin, escritor)
+	con.AsignarSalidaDiagnostico(consola.NuevaSalida(escritorDiagnostico))
+
+	app := aplicacion.NuevaAplicacion("app-prueba", "uso de prueba", "Descripción de Prueba", []string{}, con)
+	app.RegistrarComando(comando.NuevoComando(
+		"contar",
+		"contar",
+		[]string{},
+		"Cuenta",
+		func(con comando.Consola, opt comando.Opciones, params comando.Parametros, args ...any) (any, comando.CodigoError, error) {
+			io.WriteString(con, "Contando...\n")
+			con.ImprimirAdvertencia("Cuenta aproximada", nil)
+			return map[string]int{"total": 3}, comando.EXITO, nil
+		},
+		[]string{},
+	))
+
+	_, _, err = app.Ejecutar(con, "contar", "--salida", "json")
+	require.NoError(t, err)
+	escritor.Close()
+	escritorDiagnostico.Close()
+
+	salida, _ := io.ReadAll(lector)
+	diagnostico, _ := io.ReadAll(lectorDiagnostico)
+	assert.JSONEq(t, `{"total": 3}`, string(salida))
+	assert.Contains(t, string(diagnostico), "Cuenta aproximada")
+	assert.NotContains(t, string(diagnostico), "Contando")
+}
+
+// TestPlantillaDeSalida prueba la plantilla declarada por un comando y la indicada con --formato
+func TestPlantillaDeSalida(t *testing.T) {
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	con := consola.NuevaConsola(os.Stdin, escritor)
+
+	app := aplicacion.NuevaAplicacion("app-prueba", "uso de prueba", "Descripción de Prueba", []string{}, con)
+
+	type item struct {
+		Nombre   string
+		Cantidad int
+	}
+	listar := func(con comando.Consola, opt comando.Opciones, params comando.Parametros, args ...any) (any, comando.CodigoError, error) {
+		return []item{{"uno", 1}, {"dos", 2}}, comando.EXITO, nil
+	}
+	grupo := comando.NuevoComando("grupo", "grupo", []string{}, "Grupo", nil, []string{})
+	grupo.RegistrarComando(comando.NuevoComando("listar", "listar", []string{}, "Lista ítems", listar, []string{},
+		comando.Config{Plantilla: "{{.Nombre}}={{.Cantidad}}"}))
+	app.RegistrarComando(grupo)
+
+	_, _, err = app.Ejecutar(con, "grupo", "listar")
+	require.NoError(t, err)
+	_, _, err = app.Ejecutar(con, "grupo", "listar", "--formato", "{{.Nombre | mayusculas}}")
+	require.NoError(t, err)
+	_, _, err = app.Ejecutar(con, "grupo", "listar", "--salida", "csv")
+	require.NoError(t, err)
+	_, _, err = app.Ejecutar(con, "grupo", "listar", "--formato={{.Nombre")
+	assert.Error(t, err)
+
+	escritor.Close()
+	salida, _ := io.ReadAll(lector)
+	assert.Contains(t, string(salida), "uno=1\ndos=2\n")
+	assert.Contains(t, string(salida), "UNO\nDOS\n")
+	assert.Contains(t, string(salida), "Nombre,Cantidad\nuno,1\ndos,2\n")
+}
+
+// TestFormatoDeSalidaEnSesion prueba que el --salida de inicio quede como formato de la sesión y que una línea pueda
+// empezar con opciones de formato
+func TestFormatoDeSalidaEnSesion(t *testing.T) {
+	g := &consola.Grabacion{
+		Encabezado: consola.EncabezadoGrabacion{Version: 2, Ancho: 80, Alto: 24},
+		Eventos: []consola.EventoGrabacion{
+			{Tiempo: 0.1, Tipo: consola.EVENTO_ENTRADA, Datos: "listar\n"},
+			{Tiempo: 0.2, Tipo: consola.EVENTO_ENTRADA, Datos: "--salida csv listar\n"},
+			{Tiempo: 0.3, Tipo: consola.EVENTO_ENTRADA, Datos: "chau\n"},
+		},
+	}
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	leido := make(chan string)
+	go func() {
+		salida, _ := io.ReadAll(lector)
+		leido <- string(salida)
+	}()
+	con, err := consola.NuevaConsolaReproducida(g, escritor, false)
+	require.NoError(t, err)
+
+	app := aplicacion.NuevaAplicacion("app-prueba", "uso de prueba", "Descripción de Prueba", []string{}, con)
+	type item struct {
+		Nombre   string `json:"nombre"`
+		Cantidad int    `json:"cantidad"`
+	}
+	app.RegistrarComando(comando.NuevoComando("listar", "listar", []string{}, "Lista ítems",
+		func(con comando.Consola, opt comando.Opciones, params comando.Parametros, args ...any) (any, comando.CodigoError, error) {
+			return []item{{"uno", 1}}, comando.EXITO, nil
+		}, []string{}))
+	nada := func(aplicacion.Aplicacion, ...string) error { return nil }
+	app.RegistrarInicio(nada).RegistrarLimpieza(nada).RegistrarFinal(nada)
+
+	_, err = app.Correr("--salida", "json")
+	require.NoError(t, err)
+	con.Cerrar()
+	escritor.Close()
+
+	salida := <-leido
+	assert.Contains(t, salida, `"nombre": "uno"`)
+	assert.Contains(t, salida, "nombre,cantidad\nuno,1\n")
+	assert.NotContains(t, salida, "no existe")
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/hernanatn/aplicacion.go/consola"
 	"github.com/hernanatn/aplicacion.go/consola/cadena"
-	"github.com/hernanatn/aplicacion.go/consola/color"
 	"github.com/hernanatn/aplicacion.go/utiles"
 )
 
@@ -42,8 +41,83 @@ type Comando interface {
 	DevolverAliases() []string
 }
 
+// Comandos que pueden presentar su línea de ayuda con el Tema de la Consola en lugar del predeterminado.
+type AyudaConTema interface {
+	TextoAyudaTema(t cadena.Tema) string
+}
+
+// Devuelve la línea de ayuda de c con el Tema t, si c lo admite.
+func TextoAyuda(c Comando, t cadena.Tema) string {
+	if ct, ok := c.(AyudaConTema); ok {
+		return ct.TextoAyudaTema(t)
+	}
+	return c.TextoAyuda()
+}
+
+// Comandos con subcomandos que se pueden buscar por nombre o alias.
+type ConSubcomandos interface {
+	BuscarSubComando(nombre string) (Comando, bool)
+}
+
+// Comandos que pueden listar sus subcomandos.
+type ConListaSubcomandos interface {
+	DevolverSubcomandos() []Comando
+}
+
+// Devuelve los subcomandos de c, o nil si c no los puede listar.
+func Subcomandos(c Comando) []Comando {
+	if cl, ok := c.(ConListaSubcomandos); ok {
+		return cl.DevolverSubcomandos()
+	}
+	return nil
+}
+
+// Devuelve el comando que ejecutaría c.Ejecutar con opciones: el subcomando más profundo que indican, o c.
+func Resolver(c Comando, opciones ...string) Comando {
+	for len(opciones) > 0 {
+		cs, ok := c.(ConSubcomandos)
+		if !ok {
+			break
+		}
+		sc, existe := cs.BuscarSubComando(opciones[0])
+		if !existe {
+			break
+		}
+		c, opciones = sc, opciones[1:]
+	}
+	return c
+}
+
+// Comandos que declaran una plantilla para presentar su resultado en formato texto.
+type ConPlantilla interface {
+	DevolverPlantilla() string
+}
+
+// Devuelve la plantilla de salida declarada por c, o "" si no declara ninguna.
+func Plantilla(c Comando) string {
+	if cp, ok := c.(ConPlantilla); ok {
+		return cp.DevolverPlantilla()
+	}
+	return ""
+}
+
+// Comandos que indican si tienen una Accion propia, además de sus subcomandos.
+type ConAccion interface {
+	TieneAccion() bool
+}
+
+// Devuelve true si c tiene una Accion propia. Si c no lo indica, se asume que sí.
+func TieneAccion(c Comando) bool {
+	if ca, ok := c.(ConAccion); ok {
+		return ca.TieneAccion()
+	}
+	return true
+}
+
 type Config struct {
-	EsOculto bool
+	EsOculto   bool
+	AyudaLarga string // Markdown, se presenta en la ayuda del comando (ver cadena.Markdown)
+	Plantilla  string // text/template para presentar el resultado en formato texto (ver formato.Plantilla)
 }
 type comando struct {
 	Nombre      string
@@ -52,6 +126,8 @@ type comando struct {
 	Descripcion string
 	Opciones    []string
 	Oculto      bool
+	AyudaLarga  string // Markdown
+	Plantilla   string // text/template para el resultado
 
 	accion   Accion
 	comandos []Comando
@@ -59,28 +135,39 @@ type comando struct {
 }
 
 func (c comando) TextoAyuda() string {
+	return c.TextoAyudaTema(cadena.TemaPredeterminado)
+}
+
+func (c comando) TextoAyudaTema(t cadena.Tema) string {
 	nombre := c.Nombre
 	if len(c.Aliases) > 0 {
 		nombre += " (" + strings.Join(c.Aliases, ",") + ") "
 	}
-	return nombre + cadena.TextoJustificado(c.Descripcion, 40, cadena.OpcionesFormato{Sangria: strings.Repeat(" ", 40-len(nombre)-2), Prefijo: strings.Repeat(" ", 40), Color: color.GrisFuente}) + "\n"
+	return nombre + cadena.TextoJustificado(c.Descripcion, 40, cadena.OpcionesFormato{Sangria: strings.Repeat(" ", max(1, 40-cadena.Ancho(nombre)-2)), Prefijo: strings.Repeat(" ", 40), Color: t.Estilo(cadena.ROL_ATENUADO).Secuencia()}) + "\n"
 }
 
 func (c comando) Ayuda(con Consola, args ...string) {
-	con.ImprimirCadena(Cadena(cadena.Titulo(c.Nombre)))
-	con.ImprimirCadena(Cadena(cadena.Subtitulo(c.Descripcion)))
-	con.EscribirLinea(Cadena("Uso:").Subrayada())
+	e := consola.Extender(con)
+	e.IniciarPaginado()
+	defer e.FinalizarPaginado()
+	tema := e.Tema()
+	con.ImprimirCadena(tema.Titulo(c.Nombre))
+	con.ImprimirCadena(tema.Subtitulo(c.Descripcion))
+	con.EscribirLinea(tema.Aplicar(cadena.ROL_SUBTITULO, "Uso:"))
 	con.EscribirLinea(Cadena("\t" + c.Uso))
+	if c.AyudaLarga != "" {
+		con.EscribirLinea(Cadena(cadena.Markdown(c.AyudaLarga, cadena.OpcionesMarkdown{Tema: tema, Salida: con.FSalida()})))
+	}
 	//con.EscribirLinea(Cadena("Ayuda").Negrita().Subrayada())
-	con.EscribirLinea(cadena.CadenaFmt("Subcomandos:").Subrayada())
+	con.EscribirLinea(tema.Aplicar(cadena.ROL_SUBTITULO, "Subcomandos:"))
 
 	for _, c := range c.comandos {
 		if !c.EsOculto() {
-			con.EscribirLinea(Cadena("  " + c.TextoAyuda()))
+			con.EscribirLinea(Cadena("  " + TextoAyuda(c, tema)))
 		}
 	}
 	if len(c.Opciones) > 0 {
-		con.EscribirLinea(Cadena("Opciones Generales:").Subrayada())
+		con.EscribirLinea(tema.Aplicar(cadena.ROL_SUBTITULO, "Opciones Generales:"))
 		for _, o := range c.Opciones {
 			con.EscribirCadena(Cadena("\t" + o))
 		}
@@ -99,7 +186,8 @@ func (c *comando) AsignarPadre(p Comando) {
 	c.padre = p
 }
 
-func (c *comando) buscarSubComando(nombre string) (Comando, bool) {
+// Devuelve el subcomando directo de c con ese nombre o alias.
+func (c *comando) BuscarSubComando(nombre string) (Comando, bool) {
 	for _, c := range c.comandos {
 		if c.DevolverNombre() == nombre || slices.Contains(c.DevolverAliases(), nombre) {
 			return c, true
@@ -139,7 +227,7 @@ func (c *comando) DescifrarOpciones(opciones []string) (Parametros, Opciones, Ar
 
 func (c *comando) Ejecutar(consola Consola, opciones ...string) (res any, cod CodigoError, err error) {
 	if len(opciones) > 0 {
-		sc, existe := c.buscarSubComando(opciones[0])
+		sc, existe := c.BuscarSubComando(opciones[0])
 		if existe {
 			if sc.DevolverNombre() == "ayuda" {
 				c.Ayuda(consola, opciones[1:]...)
@@ -170,6 +258,15 @@ func (c comando) DevolverNombre() string {
 func (c comando) DevolverAliases() []string {
 	return c.Aliases
 }
+func (c comando) DevolverSubcomandos() []Comando {
+	return c.comandos
+}
+func (c comando) DevolverPlantilla() string {
+	return c.Plantilla
+}
+func (c comando) TieneAccion() bool {
+	return c.accion != nil
+}
 
 func NuevoComando(nombre string, uso string, aliases []string, descripcion string, accion Accion, opciones []string, config ...Config) *comando {
 
@@ -188,6 +285,8 @@ func NuevoComando(nombre string, uso string, aliases []string, descripcion strin
 		Descripcion: descripcion,
 		Opciones:    opciones,
 		Oculto:      cfg.EsOculto,
+		AyudaLarga:  cfg.AyudaLarga,
+		Plantilla:   cfg.Plantilla,
 	}
 
 	c.RegistrarComando(
@@ -0,0 +1,119 @@
+package cadena
+
+import (
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola/color"
+	"github.com/rivo/uniseg"
+)
+
+// Recorre s llamando a f con cada secuencia de escape ANSI (ancho 0, esEscape true) y cada grupo de grafemas con su ancho en columnas.
+// Si f devuelve false el recorrido se detiene.
+func recorrer(s string, f func(fragmento string, ancho int, esEscape bool) bool) {
+	escapes := secuenciaANSI.FindAllStringIndex(s, -1)
+	inicio := 0
+	texto := func(t string) bool {
+		estado := -1
+		for t != "" {
+			var grafema string
+			var ancho int
+			grafema, t, ancho, estado = uniseg.FirstGraphemeClusterInString(t, estado)
+			if !f(grafema, ancho, false) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, e := range escapes {
+		if !texto(s[inicio:e[0]]) || !f(s[e[0]:e[1]], 0, true) {
+			return
+		}
+		inicio = e[1]
+	}
+	texto(s[inicio:])
+}
+
+// Ancho de s en columnas de terminal: cuenta grupos de grafemas (acentos combinados, emojis compuestos),
+// caracteres anchos de Asia Oriental como dos columnas e ignora las secuencias de escape ANSI.
+func Ancho(s string) int {
+	return uniseg.StringWidth(SinEstilos(s))
+}
+
+func (c Cadena) Ancho() int {
+	return Ancho(c.S())
+}
+
+// Recorta s para que ocupe como máximo ancho columnas, terminando en final (por ejemplo "…") si hubo recorte.
+// Conserva las secuencias de escape y, si alguna quedó abierta, agrega color.Resetear al final.
+func Truncar(s string, ancho int, final string) string {
+	if Ancho(s) <= ancho {
+		return s
+	}
+	anchoFinal := Ancho(final)
+	if anchoFinal > ancho {
+		final, anchoFinal = "", 0
+	}
+	var sb strings.Builder
+	usado := 0
+	conEstilos := false
+	recorrer(s, func(fragmento string, a int, esEscape bool) bool {
+		if esEscape {
+			sb.WriteString(fragmento)
+			conEstilos = true
+			return true
+		}
+		if usado+a > ancho-anchoFinal {
+			return false
+		}
+		sb.WriteString(fragmento)
+		usado += a
+		return true
+	})
+	sb.WriteString(final)
+	if conEstilos {
+		sb.WriteString(color.Resetear)
+	}
+	return sb.String()
+}
+
+func (c Cadena) Truncar(ancho int, final string) Cadena {
+	return Cadena(Truncar(c.S(), ancho, final))
+}
+
+// Separa s en la columna ancho: cabeza ocupa como máximo ancho columnas y resto contiene lo demás.
+// Las secuencias de escape quedan del lado en el que aparecen.
+func Cortar(s string, ancho int) (cabeza string, resto string) {
+	usado := 0
+	corte := len(s)
+	posicion := 0
+	recorrer(s, func(fragmento string, a int, esEscape bool) bool {
+		if !esEscape && usado+a > ancho {
+			corte = posicion
+			return false
+		}
+		usado += a
+		posicion += len(fragmento)
+		return true
+	})
+	return s[:corte], s[corte:]
+}
+
+// Completa s con espacios hasta ocupar ancho columnas según el alineado (IZQUIERDA, DERECHA o CENTRO).
+// Si s ya ocupa ancho columnas o más, se devuelve sin cambios.
+func Rellenar(s string, ancho int, alineado TipoAlineado) string {
+	falta := ancho - Ancho(s)
+	if falta <= 0 {
+		return s
+	}
+	switch alineado {
+	case DERECHA:
+		return strings.Repeat(" ", falta) + s
+	case CENTRO:
+		return strings.Repeat(" ", falta/2) + s + strings.Repeat(" ", falta-falta/2)
+	}
+	return s + strings.Repeat(" ", falta)
+}
+
+func (c Cadena) Rellenar(ancho int, alineado TipoAlineado) Cadena {
+	return Cadena(Rellenar(c.S(), ancho, alineado))
+}
@@ -3,8 +3,8 @@ package cadena
 import (
 	"bufio"
 	"fmt"
-	"math"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/hernanatn/aplicacion.go/consola/color"
@@ -49,6 +49,8 @@ type OpcionesFormato struct {
 	Sufijo   string
 }
 
+// Ajusta s a líneas de ancho columnas cortando en los límites de palabra, con el alineado, la sangría, el prefijo,
+// el sufijo y el color de las opciones. Ver Parrafo para más control (guionado, ancho automático).
 func TextoJustificado(s string, ancho int, opciones ...OpcionesFormato) string {
 	var o OpcionesFormato
 	if opciones != nil {
@@ -57,41 +59,16 @@ func TextoJustificado(s string, ancho int, opciones ...OpcionesFormato) string {
 	if len(s) == 0 {
 		return ""
 	}
-	if ancho >= len(s) {
-		return Colorear(o.Sangria+s+o.Sufijo, o.Color)
+	if o.Color != "" {
+		s = Colorear(s, o.Color)
 	}
-	var chunks []string = make([]string, 0, (len(s)-1)/ancho+1)
-	largoFila := 0
-	inicioFila := 0
-	for i := range s {
-		if largoFila == ancho {
-			idxPrimerCar := inicioFila
-			idxUltimoCar := i
-			primerCar := string(s[inicioFila])
-			if primerCar == " " {
-				idxPrimerCar++
-				idxUltimoCar++
-			}
-			largoFila = 0
-			inicioFila = idxUltimoCar
-			quiebre := ""
-			if !((string(s[i-1]) == " " && string(s[i]) == " ") || string(s[idxUltimoCar]) == " " || string(s[idxUltimoCar-1]) == " ") {
-				quiebre = "-"
-				idxUltimoCar--
-				largoFila++
-				inicioFila--
-			}
-			chunks = append(chunks, s[idxPrimerCar:idxUltimoCar]+quiebre)
-		}
-		largoFila++
-	}
-	chunks = append(chunks, s[inicioFila:])
-	return strings.ReplaceAll(
-		Colorear(o.Sangria+strings.Join(chunks, fmt.Sprintf("%s\n%s", o.Sufijo, o.Prefijo))+o.Sufijo, o.Color),
-		" -",
-		"  ",
-	)
-	//}
+	return Parrafo(s, OpcionesParrafo{
+		Ancho:    ancho,
+		Alineado: o.Alineado,
+		Sangria:  o.Sangria,
+		Prefijo:  o.Prefijo,
+		Sufijo:   o.Sufijo,
+	})
 }
 
 func Negrita(s string) string {
@@ -137,6 +114,18 @@ func (c Cadena) Colorear(col color.Color) Cadena {
 	return Cadena(Colorear(string(c), col))
 }
 
+// Secuencias de escape ANSI: CSI (colores, estilos, cursor) y OSC (títulos, hipervínculos).
+var secuenciaANSI = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// Elimina todas las secuencias de escape ANSI de s.
+func SinEstilos(s string) string {
+	return secuenciaANSI.ReplaceAllString(s, "")
+}
+
+func (c Cadena) SinEstilos() Cadena {
+	return Cadena(SinEstilos(c.S()))
+}
+
 func (c Cadena) Limpiar() Cadena {
 	return Cadena(strings.TrimSpace(strings.Trim(strings.Trim(c.S(), "\r"), "\n")))
 
@@ -151,65 +140,64 @@ func (c Cadena) Formatear(formatos ...Formateador) Cadena {
 }
 
 func Sugerencia(msg string) string {
-	return Italica(Colorear(fmt.Sprintf("%s.", msg), color.GrisFuente)) + "\n"
-
+	return TemaPredeterminado.Sugerencia(msg)
 }
 func Debug(msg string, err error) string {
-	return Colorear(fmt.Sprintf(Negrita("[DEBUG]")+"\t%s. err: %v.", msg, err), color.GrisFuente) + "\n"
+	return TemaPredeterminado.Debug(msg, err)
 }
 func Ok(msg string) string {
-	return Colorear(fmt.Sprintf("%s.", msg), color.VerdeFuente) + "\n"
+	return TemaPredeterminado.Ok(msg)
 }
 func Exito(msg string) string {
-	return Negrita(Colorear(fmt.Sprintf("%s.", msg), color.VerdeFondo)) + "\n"
+	return TemaPredeterminado.Exito(msg)
 }
 func Advertencia(msg string, err error) string {
-	return Colorear(fmt.Sprintf(Negrita("[ADVERTENCIA]")+"\t%s. err: %v.", msg, err), color.AmarilloFuente) + "\n"
+	return TemaPredeterminado.Advertencia(msg, err)
 }
 func Error(msg string, err error) string {
-	return Colorear(Negrita("[ERROR]"), color.RojoFuente) + Colorear(fmt.Sprintf("\t%s. err: %v.", msg, err), color.RojoFuente) + "\n"
+	return TemaPredeterminado.Error(msg, err)
 }
 func Fatal(msg string, err error) string {
-	return Colorear(fmt.Sprintf(Negrita("[FATAL]")+"\t%s. err: %v.", msg, err), color.RojoFondo) + "\n"
+	return TemaPredeterminado.Fatal(msg, err)
 }
 
 func (c Cadena) Sugerencia() Cadena {
-	return (c.Limpiar() + ".").Colorear(color.GrisFuente).Italica() + "\n"
+	return Cadena(TemaPredeterminado.Sugerencia(c.Limpiar().S()))
 }
 func (c Cadena) Debug(err error) Cadena {
-	return (Cadena("[DEBUG]").Negrita() + CadenaFmt("\t%s. err: %v.", c.Limpiar(), err)).Colorear(color.GrisFuente) + "\n"
+	return Cadena(TemaPredeterminado.Debug(c.Limpiar().S(), err))
 }
 func (c Cadena) Ok() Cadena {
-	return ("✓  " + c.Limpiar() + ".").Colorear(color.VerdeFuente) + "\n"
+	return Cadena(TemaPredeterminado.Ok("✓  " + c.Limpiar().S()))
 }
 func (c Cadena) Exito() Cadena {
-	return ("✓  " + c.Limpiar() + ".").Colorear(color.VerdeFondo).Negrita() + "\n"
+	return Cadena(TemaPredeterminado.Exito("✓  " + c.Limpiar().S()))
 }
 func (c Cadena) Advertencia(err error) Cadena {
-	return (Cadena("⚠  [ADVERTENCIA]").Negrita() + CadenaFmt("\t%s. err: %v.", c.Limpiar(), err).Colorear(color.AmarilloFuente)).Colorear(color.AmarilloFuente) + "\n"
+	return Cadena(TemaPredeterminado.etiquetado(ROL_ADVERTENCIA, ROL_ADVERTENCIA, "⚠  [ADVERTENCIA]", c.Limpiar().S(), err))
 }
 func (c Cadena) Error(err error) Cadena {
-	return (Cadena("✕  [ERROR]").Negrita() + CadenaFmt("\t%s. err: %v.", c.Limpiar(), err).Colorear(color.RojoFuente)).Colorear(color.RojoFuente) + "\n"
+	return Cadena(TemaPredeterminado.etiquetado(ROL_ERROR, ROL_ERROR, "✕  [ERROR]", c.Limpiar().S(), err))
 }
 func (c Cadena) Fatal(err error) Cadena {
-	return Cadena("✕  [FATAL]").Negrita().Colorear(color.RojoFondo) + CadenaFmt("\t%s. err: %v.", c.Limpiar(), err).Colorear(color.RojoFuente) + "\n"
+	return Cadena(TemaPredeterminado.etiquetado(ROL_FATAL, ROL_ERROR, "✕  [FATAL]", c.Limpiar().S(), err))
 }
 
 func ImprimirTitulo(s string) {
-	fmt.Println(Cadena(s).Negrita().Colorear(color.CyanFuente))
+	fmt.Print(TemaPredeterminado.Titulo(s))
 }
 func Titulo(s string) Cadena {
-	return Cadena(s).Negrita().Colorear(color.CyanFuente) + "\n"
+	return TemaPredeterminado.Titulo(s)
 }
 
 func ImprimirSubtitulo(s string) {
-	fmt.Println(Cadena(s).Italica().Colorear(color.GrisFuente))
+	fmt.Print(TemaPredeterminado.Subtitulo(s))
 }
 func Subtitulo(s string) Cadena {
-	return Cadena(s).Italica().Negrita().Colorear(color.GrisFuente) + "\n"
+	return TemaPredeterminado.Subtitulo(s)
 }
 func Señalador(s string) Cadena {
-	return Cadena(s).Colorear(color.CyanFondo) + " "
+	return TemaPredeterminado.Señalador(s)
 }
 
 func (c Cadena) Imprimir(f *bufio.Writer) {
@@ -258,9 +246,6 @@ func DesdeArchivo(nombre string) (Cadena, error) {
 }
 
 func TablaFormateada(encabezados []Cadena, filas [][]Cadena, formato ...OpcionesFormato) Cadena {
-	cantColumnas := float64(len(encabezados))
-	var maxLargos map[int]int = make(map[int]int)
-
 	var formatoF OpcionesFormato
 	var formatoE OpcionesFormato
 	switch {
@@ -272,113 +257,34 @@ func TablaFormateada(encabezados []Cadena, filas [][]Cadena, formato ...Opciones
 		formatoF = formato[0]
 	}
 
-	ec := [][]Cadena{encabezados}
-	for _, fila := range append(ec, filas...) {
-		cantColumnas = math.Max(cantColumnas, float64(len(fila)))
-		for j, columna := range fila {
-			maxLargos[j] = int(math.Max(math.Max(float64(maxLargos[j]), float64(len(columna))), 3))
-		}
-	}
-
-	var salida Cadena = "\n+"
-	for c := 0; c < int(cantColumnas); c++ {
-		salida += Cadena(strings.Repeat("-", maxLargos[c]+2) + "+")
+	e := make([]string, len(encabezados))
+	for i, c := range encabezados {
+		e[i] = c.CadenaAplicarEstilos(formatoE).S()
 	}
-	salida += "\n|"
-
-	for c := 0; c < int(cantColumnas); c++ {
-		f := fmt.Sprintf(" %%-%ds ", maxLargos[c])
-		var v Cadena
-		if c < len(encabezados) {
-
-			v = encabezados[c].CadenaAplicarEstilos(formatoE)
-		} else {
-			v = "N/A"
+	f := make([][]string, len(filas))
+	for i, fila := range filas {
+		f[i] = make([]string, len(fila))
+		for j, c := range fila {
+			f[i][j] = c.CadenaAplicarEstilos(formatoF).S()
 		}
-		salida += Cadena(fmt.Sprintf(f, v) + "|")
-	}
-
-	salida += "\n+"
-	for c := 0; c < int(cantColumnas); c++ {
-		salida += Cadena(strings.Repeat("-", maxLargos[c]+2) + "+")
 	}
-	salida += "\n"
-
-	for _, fila := range filas {
-		salida += "|"
-		for c := 0; c < int(cantColumnas); c++ {
-			f := fmt.Sprintf(" %%-%ds ", maxLargos[c])
-			var v Cadena
-			if c < len(fila) {
-				v = fila[c].CadenaAplicarEstilos(formatoF)
-			} else {
-				v = "N/A"
-			}
-			salida += Cadena(fmt.Sprintf(f, v) + "|")
-		}
-		salida += "\n"
-	}
-	salida += "+"
-	for c := 0; c < int(cantColumnas); c++ {
-		salida += Cadena(strings.Repeat("-", maxLargos[c]+2) + "+")
-	}
-	salida += "\n"
-
-	return salida
+	return Cadena(Tabla(e, f))
 }
-func Tabla(encabezados []string, filas [][]string) string {
-	cantColumnas := float64(len(encabezados))
-	var maxLargos map[int]int = make(map[int]int)
-	ec := [][]string{encabezados}
-	for _, fila := range append(ec, filas...) {
-		cantColumnas = math.Max(cantColumnas, float64(len(fila)))
-		for j, columna := range fila {
-			maxLargos[j] = int(math.Max(math.Max(float64(maxLargos[j]), float64(len(columna))), 3))
-		}
-	}
 
-	var salida string = "\n+"
-	for c := 0; c < int(cantColumnas); c++ {
-		salida += strings.Repeat("-", maxLargos[c]+2) + "+"
-	}
-	salida += "\n|"
-
-	for c := 0; c < int(cantColumnas); c++ {
-		f := fmt.Sprintf(" %%-%ds ", maxLargos[c])
-		var v string
-		if c < len(encabezados) {
-			v = encabezados[c]
-		} else {
-			v = "N/A"
-		}
-		salida += fmt.Sprintf(f, v) + "|"
-	}
-
-	salida += "\n+"
-	for c := 0; c < int(cantColumnas); c++ {
-		salida += strings.Repeat("-", maxLargos[c]+2) + "+"
-	}
-	salida += "\n"
-
-	for _, fila := range filas {
-		salida += "|"
-		for c := 0; c < int(cantColumnas); c++ {
-			f := fmt.Sprintf(" %%-%ds ", maxLargos[c])
-			var v string
-			if c < len(fila) {
-				v = fila[c]
-			} else {
-				v = "N/A"
-			}
-			salida += fmt.Sprintf(f, v) + "|"
+// Dibuja una tabla ASCII. Los anchos se miden en columnas de terminal (ver Ancho), por lo que las celdas
+// pueden contener texto acentuado, emojis, caracteres anchos y secuencias de escape.
+// Para más control sobre alineado, bordes y ajuste ver Cuadro.
+func Tabla(encabezados []string, filas [][]string) string {
+	c := NuevoCuadro().ConBorde(BORDE_ASCII).ConAncho(-1)
+	c.sinEncabezado = false
+	c.Relleno = "N/A"
+	c.Filas = filas
+	for i := 0; i < c.cantidadColumnas() || i < len(encabezados); i++ {
+		titulo := "N/A"
+		if i < len(encabezados) {
+			titulo = encabezados[i]
 		}
-		salida += "\n"
-	}
-	salida += "+"
-	for c := 0; c < int(cantColumnas); c++ {
-		salida += strings.Repeat("-", maxLargos[c]+2) + "+"
+		*c.Columna(i) = Columna{Titulo: titulo, AnchoMinimo: 3}
 	}
-	salida += "\n"
-
-	return salida
+	return "\n" + c.Renderizar()
 }
@@ -0,0 +1,269 @@
+package cadena_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TestDegradar prueba que las secuencias de escape se adapten al nivel de la salida
+func TestDegradar(t *testing.T) {
+	s := cadena.Cadena("hola").Negrita().Colorear(color.GrisFuente).S()
+
+	assert.Equal(t, "hola", cadena.Degradar(s, color.SIN_ESTILOS))
+	assert.Equal(t, "\x1b[1mhola\x1b[0m\x1b[0m", cadena.Degradar(s, color.SIN_COLOR))
+	assert.Equal(t, s, cadena.Degradar(s, color.COLOR_VERDADERO))
+
+	assert.Equal(t, "\x1b[1m!", cadena.Degradar("\x1b[1;31;48;5;200m!", color.SIN_COLOR))
+	assert.Equal(t, "\x1b[F", cadena.Degradar("\x1b[F", color.SIN_COLOR))
+	assert.Equal(t, "", cadena.Degradar("\x1b[F", color.SIN_ESTILOS))
+}
+
+// TestDegradarPaleta prueba la conversión de colores de 24 bits y 256 a paletas reducidas
+func TestDegradarPaleta(t *testing.T) {
+	assert.Equal(t, "\x1b[38;5;196mx", cadena.Degradar("\x1b[38;2;255;0;0mx", color.COLOR_256))
+	assert.Equal(t, "\x1b[91mx", cadena.Degradar("\x1b[38;2;255;0;0mx", color.COLOR_16))
+	assert.Equal(t, "\x1b[1;90mx", cadena.Degradar("\x1b[1;38;2;70;70;70mx", color.COLOR_16))
+	assert.Equal(t, "\x1b[38;5;208mx", cadena.Degradar("\x1b[38;5;208mx", color.COLOR_256))
+	assert.Equal(t, "\x1b[104mx", cadena.Degradar("\x1b[48;5;12mx", color.COLOR_16))
+}
+
+// TestInterpretarTema prueba la carga de un tema con base y estilos propios
+func TestInterpretarTema(t *testing.T) {
+	tema, err := cadena.InterpretarTema([]byte(`
+nombre: propio
+base: monocromo
+estilos:
+  titulo: "negrita #ff0000 fondo:azul"
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "propio", tema.Nombre)
+	assert.Equal(t, cadena.Estilo{Fuente: "\x1b[38;2;255;0;0m", Fondo: color.AzulFondo, Negrita: true}, tema.Estilo(cadena.ROL_TITULO))
+	assert.Equal(t, cadena.TemaMonocromo.Estilo(cadena.ROL_ERROR), tema.Estilo(cadena.ROL_ERROR))
+	assert.Equal(t, "\x1b[7mx\x1b[0m", tema.Aplicar(cadena.ROL_FATAL, "x").S())
+
+	_, err = cadena.InterpretarTema([]byte(`estilos: {titulo: "parpadeante"}`))
+	assert.Error(t, err)
+	_, err = cadena.InterpretarTema([]byte(`estilos: {eror: "rojo"}`))
+	assert.ErrorContains(t, err, `"eror"`)
+}
+
+// TestAncho prueba la medición en columnas de texto acentuado, caracteres anchos, emojis y secuencias de escape
+func TestAncho(t *testing.T) {
+	assert.Equal(t, 7, cadena.Ancho("canción"))
+	assert.Equal(t, 7, cadena.Ancho("canción"))
+	assert.Equal(t, 4, cadena.Ancho("日本"))
+	assert.Equal(t, 2, cadena.Ancho("👍🏽"))
+	assert.Equal(t, 4, cadena.Ancho(cadena.Cadena("hola").Negrita().Colorear(color.RojoFuente).S()))
+
+	assert.Equal(t, "can…", cadena.Truncar("canción", 4, "…"))
+	assert.Equal(t, "日…", cadena.Truncar("日本語", 4, "…"))
+	assert.Equal(t, "\x1b[1mho…\x1b[0m", cadena.Truncar("\x1b[1mhola\x1b[0m", 3, "…"))
+
+	assert.Equal(t, "ñu  ", cadena.Rellenar("ñu", 4, cadena.IZQUIERDA))
+	assert.Equal(t, "  ñu", cadena.Rellenar("ñu", 4, cadena.DERECHA))
+	assert.Equal(t, " ñu ", cadena.Rellenar("ñu", 4, cadena.CENTRO))
+}
+
+// TestTablaUnicode prueba que las columnas queden alineadas con celdas acentuadas y con estilos
+func TestTablaUnicode(t *testing.T) {
+	tabla := cadena.Tabla([]string{"Nombre", "Estado"}, [][]string{
+		{"Año", cadena.Cadena("✓").Colorear(color.VerdeFuente).S()},
+		{"日本", "✕"},
+	})
+	lineas := strings.Split(strings.Trim(tabla, "\n"), "\n")
+	for _, l := range lineas {
+		assert.Equal(t, cadena.Ancho(lineas[0]), cadena.Ancho(l), l)
+	}
+}
+
+// TestParrafo prueba el ajuste en límites de palabra, la justificación, el guionado y la continuidad de estilos
+func TestParrafo(t *testing.T) {
+	s := "La cigüeña tocaba el saxofón detrás del palenque de paja"
+
+	assert.Equal(t, []string{"La cigüeña tocaba el", "saxofón detrás del", "palenque de paja"}, cadena.Envolver(s, 20))
+	assert.Equal(t,
+		"* La cigüeña tocaba el\n  saxofón  detrás  del\n  palenque de paja",
+		cadena.Parrafo(s, cadena.OpcionesParrafo{Ancho: 20, Alineado: cadena.JUSTIFICADO, Sangria: "* ", Prefijo: "  "}),
+	)
+	assert.Equal(t, []string{"   hola", "  mundo"}, cadena.LineasParrafo("hola mundo", cadena.OpcionesParrafo{Ancho: 7, Alineado: cadena.DERECHA}))
+	assert.Equal(t, []string{"extraordi-", "nariamente"}, cadena.LineasParrafo("extraordinariamente", cadena.OpcionesParrafo{Ancho: 10, Guionado: true}))
+	assert.Equal(t, []string{"un extra-", "ordinario"}, cadena.LineasParrafo("un extraordinario", cadena.OpcionesParrafo{Ancho: 9, Guionado: true}))
+	assert.Equal(t,
+		[]string{"\x1b[1muno dos\x1b[0m", "\x1b[1mtres\x1b[0m"},
+		cadena.Envolver("\x1b[1muno dos tres\x1b[0m", 7),
+	)
+}
+
+// TestCuadro prueba bordes, alineado, ajuste y ocultamiento de columnas al limitar el ancho
+func TestCuadro(t *testing.T) {
+	nuevo := func() *cadena.Cuadro {
+		c := cadena.NuevoCuadro("Nombre", "Estado", "Descripción").ConAncho(-1)
+		c.Columna(1).Alineado = cadena.CENTRO
+		c.Columna(2).Prioridad = -1
+		return c.AgregarFila("api", "✓", "Servicio principal").AgregarPie("Total", "1")
+	}
+
+	assert.Equal(t, ""+
+		"┌────────┬────────┬────────────────────┐\n"+
+		"│ Nombre │ Estado │ Descripción        │\n"+
+		"├────────┼────────┼────────────────────┤\n"+
+		"│ api    │   ✓    │ Servicio principal │\n"+
+		"├────────┼────────┼────────────────────┤\n"+
+		"│ Total  │   1    │                    │\n"+
+		"└────────┴────────┴────────────────────┘\n", nuevo().Renderizar())
+
+	assert.Equal(t, ""+
+		"| Nombre | Estado | Descripción        |\n"+
+		"|--------|:------:|--------------------|\n"+
+		"| api    |   ✓    | Servicio principal |\n"+
+		"| Total  |   1    |                    |\n", nuevo().ConBorde(cadena.BORDE_MARKDOWN).Renderizar())
+
+	angosto := nuevo().ConAncho(30)
+	assert.NotContains(t, angosto.Renderizar(), "Descripción")
+	for _, l := range strings.Split(strings.TrimSuffix(angosto.Renderizar(), "\n"), "\n") {
+		assert.LessOrEqual(t, cadena.Ancho(l), 30, l)
+	}
+
+	ajustado := nuevo().ConAncho(-1)
+	ajustado.Columna(2).AnchoMaximo = 10
+	assert.Contains(t, ajustado.Renderizar(), "│ api    │   ✓    │ Servicio   │\n│        │        │ principal  │\n")
+	ajustado.Columna(2).Ajuste = cadena.TRUNCAR
+	assert.Contains(t, ajustado.Renderizar(), "│ Servicio … │")
+
+	assert.Equal(t, ""+
+		"┌─────┬───┐\n"+
+		"│ api │ ✓ │\n"+
+		"└─────┴───┘\n", cadena.NuevoCuadro("Nombre", "Estado").ConEncabezado(false).ConAncho(-1).AgregarFila("api", "✓").Renderizar())
+
+	// El estilo de la fila se vuelve a activar después del restablecimiento de una celda con estilo propio.
+	rayado := cadena.NuevoCuadro().ConTema(cadena.TemaPredeterminado, true).ConAncho(-1).
+		AgregarFila("a").AgregarFila(cadena.Cadena("b").Negrita().S() + "c")
+	alterna := cadena.TemaPredeterminado.Estilo(cadena.ROL_FILA_ALTERNA).Secuencia()
+	require.NotEmpty(t, alterna)
+	assert.Contains(t, rayado.Renderizar(), color.Resetear+alterna+"c")
+}
+
+// TestDatosTabla prueba la exportación de datos tabulares a los distintos formatos
+func TestDatosTabla(t *testing.T) {
+	datos := cadena.NuevosDatosTabla("nombre", "nota").
+		AgregarFila(cadena.Cadena("Ana").Negrita().S(), "<b>10</b>").
+		AgregarFila("Luis, hijo")
+
+	var sb strings.Builder
+	assert.NoError(t, datos.EscribirCSV(&sb))
+	assert.Equal(t, "nombre,nota\nAna,<b>10</b>\n\"Luis, hijo\",\n", sb.String())
+
+	sb.Reset()
+	assert.NoError(t, datos.EscribirTSV(&sb))
+	assert.Equal(t, "nombre\tnota\nAna\t<b>10</b>\nLuis, hijo\t\n", sb.String())
+
+	sb.Reset()
+	assert.NoError(t, datos.EscribirMarkdown(&sb))
+	assert.Equal(t, "| nombre     | nota      |\n|------------|-----------|\n| Ana        | <b>10</b> |\n| Luis, hijo |           |\n", sb.String())
+
+	sb.Reset()
+	assert.NoError(t, datos.EscribirHTML(&sb))
+	assert.Contains(t, sb.String(), "<tr><td>Ana</td><td>&lt;b&gt;10&lt;/b&gt;</td></tr>")
+
+	sb.Reset()
+	assert.NoError(t, datos.EscribirJSON(&sb))
+	assert.JSONEq(t, `[{"nombre":"Ana","nota":"<b>10</b>"},{"nombre":"Luis, hijo","nota":""}]`, sb.String())
+
+	// Las celdas sin encabezado no se descartan.
+	datos.AgregarFila("Eva", "9", "recuperó")
+	b, err := json.Marshal(datos)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `{"nombre":"Eva","nota":"9","columna3":"recuperó"}`)
+	y, err := yaml.Marshal(datos)
+	assert.NoError(t, err)
+	assert.Contains(t, string(y), "- nombre: Eva\n  nota: \"9\"\n  columna3: recuperó\n")
+
+	sb.Reset()
+	assert.NoError(t, datos.EscribirCSV(&sb))
+	assert.Equal(t, "nombre,nota,columna3\nAna,<b>10</b>,\n\"Luis, hijo\",,\nEva,9,recuperó\n", sb.String())
+
+	// Las claves repetidas o que coinciden con las generadas se distinguen con un sufijo.
+	repetidos := cadena.NuevosDatosTabla("a", "a", "columna4").AgregarFila("1", "2", "3", "4", "5")
+	b, err = json.Marshal(repetidos)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"a":"1","a_2":"2","columna4":"3","columna4_2":"4","columna5":"5"}]`, string(b))
+	y, err = yaml.Marshal(repetidos)
+	assert.NoError(t, err)
+	var leidos []map[string]string
+	assert.NoError(t, yaml.Unmarshal(y, &leidos))
+	assert.Len(t, leidos[0], 5)
+}
+
+// TestMarkdown prueba la presentación de los bloques y estilos en línea de Markdown
+func TestMarkdown(t *testing.T) {
+	o := cadena.OpcionesMarkdown{Ancho: 30, Tema: cadena.TemaMonocromo, SinHipervinculos: true}
+	fuente := "# Título\n\nTexto con **negrita** y un [enlace](https://ejemplo.com).\n\n" +
+		"- uno\n- dos\n  1. anidado\n\n> cita\n\n```go\nreturn nil\n```\n\n| a | b |\n|---|--:|\n| x | 10 |\n"
+
+	assert.Equal(t, ""+
+		"Título\n"+
+		"\n"+
+		"Texto con negrita y un enlace\n"+
+		"(https://ejemplo.com).\n"+
+		"\n"+
+		"• uno\n"+
+		"• dos\n"+
+		"  1. anidado\n"+
+		"\n"+
+		"│ cita\n"+
+		"\n"+
+		"  return nil\n"+
+		"\n"+
+		"╭───┬────╮\n"+
+		"│ a │  b │\n"+
+		"├───┼────┤\n"+
+		"│ x │ 10 │\n"+
+		"╰───┴────╯\n", cadena.SinEstilos(cadena.Markdown(fuente, o)))
+
+	assert.Equal(t, "a \x1b[1mb \x1b[3mc\x1b[0m\x1b[1m\x1b[0m\n", cadena.Markdown("a **b *c***", o))
+	assert.Equal(t, "snake_case_var\n", cadena.Markdown("snake_case_var", o))
+	// Sin "|" en la línea de guiones no es una tabla sino un título subrayado.
+	assert.Equal(t, "Use foo | bar\n", cadena.SinEstilos(cadena.Markdown("Use foo | bar\n---\n", o)))
+
+	o.SinHipervinculos = false
+	t.Setenv("FORCE_HYPERLINK", "1")
+	assert.Contains(t, cadena.Markdown("[x](https://e.com)", o), "\x1b]8;;https://e.com\x1b\\")
+
+	// Sin FORCE_HYPERLINK, una Salida que no es una terminal no recibe hipervínculos ni define el ancho.
+	t.Setenv("FORCE_HYPERLINK", "")
+	t.Setenv("COLUMNS", "57")
+	archivo, err := os.CreateTemp(t.TempDir(), "salida")
+	require.NoError(t, err)
+	defer archivo.Close()
+	o.Salida = archivo
+	assert.NotContains(t, cadena.Markdown("[x](https://e.com)", o), "\x1b]8;;")
+	assert.Equal(t, 57, cadena.AnchoTerminalDe(archivo))
+
+	resaltado := cadena.Resaltar("x := 1 // uno", "go", cadena.TemaOscuro)
+	assert.Contains(t, resaltado[0], cadena.TemaOscuro.Aplicar(cadena.ROL_COMENTARIO, "// uno").S())
+}
+
+// TestPlantilla prueba las funciones de estilo, texto y datos disponibles en las plantillas de salida
+func TestPlantilla(t *testing.T) {
+	var sb strings.Builder
+	datos := map[string]any{"Nombre": "api", "Etiquetas": []string{"a", "b"}}
+	err := cadena.EjecutarPlantilla(&sb, `{{.Nombre | negrita}} {{.Nombre | error}} [{{unir "," .Etiquetas}}] {{.Nombre | derecha 5}}|`, datos, cadena.TemaOscuro)
+	assert.NoError(t, err)
+	assert.Equal(t, cadena.Negrita("api")+" "+cadena.TemaOscuro.Aplicar(cadena.ROL_ERROR, "api").S()+" [a,b]   api|", sb.String())
+
+	sb.Reset()
+	err = cadena.EjecutarPlantilla(&sb, `{{tabla .}}`, *cadena.NuevosDatosTabla("x").AgregarFila("1"), cadena.TemaOscuro)
+	assert.NoError(t, err)
+	assert.Contains(t, cadena.SinEstilos(sb.String()), "│ 1 │")
+
+	assert.Error(t, cadena.EjecutarPlantilla(&sb, `{{.Nombre | colorear "violetaz"}}`, datos, cadena.TemaOscuro))
+	assert.ErrorContains(t, cadena.EjecutarPlantilla(&sb, `{{.Nombre | rol "eror"}}`, datos, cadena.TemaOscuro), `"eror"`)
+}
@@ -0,0 +1,366 @@
+package cadena
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola/color"
+)
+
+// Caracteres con los que se dibujan los bordes de un Cuadro. Superior, Medio e Inferior son
+// {esquina izquierda, cruce, esquina derecha} de cada línea horizontal.
+type EstiloBorde struct {
+	Horizontal string
+	Vertical   string
+	Superior   [3]string
+	Medio      [3]string
+	Inferior   [3]string
+	Markdown   bool // Tabla de GitHub Markdown: sin líneas superior e inferior y con alineado en la línea del encabezado
+}
+
+var (
+	BORDE_ASCII      = EstiloBorde{"-", "|", [3]string{"+", "+", "+"}, [3]string{"+", "+", "+"}, [3]string{"+", "+", "+"}, false}
+	BORDE_LIGERO     = EstiloBorde{"─", "│", [3]string{"┌", "┬", "┐"}, [3]string{"├", "┼", "┤"}, [3]string{"└", "┴", "┘"}, false}
+	BORDE_GRUESO     = EstiloBorde{"━", "┃", [3]string{"┏", "┳", "┓"}, [3]string{"┣", "╋", "┫"}, [3]string{"┗", "┻", "┛"}, false}
+	BORDE_DOBLE      = EstiloBorde{"═", "║", [3]string{"╔", "╦", "╗"}, [3]string{"╠", "╬", "╣"}, [3]string{"╚", "╩", "╝"}, false}
+	BORDE_REDONDEADO = EstiloBorde{"─", "│", [3]string{"╭", "┬", "╮"}, [3]string{"├", "┼", "┤"}, [3]string{"╰", "┴", "╯"}, false}
+	BORDE_MARKDOWN   = EstiloBorde{"-", "|", [3]string{}, [3]string{"|", "|", "|"}, [3]string{}, true}
+	BORDE_NINGUNO    = EstiloBorde{}
+)
+
+// Ancho al que se puede reducir una columna sin AnchoMinimo antes de ocultar columnas.
+const ANCHO_REDUCIDO = 10
+
+// Cómo se presenta el contenido de una celda más ancho que su columna.
+type TipoAjuste int
+
+const (
+	AJUSTAR TipoAjuste = iota // Se distribuye en varias líneas (ver Parrafo)
+	TRUNCAR                   // Se recorta con "…"
+)
+
+type Columna struct {
+	Titulo      string
+	Alineado    TipoAlineado
+	AnchoMinimo int
+	AnchoMaximo int // 0: sin límite
+	Ajuste      TipoAjuste
+	Prioridad   int // Si el cuadro no entra en el ancho disponible, se ocultan primero las columnas de menor prioridad
+}
+
+// Cuadro es un constructor de tablas para la terminal.
+//
+// # Ejemplo:
+//
+//	cuadro := cadena.NuevoCuadro("Nombre", "Estado", "Descripción").
+//		ConBorde(cadena.BORDE_REDONDEADO).
+//		ConTema(con.Tema(), true)
+//	cuadro.Columna(1).Alineado = cadena.CENTRO
+//	cuadro.Columna(2).Prioridad = -1
+//	cuadro.AgregarFila("api", "✓", "Servicio principal")
+//	con.ImprimirCadena(cuadro.Cadena())
+type Cuadro struct {
+	Columnas []Columna
+	Filas    [][]string
+	Pies     [][]string
+	Borde    EstiloBorde
+	Tema     Tema
+	Rayado   bool   // Alterna el estilo ROL_FILA_ALTERNA del Tema en las filas
+	Relleno  string // Contenido de las celdas faltantes
+	Ancho    int    // Ancho máximo total: 0 usa el de la terminal, negativo no limita
+	Margen   int    // Espacios a cada lado del contenido de la celda
+
+	sinEncabezado bool
+}
+
+func NuevoCuadro(titulos ...string) *Cuadro {
+	c := &Cuadro{Borde: BORDE_LIGERO, Margen: 1}
+	for _, t := range titulos {
+		c.Columnas = append(c.Columnas, Columna{Titulo: t})
+	}
+	c.sinEncabezado = len(titulos) == 0
+	return c
+}
+
+func (c *Cuadro) AgregarFila(celdas ...string) *Cuadro {
+	c.Filas = append(c.Filas, celdas)
+	return c
+}
+
+func (c *Cuadro) AgregarPie(celdas ...string) *Cuadro {
+	c.Pies = append(c.Pies, celdas)
+	return c
+}
+
+func (c *Cuadro) ConBorde(b EstiloBorde) *Cuadro {
+	c.Borde = b
+	return c
+}
+
+// Usa los roles ROL_ENCABEZADO, ROL_PIE y, si rayado es true, ROL_FILA_ALTERNA del tema.
+func (c *Cuadro) ConTema(t Tema, rayado bool) *Cuadro {
+	c.Tema = t
+	c.Rayado = rayado
+	return c
+}
+
+func (c *Cuadro) ConAncho(ancho int) *Cuadro {
+	c.Ancho = ancho
+	return c
+}
+
+// Indica si se dibuja la fila de títulos. Por defecto se dibuja si el cuadro se creó con títulos.
+func (c *Cuadro) ConEncabezado(mostrar bool) *Cuadro {
+	c.sinEncabezado = !mostrar
+	return c
+}
+
+// Devuelve la configuración de la columna i, creándola si hace falta.
+func (c *Cuadro) Columna(i int) *Columna {
+	for len(c.Columnas) <= i {
+		c.Columnas = append(c.Columnas, Columna{})
+	}
+	return &c.Columnas[i]
+}
+
+func (c *Cuadro) cantidadColumnas() int {
+	n := len(c.Columnas)
+	for _, f := range slices.Concat(c.Filas, c.Pies) {
+		n = max(n, len(f))
+	}
+	return n
+}
+
+func (c *Cuadro) columna(i int) Columna {
+	if i < len(c.Columnas) {
+		return c.Columnas[i]
+	}
+	return Columna{}
+}
+
+func (c *Cuadro) celda(fila []string, i int) string {
+	if i < len(fila) {
+		return fila[i]
+	}
+	return c.Relleno
+}
+
+// Ancho natural de la columna i: el de su línea más ancha, acotado por AnchoMinimo y AnchoMaximo.
+func (c *Cuadro) anchoNatural(i int) int {
+	col := c.columna(i)
+	ancho := 0
+	medir := func(s string) {
+		for _, l := range strings.Split(s, "\n") {
+			ancho = max(ancho, Ancho(l))
+		}
+	}
+	if !c.sinEncabezado {
+		medir(col.Titulo)
+	}
+	for _, f := range slices.Concat(c.Filas, c.Pies) {
+		medir(c.celda(f, i))
+	}
+	if col.AnchoMaximo > 0 {
+		ancho = min(ancho, col.AnchoMaximo)
+	}
+	return max(ancho, col.AnchoMinimo, 1)
+}
+
+// Ancho mínimo al que se puede reducir la columna i para entrar en el ancho disponible.
+func (c *Cuadro) anchoReducido(i int, natural int) int {
+	col := c.columna(i)
+	if col.AnchoMinimo > 0 {
+		return min(natural, col.AnchoMinimo)
+	}
+	return min(natural, ANCHO_REDUCIDO)
+}
+
+// Ancho que ocupan bordes y márgenes para n columnas visibles.
+func (c *Cuadro) anchoFijo(n int) int {
+	fijo := 2 * c.Margen * n
+	if c.Borde.Vertical != "" {
+		fijo += Ancho(c.Borde.Vertical) * (n + 1)
+	}
+	return fijo
+}
+
+// Determina qué columnas se muestran y con qué ancho.
+func (c *Cuadro) distribuir() (visibles []int, anchos []int) {
+	n := c.cantidadColumnas()
+	for i := 0; i < n; i++ {
+		visibles = append(visibles, i)
+	}
+	limite := c.Ancho
+	if limite == 0 {
+		limite = AnchoTerminal()
+	}
+	for {
+		anchos = make([]int, len(visibles))
+		total := c.anchoFijo(len(visibles))
+		for j, i := range visibles {
+			anchos[j] = c.anchoNatural(i)
+			total += anchos[j]
+		}
+		if limite < 0 || c.Borde.Markdown || total <= limite {
+			return visibles, anchos
+		}
+		// Se reduce de a una columna la más ancha que todavía admita reducción.
+		for total > limite {
+			mayor := -1
+			for j, i := range visibles {
+				if anchos[j] > c.anchoReducido(i, c.anchoNatural(i)) && (mayor < 0 || anchos[j] > anchos[mayor]) {
+					mayor = j
+				}
+			}
+			if mayor < 0 {
+				break
+			}
+			anchos[mayor]--
+			total--
+		}
+		if total <= limite || len(visibles) == 1 {
+			return visibles, anchos
+		}
+		// Se oculta la columna de menor prioridad (ante empate, la de más a la derecha).
+		ocultar := len(visibles) - 1
+		for j := len(visibles) - 1; j >= 0; j-- {
+			if c.columna(visibles[j]).Prioridad < c.columna(visibles[ocultar]).Prioridad {
+				ocultar = j
+			}
+		}
+		visibles = slices.Delete(visibles, ocultar, ocultar+1)
+	}
+}
+
+// Distribuye el contenido de una celda en líneas de ancho columnas según el ajuste de la columna.
+func (c *Cuadro) lineasCelda(s string, col Columna, ancho int) []string {
+	var lineas []string
+	for _, l := range strings.Split(s, "\n") {
+		if c.Borde.Markdown {
+			l = strings.ReplaceAll(l, "|", "\\|")
+		}
+		switch {
+		case Ancho(l) <= ancho || c.Borde.Markdown:
+			lineas = append(lineas, l)
+		case col.Ajuste == TRUNCAR:
+			lineas = append(lineas, Truncar(l, ancho, "…"))
+		default:
+			lineas = append(lineas, LineasParrafo(l, OpcionesParrafo{Ancho: ancho})...)
+		}
+	}
+	if c.Borde.Markdown {
+		return []string{strings.Join(lineas, " ")}
+	}
+	return lineas
+}
+
+func (c *Cuadro) lineaHorizontal(partes [3]string, anchos []int, columnas []int, esEncabezado bool) string {
+	if c.Borde.Horizontal == "" {
+		return ""
+	}
+	segmentos := make([]string, len(anchos))
+	for j, a := range anchos {
+		largo := a + 2*c.Margen
+		if c.Borde.Markdown && esEncabezado {
+			switch c.columna(columnas[j]).Alineado {
+			case DERECHA:
+				segmentos[j] = strings.Repeat("-", largo-1) + ":"
+				continue
+			case CENTRO:
+				segmentos[j] = ":" + strings.Repeat("-", max(1, largo-2)) + ":"
+				continue
+			}
+		}
+		segmentos[j] = strings.Repeat(c.Borde.Horizontal, largo)
+	}
+	return partes[0] + strings.Join(segmentos, partes[1]) + partes[2] + "\n"
+}
+
+func (c *Cuadro) lineasFila(celdas []string, visibles []int, anchos []int, estilo Estilo) string {
+	contenido := make([][]string, len(visibles))
+	alto := 1
+	for j, i := range visibles {
+		contenido[j] = c.lineasCelda(celdas[j], c.columna(i), anchos[j])
+		alto = max(alto, len(contenido[j]))
+	}
+	margen := strings.Repeat(" ", c.Margen)
+	var sb strings.Builder
+	for l := 0; l < alto; l++ {
+		sb.WriteString(c.Borde.Vertical)
+		for j, i := range visibles {
+			texto := ""
+			if l < len(contenido[j]) {
+				texto = contenido[j][l]
+			}
+			sb.WriteString(aplicarEnCelda(estilo, margen+Rellenar(texto, anchos[j], c.columna(i).Alineado)+margen))
+			sb.WriteString(c.Borde.Vertical)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Aplica estilo a s y lo vuelve a activar después de cada restablecimiento de s, para que los estilos propios de la
+// celda no corten el de la fila.
+func aplicarEnCelda(estilo Estilo, s string) string {
+	seq := estilo.Secuencia()
+	if seq == "" {
+		return s
+	}
+	return seq + strings.ReplaceAll(s, color.Resetear, color.Resetear+seq) + color.Resetear
+}
+
+// Devuelve el cuadro dibujado, terminado en "\n".
+func (c *Cuadro) Renderizar() string {
+	visibles, anchos := c.distribuir()
+	if len(visibles) == 0 {
+		return ""
+	}
+	seleccionar := func(fila []string) []string {
+		celdas := make([]string, len(visibles))
+		for j, i := range visibles {
+			celdas[j] = c.celda(fila, i)
+		}
+		return celdas
+	}
+
+	var sb strings.Builder
+	if !c.Borde.Markdown {
+		sb.WriteString(c.lineaHorizontal(c.Borde.Superior, anchos, visibles, false))
+	}
+	if !c.sinEncabezado {
+		titulos := make([]string, len(visibles))
+		for j, i := range visibles {
+			titulos[j] = c.columna(i).Titulo
+		}
+		sb.WriteString(c.lineasFila(titulos, visibles, anchos, c.Tema.Estilo(ROL_ENCABEZADO)))
+		sb.WriteString(c.lineaHorizontal(c.Borde.Medio, anchos, visibles, true))
+	}
+	for k, f := range c.Filas {
+		var estilo Estilo
+		if c.Rayado && k%2 == 1 {
+			estilo = c.Tema.Estilo(ROL_FILA_ALTERNA)
+		}
+		sb.WriteString(c.lineasFila(seleccionar(f), visibles, anchos, estilo))
+	}
+	if len(c.Pies) > 0 {
+		if !c.Borde.Markdown {
+			sb.WriteString(c.lineaHorizontal(c.Borde.Medio, anchos, visibles, false))
+		}
+		for _, f := range c.Pies {
+			sb.WriteString(c.lineasFila(seleccionar(f), visibles, anchos, c.Tema.Estilo(ROL_PIE)))
+		}
+	}
+	if !c.Borde.Markdown {
+		sb.WriteString(c.lineaHorizontal(c.Borde.Inferior, anchos, visibles, false))
+	}
+	return sb.String()
+}
+
+func (c *Cuadro) String() string {
+	return c.Renderizar()
+}
+
+func (c *Cuadro) Cadena() Cadena {
+	return Cadena(c.Renderizar())
+}
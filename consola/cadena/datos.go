@@ -0,0 +1,241 @@
+package cadena
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tabulable es implementado por los valores que saben presentarse como encabezados y filas.
+// Los codificadores de tablas del paquete formato (tabla, csv, tsv, markdown, html) lo consultan antes de recurrir a reflexión.
+type Tabulable interface {
+	Tabular() ([]string, [][]string)
+}
+
+// DatosTabla son encabezados y filas, independientes de su presentación.
+//
+// # Ejemplo:
+//
+//	datos := cadena.NuevosDatosTabla("Nombre", "Versión").
+//		AgregarFila("api", "1.2.0").
+//		AgregarFila("web", "0.9.1")
+//	con.ImprimirCadena(datos.Cuadro().Cadena())
+//	datos.EscribirMarkdown(archivo)
+//
+// Al ser Tabulable, un Comando puede devolver DatosTabla como resultado y se presenta según la opción --salida.
+type DatosTabla struct {
+	Encabezados []string
+	Filas       [][]string
+}
+
+func NuevosDatosTabla(encabezados ...string) *DatosTabla {
+	return &DatosTabla{Encabezados: encabezados}
+}
+
+func (d *DatosTabla) AgregarFila(celdas ...string) *DatosTabla {
+	d.Filas = append(d.Filas, celdas)
+	return d
+}
+
+func (d DatosTabla) Tabular() ([]string, [][]string) {
+	return d.Encabezados, d.Filas
+}
+
+// Devuelve la fila i con tantas celdas como encabezados y sin secuencias de escape.
+func (d DatosTabla) fila(i int) []string {
+	celdas := make([]string, max(len(d.Encabezados), len(d.Filas[i])))
+	for j, c := range d.Filas[i] {
+		celdas[j] = SinEstilos(c)
+	}
+	return celdas
+}
+
+func (d DatosTabla) encabezados() []string {
+	e := make([]string, len(d.Encabezados))
+	for i, c := range d.Encabezados {
+		e[i] = SinEstilos(c)
+	}
+	return e
+}
+
+// Devuelve la cantidad de columnas: la de los encabezados o la de la fila más larga.
+func (d DatosTabla) columnas() int {
+	n := len(d.Encabezados)
+	for _, f := range d.Filas {
+		n = max(n, len(f))
+	}
+	return n
+}
+
+// Devuelve claves distintas para n celdas: los encabezados y, para las celdas que no tienen, "columna<n>" (contando
+// desde 1). Los encabezados repetidos y las claves generadas que coinciden con un encabezado llevan el sufijo _2, _3...
+func (d DatosTabla) claves(n int) []string {
+	encabezados := d.encabezados()
+	ocupadas := map[string]bool{}
+	for _, e := range encabezados {
+		ocupadas[e] = true
+	}
+	usadas := map[string]bool{}
+	unica := func(base string) string {
+		if !ocupadas[base] && !usadas[base] {
+			return base
+		}
+		for k := 2; ; k++ {
+			if c := fmt.Sprintf("%s_%d", base, k); !ocupadas[c] && !usadas[c] {
+				return c
+			}
+		}
+	}
+
+	claves := make([]string, max(n, len(encabezados)))
+	for j := range claves {
+		switch {
+		case j < len(encabezados) && !usadas[encabezados[j]]:
+			claves[j] = encabezados[j]
+		case j < len(encabezados):
+			claves[j] = unica(encabezados[j])
+		default:
+			claves[j] = unica(fmt.Sprintf("columna%d", j+1))
+		}
+		usadas[claves[j]] = true
+	}
+	return claves
+}
+
+// Devuelve un Cuadro con los datos, para ajustar su presentación en la terminal.
+func (d DatosTabla) Cuadro() *Cuadro {
+	c := NuevoCuadro(d.Encabezados...)
+	c.Filas = d.Filas
+	return c
+}
+
+func (d DatosTabla) String() string {
+	return d.Cuadro().Renderizar()
+}
+
+func (d DatosTabla) escribirSeparado(w io.Writer, separador rune) error {
+	e := csv.NewWriter(w)
+	e.Comma = separador
+	// Todas las líneas con la misma cantidad de campos: las columnas sin encabezado se nombran como en MarshalJSON.
+	columnas := d.columnas()
+	if err := e.Write(append(d.encabezados(), d.claves(columnas)[len(d.Encabezados):]...)); err != nil {
+		return err
+	}
+	for i := range d.Filas {
+		fila := d.fila(i)
+		if err := e.Write(append(fila, make([]string, columnas-len(fila))...)); err != nil {
+			return err
+		}
+	}
+	e.Flush()
+	return e.Error()
+}
+
+// Escribe los datos como CSV (RFC 4180), con los encabezados en la primera línea.
+func (d DatosTabla) EscribirCSV(w io.Writer) error {
+	return d.escribirSeparado(w, ',')
+}
+
+// Escribe los datos separados por tabulaciones.
+func (d DatosTabla) EscribirTSV(w io.Writer) error {
+	return d.escribirSeparado(w, '\t')
+}
+
+// Escribe los datos como tabla de GitHub Markdown.
+func (d DatosTabla) EscribirMarkdown(w io.Writer) error {
+	c := NuevoCuadro(d.encabezados()...).ConBorde(BORDE_MARKDOWN).ConAncho(-1)
+	for i := range d.Filas {
+		c.AgregarFila(d.fila(i)...)
+	}
+	_, err := io.WriteString(w, c.Renderizar())
+	return err
+}
+
+// Escribe los datos como un elemento <table> de HTML.
+func (d DatosTabla) EscribirHTML(w io.Writer) error {
+	var sb strings.Builder
+	celdas := func(etiqueta string, valores []string) {
+		sb.WriteString("    <tr>")
+		for _, v := range valores {
+			sb.WriteString("<" + etiqueta + ">" + strings.ReplaceAll(html.EscapeString(v), "\n", "<br>") + "</" + etiqueta + ">")
+		}
+		sb.WriteString("</tr>\n")
+	}
+	sb.WriteString("<table>\n  <thead>\n")
+	celdas("th", d.encabezados())
+	sb.WriteString("  </thead>\n  <tbody>\n")
+	for i := range d.Filas {
+		celdas("td", d.fila(i))
+	}
+	sb.WriteString("  </tbody>\n</table>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Escribe los datos como un arreglo JSON de objetos, uno por fila, con las claves en el orden de los encabezados
+// (ver MarshalJSON).
+func (d DatosTabla) EscribirJSON(w io.Writer) error {
+	b, err := d.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	var sb bytes.Buffer
+	if err := json.Indent(&sb, b, "", "  "); err != nil {
+		return err
+	}
+	sb.WriteByte('\n')
+	_, err = sb.WriteTo(w)
+	return err
+}
+
+// Presenta los datos como un arreglo de objetos, uno por fila. Las celdas que exceden los encabezados se presentan con
+// las claves columna<n>.
+func (d DatosTabla) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i := range d.Filas {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('{')
+		fila := d.fila(i)
+		claves := d.claves(len(fila))
+		for j, v := range fila {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			clave, _ := json.Marshal(claves[j])
+			valor, _ := json.Marshal(v)
+			b.Write(clave)
+			b.WriteByte(':')
+			b.Write(valor)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(']')
+	return b.Bytes(), nil
+}
+
+// Presenta los datos en YAML como una secuencia de mapas, con las claves en el orden de los encabezados (como MarshalJSON).
+func (d DatosTabla) MarshalYAML() (any, error) {
+	secuencia := &yaml.Node{Kind: yaml.SequenceNode}
+	for i := range d.Filas {
+		mapa := &yaml.Node{Kind: yaml.MappingNode}
+		fila := d.fila(i)
+		claves := d.claves(len(fila))
+		for j, v := range fila {
+			mapa.Content = append(mapa.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: claves[j]},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v},
+			)
+		}
+		secuencia.Content = append(secuencia.Content, mapa)
+	}
+	return secuencia, nil
+}
@@ -0,0 +1,124 @@
+package cadena
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola/color"
+)
+
+// Adapta las secuencias de escape de s al nivel de soporte de la salida:
+//   - SIN_ESTILOS elimina todas las secuencias (estilos, colores, movimientos del cursor e hipervínculos);
+//   - SIN_COLOR conserva los estilos (negrita, subrayado...) y los movimientos del cursor, pero elimina los colores;
+//   - COLOR_16 y COLOR_256 reemplazan los colores de 24 bits (y los de 256, en el caso de COLOR_16) por el más cercano de su paleta;
+//   - COLOR_VERDADERO devuelve s sin cambios.
+func Degradar(s string, nivel color.Nivel) string {
+	if nivel >= color.COLOR_VERDADERO || !strings.Contains(s, "\x1b") {
+		return s
+	}
+	if nivel <= color.SIN_ESTILOS {
+		return SinEstilos(s)
+	}
+	return secuenciaANSI.ReplaceAllStringFunc(s, func(seq string) string {
+		if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+			return seq
+		}
+		parametros := degradarSGR(strings.Split(seq[2:len(seq)-1], ";"), nivel)
+		if len(parametros) == 0 {
+			return ""
+		}
+		return "\x1b[" + strings.Join(parametros, ";") + "m"
+	})
+}
+
+// Filtra o convierte los parámetros de una secuencia SGR (ESC[...m) según el nivel.
+func degradarSGR(parametros []string, nivel color.Nivel) []string {
+	if len(parametros) == 1 && parametros[0] == "" {
+		return parametros
+	}
+	resultado := make([]string, 0, len(parametros))
+	for i := 0; i < len(parametros); i++ {
+		p := parametros[i]
+		if base, _, compuesto := strings.Cut(p, ":"); compuesto && esColorExtendido(base) {
+			// La forma con ':' (38:2::r:g:b) no se convierte: sólo se conserva con color verdadero.
+			continue
+		}
+		switch {
+		case esColorExtendido(p):
+			largo := largoColorExtendido(parametros[i+1:])
+			if nivel > color.SIN_COLOR {
+				resultado = append(resultado, convertirExtendido(p, parametros[i+1:i+1+largo], nivel)...)
+			}
+			i += largo
+		case esColorBasico(p):
+			if nivel > color.SIN_COLOR {
+				resultado = append(resultado, p)
+			}
+		default:
+			resultado = append(resultado, p)
+		}
+	}
+	return resultado
+}
+
+// 38 (fuente), 48 (fondo) y 58 (subrayado) introducen un color de la paleta 256 o de 24 bits.
+func esColorExtendido(p string) bool {
+	return p == "38" || p == "48" || p == "58"
+}
+
+// Cantidad de parámetros que siguen a un 38/48/58: "5;n" o "2;r;g;b".
+func largoColorExtendido(resto []string) int {
+	if len(resto) == 0 {
+		return 0
+	}
+	switch resto[0] {
+	case "5":
+		return min(2, len(resto))
+	case "2":
+		return min(4, len(resto))
+	}
+	return 1
+}
+
+// Convierte un color extendido (base 38/48/58 seguida de sus argumentos) a la paleta del nivel.
+func convertirExtendido(base string, argumentos []string, nivel color.Nivel) []string {
+	numeros := make([]int, len(argumentos))
+	for i, a := range argumentos {
+		n, err := strconv.Atoi(a)
+		if err != nil || n < 0 || n > 255 {
+			return nil
+		}
+		numeros[i] = n
+	}
+	var c color.RGB
+	switch {
+	case len(numeros) == 2 && numeros[0] == 5:
+		if nivel >= color.COLOR_256 {
+			return append([]string{base}, argumentos...)
+		}
+		c = color.Indice(numeros[1]).RGB()
+	case len(numeros) == 4 && numeros[0] == 2:
+		c = color.RGB{R: uint8(numeros[1]), G: uint8(numeros[2]), B: uint8(numeros[3])}
+		if nivel == color.COLOR_256 {
+			return []string{base, "5", strconv.Itoa(int(c.Indice256()))}
+		}
+	default:
+		return nil
+	}
+	if base == "58" {
+		// El color de subrayado no tiene equivalente entre los 16 colores básicos.
+		return nil
+	}
+	return []string{strconv.Itoa(color.CodigoBasico(c.Indice16(), base == "48"))}
+}
+
+func esColorBasico(p string) bool {
+	var n int
+	for _, r := range p {
+		if r < '0' || r > '9' {
+			return false
+		}
+		n = n*10 + int(r-'0')
+	}
+	return p != "" && (n >= 30 && n <= 37 || n == 39 || n >= 40 && n <= 47 || n == 49 || n == 59 || n >= 90 && n <= 97 || n >= 100 && n <= 107)
+}
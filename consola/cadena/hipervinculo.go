@@ -0,0 +1,51 @@
+package cadena
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Envuelve texto en un hipervínculo OSC 8 hacia url. Las terminales que no lo admiten muestran sólo el texto.
+func Hipervinculo(texto string, url string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + texto + "\x1b]8;;\x1b\\"
+}
+
+// Devuelve true si la terminal asociada a os.Stdout probablemente admite hipervínculos OSC 8.
+// FORCE_HYPERLINK=1 o FORCE_HYPERLINK=0 fuerzan el resultado.
+func SoportaHipervinculos() bool {
+	return SoportaHipervinculosEn(os.Stdout)
+}
+
+// Como SoportaHipervinculos, para la terminal asociada a f (por ejemplo, la FSalida de una Consola).
+func SoportaHipervinculosEn(f *os.File) bool {
+	switch forzar := os.Getenv("FORCE_HYPERLINK"); forzar {
+	case "":
+	case "0", "false":
+		return false
+	default:
+		return true
+	}
+	if f == nil || !term.IsTerminal(int(f.Fd())) || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("DOMTERM") != "" {
+		return true
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper", "Tabby", "rio":
+		return true
+	}
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+	terminal := os.Getenv("TERM")
+	for _, t := range []string{"kitty", "foot", "alacritty", "wezterm", "ghostty"} {
+		if strings.Contains(terminal, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,649 @@
+package cadena
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hernanatn/aplicacion.go/consola/color"
+)
+
+type OpcionesMarkdown struct {
+	Ancho            int      // Ancho del texto; con 0 se usa el de la terminal
+	Tema             Tema     // Con un Tema vacío se usa TemaPredeterminado
+	SinHipervinculos bool     // Presenta los enlaces como "texto (url)" aunque la terminal admita hipervínculos OSC 8
+	Salida           *os.File // Terminal en la que se presentará el texto, para Ancho e hipervínculos; por defecto os.Stdout
+}
+
+var (
+	mdTitulo      = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdValla       = regexp.MustCompile("^(`{3,}|~{3,})[ \t]*([^`]*)$")
+	mdItem        = regexp.MustCompile(`^([-*+]|\d{1,9}[.)])( +|\t|$)(.*)$`)
+	mdTarea       = regexp.MustCompile(`^\[([ xX])\][ \t]+`)
+	mdDelimitador = regexp.MustCompile(`^\|?[ \t]*:?-+:?[ \t]*(\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	mdSubrayado   = regexp.MustCompile(`^(=+|-+)[ \t]*$`)
+	mdAutoenlace  = regexp.MustCompile(`^<((?:https?|ftp|mailto):[^>\s]+|[^>\s@]+@[^>\s]+)>`)
+)
+
+// Caracteres que se pueden escapar con "\" en Markdown.
+const mdPuntuacion = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// Viñetas de las listas según su nivel de anidamiento.
+var mdViñetas = []string{"•", "◦", "▪"}
+
+type renderizadorMarkdown struct {
+	tema          Tema
+	hipervinculos bool
+}
+
+// Presenta texto Markdown en la terminal: títulos (con los roles ROL_TITULO y ROL_SUBTITULO), énfasis, listas,
+// citas, bloques de código con resaltado de sintaxis (ver Resaltar), tablas (ver Cuadro) y enlaces, como hipervínculos
+// OSC 8 si la terminal los admite.
+//
+// # Ejemplo:
+//
+//	notas, _ := os.ReadFile("CAMBIOS.md")
+//	con.ImprimirCadena(cadena.Cadena(cadena.Markdown(string(notas), cadena.OpcionesMarkdown{Tema: con.Tema()})))
+func Markdown(fuente string, o OpcionesMarkdown) string {
+	if o.Salida == nil {
+		o.Salida = os.Stdout
+	}
+	if o.Ancho <= 0 {
+		o.Ancho = AnchoTerminalDe(o.Salida)
+	}
+	if o.Tema.Estilos == nil {
+		o.Tema = TemaPredeterminado
+	}
+	r := renderizadorMarkdown{o.Tema, !o.SinHipervinculos && SoportaHipervinculosEn(o.Salida)}
+	lineas := strings.Split(strings.ReplaceAll(fuente, "\r\n", "\n"), "\n")
+	for i, l := range lineas {
+		lineas[i] = expandirSangria(l)
+	}
+	return strings.Join(r.bloques(lineas, o.Ancho, 0), "\n") + "\n"
+}
+
+func (c Cadena) Markdown(o OpcionesMarkdown) Cadena {
+	return Cadena(Markdown(c.S(), o))
+}
+
+// Lee un archivo Markdown y lo presenta con Markdown.
+func MarkdownDesdeArchivo(nombre string, o OpcionesMarkdown) (Cadena, error) {
+	datos, err := os.ReadFile(nombre)
+	if err != nil {
+		return "", err
+	}
+	return Cadena(Markdown(string(datos), o)), nil
+}
+
+// Reemplaza las tabulaciones de la sangría por cuatro espacios.
+func expandirSangria(l string) string {
+	i := 0
+	for i < len(l) && (l[i] == ' ' || l[i] == '\t') {
+		i++
+	}
+	return strings.ReplaceAll(l[:i], "\t", "    ") + l[i:]
+}
+
+func sangria(l string) int {
+	return len(l) - len(strings.TrimLeft(l, " "))
+}
+
+func esBlanca(l string) bool {
+	return strings.TrimSpace(l) == ""
+}
+
+// Una línea de al menos tres "-", "*" o "_" iguales, con espacios opcionales entre ellos.
+func esSeparador(l string) bool {
+	l = strings.ReplaceAll(strings.ReplaceAll(strings.TrimSpace(l), " ", ""), "\t", "")
+	return len(l) >= 3 && strings.Trim(l, l[:1]) == "" && strings.Contains("-*_", l[:1])
+}
+
+func esTabla(lineas []string, i int) bool {
+	return i+1 < len(lineas) && strings.Contains(lineas[i], "|") && strings.Contains(lineas[i+1], "|") &&
+		mdDelimitador.MatchString(strings.TrimSpace(lineas[i+1])) && strings.Contains(lineas[i+1], "-")
+}
+
+// Devuelve true si la línea i inicia un bloque que interrumpe un párrafo.
+func iniciaBloque(lineas []string, i int) bool {
+	l := lineas[i]
+	if sangria(l) >= 4 {
+		return false
+	}
+	l = strings.TrimSpace(l)
+	return mdTitulo.MatchString(l) || mdValla.MatchString(l) || strings.HasPrefix(l, ">") ||
+		esSeparador(l) || (mdItem.MatchString(l) && !esBlanca(mdItem.FindStringSubmatch(l)[3])) || esTabla(lineas, i)
+}
+
+// Presenta las líneas como bloques separados por una línea vacía. profundidad es el nivel de anidamiento de listas.
+func (r renderizadorMarkdown) bloques(lineas []string, ancho int, profundidad int) []string {
+	var salida []string
+	agregar := func(bloque []string) {
+		if len(salida) > 0 {
+			salida = append(salida, "")
+		}
+		salida = append(salida, bloque...)
+	}
+
+	for i := 0; i < len(lineas); {
+		l := lineas[i]
+		recortada := strings.TrimSpace(l)
+		switch {
+		case recortada == "":
+			i++
+
+		case sangria(l) >= 4:
+			var codigo []string
+			for ; i < len(lineas) && (esBlanca(lineas[i]) || sangria(lineas[i]) >= 4); i++ {
+				codigo = append(codigo, strings.TrimPrefix(lineas[i], "    "))
+			}
+			for len(codigo) > 0 && esBlanca(codigo[len(codigo)-1]) {
+				codigo = codigo[:len(codigo)-1]
+			}
+			agregar(r.codigo(codigo, ""))
+
+		case mdValla.MatchString(recortada):
+			m := mdValla.FindStringSubmatch(recortada)
+			valla, lenguaje := m[1], ""
+			if f := strings.Fields(m[2]); len(f) > 0 {
+				lenguaje = f[0]
+			}
+			s := sangria(l)
+			var codigo []string
+			for i++; i < len(lineas); i++ {
+				cierre := strings.TrimSpace(lineas[i])
+				if strings.HasPrefix(cierre, valla) && strings.Trim(cierre, valla[:1]) == "" {
+					i++
+					break
+				}
+				codigo = append(codigo, lineas[i][min(s, sangria(lineas[i])):])
+			}
+			agregar(r.codigo(codigo, lenguaje))
+
+		case mdTitulo.MatchString(recortada):
+			m := mdTitulo.FindStringSubmatch(recortada)
+			agregar(r.titulo(len(m[1]), m[2], ancho))
+			i++
+
+		case esSeparador(recortada):
+			agregar([]string{r.tema.Aplicar(ROL_ATENUADO, strings.Repeat("─", ancho)).S()})
+			i++
+
+		case strings.HasPrefix(recortada, ">"):
+			var cita []string
+			for ; i < len(lineas) && !esBlanca(lineas[i]); i++ {
+				c := strings.TrimSpace(lineas[i])
+				if strings.HasPrefix(c, ">") {
+					c = strings.TrimPrefix(strings.TrimPrefix(c, ">"), " ")
+				} else if iniciaBloque(lineas, i) {
+					break
+				}
+				cita = append(cita, c)
+			}
+			barra := r.tema.Aplicar(ROL_CITA, "│ ").S()
+			estilo := r.tema.Estilo(ROL_CITA).Secuencia()
+			var bloque []string
+			for _, c := range r.bloques(cita, max(ancho-2, 1), profundidad) {
+				linea := barra + estilo + c
+				if estilo != "" {
+					linea += color.Resetear
+				}
+				bloque = append(bloque, linea)
+			}
+			agregar(bloque)
+
+		case mdItem.MatchString(recortada):
+			var bloque []string
+			bloque, i = r.lista(lineas, i, ancho, profundidad)
+			agregar(bloque)
+
+		case esTabla(lineas, i):
+			var filas []string
+			for ; i < len(lineas) && !esBlanca(lineas[i]) && strings.Contains(lineas[i], "|"); i++ {
+				filas = append(filas, strings.TrimSpace(lineas[i]))
+			}
+			agregar(r.tabla(filas, ancho))
+
+		default:
+			var parrafo []string
+			nivel := 0
+			for ; i < len(lineas) && !esBlanca(lineas[i]); i++ {
+				if len(parrafo) > 0 {
+					if m := mdSubrayado.FindStringSubmatch(strings.TrimSpace(lineas[i])); m != nil && sangria(lineas[i]) < 4 {
+						nivel = 2
+						if m[1][0] == '=' {
+							nivel = 1
+						}
+						i++
+						break
+					}
+					if iniciaBloque(lineas, i) {
+						break
+					}
+				}
+				parrafo = append(parrafo, lineas[i])
+			}
+			if nivel > 0 {
+				agregar(r.titulo(nivel, strings.TrimSpace(strings.Join(parrafo, " ")), ancho))
+			} else {
+				agregar(r.parrafo(parrafo, ancho))
+			}
+		}
+	}
+	return salida
+}
+
+func (r renderizadorMarkdown) titulo(nivel int, texto string, ancho int) []string {
+	rol := ROL_ENFASIS
+	switch nivel {
+	case 1:
+		rol = ROL_TITULO
+	case 2:
+		rol = ROL_SUBTITULO
+	}
+	abierto := r.tema.Estilo(rol).Secuencia()
+	contenido := r.enLinea(texto, abierto)
+	if abierto != "" {
+		contenido = abierto + contenido + color.Resetear
+	}
+	return LineasParrafo(contenido, OpcionesParrafo{Ancho: ancho})
+}
+
+// Un párrafo: las líneas que terminan en dos espacios o en "\" fuerzan un salto de línea.
+func (r renderizadorMarkdown) parrafo(lineas []string, ancho int) []string {
+	var salida []string
+	var actual []string
+	cerrar := func() {
+		if len(actual) > 0 {
+			salida = append(salida, LineasParrafo(r.enLinea(strings.Join(actual, " "), ""), OpcionesParrafo{Ancho: ancho})...)
+			actual = nil
+		}
+	}
+	for _, l := range lineas {
+		switch {
+		case strings.HasSuffix(l, "  "):
+			actual = append(actual, strings.TrimSpace(l))
+			cerrar()
+		case strings.HasSuffix(l, "\\") && !strings.HasSuffix(l, "\\\\"):
+			actual = append(actual, strings.TrimSpace(strings.TrimSuffix(l, "\\")))
+			cerrar()
+		default:
+			actual = append(actual, strings.TrimSpace(l))
+		}
+	}
+	cerrar()
+	return salida
+}
+
+func (r renderizadorMarkdown) codigo(lineas []string, lenguaje string) []string {
+	if len(lineas) == 0 {
+		return []string{}
+	}
+	resaltadas := Resaltar(strings.Join(lineas, "\n"), lenguaje, r.tema)
+	for i, l := range resaltadas {
+		resaltadas[i] = "  " + l
+	}
+	return resaltadas
+}
+
+// Presenta la lista que empieza en la línea i y devuelve la línea siguiente a ella.
+func (r renderizadorMarkdown) lista(lineas []string, i int, ancho int, profundidad int) ([]string, int) {
+	primero := mdItem.FindStringSubmatch(strings.TrimSpace(lineas[i]))
+	ordenada := unicode.IsDigit(rune(primero[1][0]))
+	tipo := primero[1][len(primero[1])-1:]
+	inicio := 1
+	if ordenada {
+		inicio, _ = strconv.Atoi(primero[1][:len(primero[1])-1])
+	}
+
+	var items [][]string
+	suelta := false
+	for i < len(lineas) {
+		l := lineas[i]
+		m := mdItem.FindStringSubmatch(strings.TrimSpace(l))
+		if m == nil || sangria(l) >= 4 || m[1][len(m[1])-1:] != tipo || esSeparador(l) {
+			break
+		}
+		desplazamiento := sangria(l) + len(m[1]) + len(m[2])
+		if len(m[2]) > 4 {
+			desplazamiento = sangria(l) + len(m[1]) + 1
+		}
+		contenido := []string{m[3]}
+	continuacion:
+		for i++; i < len(lineas); i++ {
+			l := lineas[i]
+			switch {
+			case esBlanca(l):
+				contenido = append(contenido, "")
+			case sangria(l) >= desplazamiento:
+				contenido = append(contenido, l[desplazamiento:])
+			case !esBlanca(contenido[len(contenido)-1]) && !iniciaBloque(lineas, i):
+				// Continuación perezosa del párrafo
+				contenido = append(contenido, strings.TrimSpace(l))
+			default:
+				break continuacion
+			}
+		}
+		blancas := 0
+		for len(contenido) > 1 && esBlanca(contenido[len(contenido)-1]) {
+			contenido = contenido[:len(contenido)-1]
+			blancas++
+		}
+		// Una lista es suelta si sus ítems o los bloques de un ítem están separados por líneas vacías.
+		if slices.Contains(contenido, "") {
+			suelta = true
+		}
+		items = append(items, contenido)
+		if blancas > 0 {
+			siguiente := []string(nil)
+			if i < len(lineas) {
+				siguiente = mdItem.FindStringSubmatch(strings.TrimSpace(lineas[i]))
+			}
+			if siguiente == nil || !strings.HasSuffix(siguiente[1], tipo) {
+				break
+			}
+			suelta = true
+		}
+	}
+
+	marcas := make([]string, len(items))
+	anchoMarca := 0
+	for k := range items {
+		if ordenada {
+			marcas[k] = fmt.Sprintf("%d%s", inicio+k, tipo)
+		} else {
+			marcas[k] = mdViñetas[profundidad%len(mdViñetas)]
+		}
+		anchoMarca = max(anchoMarca, Ancho(marcas[k]))
+	}
+	anchoMarca++
+
+	var salida []string
+	for k, contenido := range items {
+		tarea := ""
+		if m := mdTarea.FindStringSubmatch(contenido[0]); m != nil {
+			tarea = "☐ "
+			if m[1] != " " {
+				tarea = "☑ "
+			}
+			contenido[0] = contenido[0][len(m[0]):]
+		}
+		if suelta && k > 0 {
+			salida = append(salida, "")
+		}
+		cuerpo := r.bloques(contenido, max(ancho-anchoMarca-Ancho(tarea), 1), profundidad+1)
+		if len(cuerpo) == 0 {
+			cuerpo = []string{""}
+		}
+		marca := r.tema.Aplicar(ROL_ENFASIS, Rellenar(marcas[k], anchoMarca, IZQUIERDA)).S()
+		for j, c := range cuerpo {
+			if !suelta && c == "" {
+				continue
+			}
+			switch {
+			case j == 0:
+				salida = append(salida, marca+tarea+c)
+			case c == "":
+				salida = append(salida, "")
+			default:
+				salida = append(salida, strings.Repeat(" ", anchoMarca+Ancho(tarea))+c)
+			}
+		}
+	}
+	return salida, i
+}
+
+// Separa una fila de tabla en celdas, respetando las barras escapadas.
+func celdasTabla(fila string) []string {
+	fila = strings.TrimSuffix(strings.TrimPrefix(fila, "|"), "|")
+	var celdas []string
+	var actual strings.Builder
+	for i := 0; i < len(fila); i++ {
+		switch {
+		case fila[i] == '\\' && i+1 < len(fila) && fila[i+1] == '|':
+			actual.WriteByte('|')
+			i++
+		case fila[i] == '|':
+			celdas = append(celdas, strings.TrimSpace(actual.String()))
+			actual.Reset()
+		default:
+			actual.WriteByte(fila[i])
+		}
+	}
+	return append(celdas, strings.TrimSpace(actual.String()))
+}
+
+func (r renderizadorMarkdown) tabla(filas []string, ancho int) []string {
+	cuadro := &Cuadro{Borde: BORDE_REDONDEADO, Tema: r.tema, Ancho: ancho, Margen: 1}
+	for j, t := range celdasTabla(filas[0]) {
+		cuadro.Columna(j).Titulo = r.enLinea(t, "")
+	}
+	for j, d := range celdasTabla(filas[1]) {
+		switch {
+		case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+			cuadro.Columna(j).Alineado = CENTRO
+		case strings.HasSuffix(d, ":"):
+			cuadro.Columna(j).Alineado = DERECHA
+		}
+	}
+	for _, f := range filas[2:] {
+		celdas := celdasTabla(f)
+		for j, c := range celdas {
+			celdas[j] = r.enLinea(c, "")
+		}
+		cuadro.AgregarFila(celdas...)
+	}
+	return strings.Split(strings.TrimSuffix(cuadro.Renderizar(), "\n"), "\n")
+}
+
+// Busca el cierre del delimitador delim a partir de la posición desde, que no esté precedido por un espacio.
+func cierreEnfasis(s string, desde int, delim string) int {
+	for j := desde; j+len(delim) <= len(s); j++ {
+		if s[j] == '\\' {
+			j++
+			continue
+		}
+		if s[j] == '`' {
+			// Los delimitadores dentro de código no cuentan.
+			if fin := strings.Index(s[j+1:], "`"); fin >= 0 {
+				j += fin + 1
+			}
+			continue
+		}
+		if strings.HasPrefix(s[j:], delim) && j > desde && s[j-1] != ' ' {
+			finCorrida := j
+			for finCorrida < len(s) && s[finCorrida] == delim[0] {
+				finCorrida++
+			}
+			// "**" no cierra un "*"; en una corrida más larga ("***") el cierre es su final.
+			if len(delim) == 1 && finCorrida-j == 2 {
+				j = finCorrida - 1
+				continue
+			}
+			// "_" no cierra en medio de una palabra.
+			if delim[0] == '_' && finCorrida < len(s) {
+				if r, _ := utf8.DecodeRuneInString(s[finCorrida:]); esLetra(r) {
+					j = finCorrida - 1
+					continue
+				}
+			}
+			return finCorrida - len(delim)
+		}
+	}
+	return -1
+}
+
+// Busca el corchete que cierra el abierto en la posición desde.
+func cierreCorchete(s string, desde int) int {
+	profundidad := 0
+	for j := desde; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			profundidad++
+		case ']':
+			profundidad--
+			if profundidad == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// Interpreta el destino de un enlace "(url "título")" que empieza en desde. Devuelve la url y la posición siguiente al paréntesis.
+func destinoEnlace(s string, desde int) (string, int, bool) {
+	if desde >= len(s) || s[desde] != '(' {
+		return "", 0, false
+	}
+	profundidad := 0
+	for j := desde; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '(':
+			profundidad++
+		case ')':
+			profundidad--
+			if profundidad == 0 {
+				destino := strings.TrimSpace(s[desde+1 : j])
+				if url, _, ok := strings.Cut(destino, " "); ok {
+					destino = url
+				}
+				return strings.Trim(destino, "<>"), j + 1, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// Presenta un texto en línea: código, énfasis, tachado, enlaces e imágenes. abierto son las secuencias del estilo
+// que envuelve al texto, para volver a activarlas después de cada estilo interno.
+func (r renderizadorMarkdown) enLinea(s string, abierto string) string {
+	var sb strings.Builder
+	estilizar := func(e Estilo, contenido string) {
+		seq := e.Secuencia()
+		if seq == "" {
+			sb.WriteString(contenido)
+			return
+		}
+		sb.WriteString(seq + contenido + color.Resetear + abierto)
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(mdPuntuacion, s[i+1]) >= 0:
+			sb.WriteByte(s[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			valla := s[i : i+n]
+			if fin := strings.Index(s[i+n:], valla); fin >= 0 {
+				codigo := s[i+n : i+n+fin]
+				if len(codigo) > 1 && codigo[0] == ' ' && codigo[len(codigo)-1] == ' ' {
+					codigo = codigo[1 : len(codigo)-1]
+				}
+				estilizar(r.tema.Estilo(ROL_CODIGO), codigo)
+				i += n + fin + n
+				continue
+			}
+			sb.WriteString(valla)
+			i += n
+			continue
+
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if fin := cierreCorchete(s, i+1); fin > 0 {
+				if url, siguiente, ok := destinoEnlace(s, fin+1); ok {
+					sb.WriteString(r.enlace("🖼 "+s[i+2:fin], url, abierto))
+					i = siguiente
+					continue
+				}
+			}
+
+		case c == '[':
+			if fin := cierreCorchete(s, i); fin > 0 {
+				if url, siguiente, ok := destinoEnlace(s, fin+1); ok {
+					sb.WriteString(r.enlace(s[i+1:fin], url, abierto))
+					i = siguiente
+					continue
+				}
+			}
+
+		case c == '<':
+			if m := mdAutoenlace.FindStringSubmatch(s[i:]); m != nil {
+				url := m[1]
+				if !strings.Contains(url, ":") {
+					url = "mailto:" + url
+				}
+				sb.WriteString(r.enlace(m[1], url, abierto))
+				i += len(m[0])
+				continue
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			n := len(s[i:]) - len(strings.TrimLeft(s[i:], string(c)))
+			intrapalabra := false
+			if c == '_' && i > 0 {
+				anterior, _ := utf8.DecodeLastRuneInString(s[:i])
+				intrapalabra = esLetra(anterior)
+			}
+			if c == '~' && n != 2 || intrapalabra || i+n >= len(s) || s[i+n] == ' ' {
+				sb.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			n = min(n, 3)
+			delim := s[i : i+n]
+			fin := cierreEnfasis(s, i+n, delim)
+			for fin < 0 && n > 1 {
+				n--
+				delim = s[i : i+n]
+				fin = cierreEnfasis(s, i+n, delim)
+			}
+			if fin < 0 {
+				sb.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+			var e Estilo
+			switch {
+			case c == '~':
+				e.Tachada = true
+			case n == 1:
+				e.Italica = true
+			case n == 2:
+				e.Negrita = true
+			default:
+				e.Negrita, e.Italica = true, true
+			}
+			seq := e.Secuencia()
+			sb.WriteString(seq + r.enLinea(s[i+n:fin], abierto+seq) + color.Resetear + abierto)
+			i = fin + n
+			continue
+		}
+		_, largo := utf8.DecodeRuneInString(s[i:])
+		sb.WriteString(s[i : i+largo])
+		i += largo
+	}
+	return sb.String()
+}
+
+func (r renderizadorMarkdown) enlace(texto string, url string, abierto string) string {
+	estilo := r.tema.Estilo(ROL_ENLACE).Secuencia()
+	visible := estilo + r.enLinea(texto, abierto+estilo) + color.Resetear + abierto
+	if r.hipervinculos {
+		return Hipervinculo(visible, url)
+	}
+	if SinEstilos(visible) == strings.TrimPrefix(url, "mailto:") {
+		return visible
+	}
+	return visible + " " + r.tema.Aplicar(ROL_ATENUADO, "("+url+")").S() + abierto
+}
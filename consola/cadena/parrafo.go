@@ -0,0 +1,274 @@
+package cadena
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola/color"
+	"golang.org/x/term"
+)
+
+// Ancho utilizado cuando no se puede determinar el de la terminal.
+const ANCHO_POR_DEFECTO = 80
+
+// Devuelve el ancho en columnas de la terminal asociada a os.Stdout, o la variable COLUMNS, o ANCHO_POR_DEFECTO.
+func AnchoTerminal() int {
+	return AnchoTerminalDe(os.Stdout)
+}
+
+// Como AnchoTerminal, para la terminal asociada a f (por ejemplo, la FSalida de una Consola).
+func AnchoTerminalDe(f *os.File) int {
+	if ancho, _, err := term.GetSize(int(f.Fd())); err == nil && ancho > 0 {
+		return ancho
+	}
+	if ancho, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && ancho > 0 {
+		return ancho
+	}
+	return ANCHO_POR_DEFECTO
+}
+
+type OpcionesParrafo struct {
+	Ancho    int          // Ancho del texto, sin contar Sangria ni Prefijo. Con 0 se usa el de la terminal.
+	Alineado TipoAlineado // IZQUIERDA, DERECHA, CENTRO o JUSTIFICADO
+	Sangria  string       // Antecede a la primera línea
+	Prefijo  string       // Antecede a las demás líneas; más largo que Sangria produce una sangría francesa
+	Sufijo   string       // Sigue a cada línea
+	Guionado bool         // Corta con guion las palabras que no entran en el espacio restante de la línea
+}
+
+// Una palabra del párrafo, con las secuencias de escape que contiene.
+type palabra struct {
+	texto string
+	ancho int
+}
+
+// Separa s en palabras. Los espacios se descartan; las secuencias de escape quedan pegadas a la palabra siguiente (o a la última).
+func palabras(s string) []palabra {
+	var resultado []palabra
+	var actual strings.Builder
+	var ancho int
+	var escapes string
+	recorrer(s, func(fragmento string, a int, esEscape bool) bool {
+		switch {
+		case esEscape:
+			if actual.Len() > 0 {
+				actual.WriteString(fragmento)
+			} else {
+				escapes += fragmento
+			}
+		case strings.TrimSpace(fragmento) == "":
+			if actual.Len() > 0 {
+				resultado = append(resultado, palabra{actual.String(), ancho})
+				actual.Reset()
+				ancho = 0
+			}
+		default:
+			if actual.Len() == 0 {
+				actual.WriteString(escapes)
+				escapes = ""
+			}
+			actual.WriteString(fragmento)
+			ancho += a
+		}
+		return true
+	})
+	if actual.Len() > 0 {
+		resultado = append(resultado, palabra{actual.String(), ancho})
+	} else if escapes != "" {
+		if len(resultado) > 0 {
+			resultado[len(resultado)-1].texto += escapes
+		} else {
+			resultado = append(resultado, palabra{escapes, 0})
+		}
+	}
+	return resultado
+}
+
+// Corta p para que la primera parte ocupe como máximo ancho columnas.
+func cortarPalabra(p palabra, ancho int) (palabra, palabra) {
+	cabeza, cola := Cortar(p.texto, ancho)
+	a := Ancho(cabeza)
+	return palabra{cabeza, a}, palabra{cola, p.ancho - a}
+}
+
+type linea struct {
+	palabras []palabra
+	ancho    int  // ancho de las palabras más un espacio entre cada una
+	ultima   bool // última línea del párrafo: no se justifica
+}
+
+// Distribuye las palabras en líneas de como máximo ancho columnas.
+func componer(ps []palabra, ancho int, guionado bool) []linea {
+	var lineas []linea
+	var actual linea
+	disponible := max(1, ancho)
+	cerrar := func() {
+		lineas = append(lineas, actual)
+		actual = linea{}
+	}
+	agregar := func(p palabra) {
+		if len(actual.palabras) > 0 {
+			actual.ancho++
+		}
+		actual.palabras = append(actual.palabras, p)
+		actual.ancho += p.ancho
+	}
+
+	for _, p := range ps {
+		for {
+			separador := 0
+			if len(actual.palabras) > 0 {
+				separador = 1
+			}
+			restante := disponible - actual.ancho - separador
+			if p.ancho <= restante {
+				agregar(p)
+				break
+			}
+			// Con guionado se aprovecha el espacio restante si entran al menos dos columnas de la palabra y el guion.
+			if guionado && restante >= 3 && p.ancho > 3 {
+				cabeza, cola := cortarPalabra(p, restante-1)
+				if cabeza.ancho >= 2 {
+					cabeza.texto += "-"
+					cabeza.ancho++
+					agregar(cabeza)
+					cerrar()
+					p = cola
+					continue
+				}
+			}
+			if len(actual.palabras) > 0 {
+				cerrar()
+				continue
+			}
+			// La palabra no entra en una línea vacía: se corta a la fuerza.
+			corte := disponible
+			if guionado && corte > 1 {
+				corte--
+			}
+			cabeza, cola := cortarPalabra(p, corte)
+			if cabeza.ancho == 0 {
+				// Un grafema más ancho que la línea: se lo ubica solo en ella.
+				cabeza, cola = palabra{p.texto, p.ancho}, palabra{}
+			} else if guionado && cola.ancho > 0 {
+				cabeza.texto += "-"
+				cabeza.ancho++
+			}
+			agregar(cabeza)
+			cerrar()
+			if cola.ancho == 0 {
+				if cola.texto != "" && len(lineas) > 0 {
+					ultima := &lineas[len(lineas)-1]
+					ultima.palabras[len(ultima.palabras)-1].texto += cola.texto
+				}
+				break
+			}
+			p = cola
+		}
+	}
+	if len(actual.palabras) > 0 || len(lineas) == 0 {
+		cerrar()
+	}
+	lineas[len(lineas)-1].ultima = true
+	return lineas
+}
+
+// Arma el texto de la línea según el alineado, con espacios entre palabras.
+func (l linea) renderizar(ancho int, alineado TipoAlineado) string {
+	huecos := len(l.palabras) - 1
+	extra := ancho - l.ancho
+	var sb strings.Builder
+	switch {
+	case alineado == DERECHA && extra > 0:
+		sb.WriteString(strings.Repeat(" ", extra))
+	case alineado == CENTRO && extra > 0:
+		sb.WriteString(strings.Repeat(" ", extra/2))
+	}
+	for i, p := range l.palabras {
+		if i > 0 {
+			espacios := 1
+			if alineado == JUSTIFICADO && !l.ultima && extra > 0 && huecos > 0 {
+				espacios += extra / huecos
+				if i <= extra%huecos {
+					espacios++
+				}
+			}
+			sb.WriteString(strings.Repeat(" ", espacios))
+		}
+		sb.WriteString(p.texto)
+	}
+	return sb.String()
+}
+
+// Devuelve el estado de estilos (secuencias SGR activas) después de recorrer s partiendo de estado.
+func estadoEstilos(estado string, s string) string {
+	for _, seq := range secuenciaANSI.FindAllString(s, -1) {
+		if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "m") {
+			continue
+		}
+		if seq == "\x1b[0m" || seq == "\x1b[m" {
+			estado = ""
+		} else {
+			estado += seq
+		}
+	}
+	return estado
+}
+
+// Distribuye s en líneas de texto según las opciones y devuelve las líneas ya alineadas, sin Sangria, Prefijo ni Sufijo.
+// Cada "\n" de s inicia un párrafo nuevo. Los estilos abiertos al final de una línea se cierran y se vuelven a abrir en la siguiente.
+func LineasParrafo(s string, o OpcionesParrafo) []string {
+	ancho := o.Ancho
+	if ancho <= 0 {
+		ancho = AnchoTerminal() - max(Ancho(o.Sangria), Ancho(o.Prefijo))
+	}
+	var resultado []string
+	estado := ""
+	for _, p := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		for _, l := range componer(palabras(p), ancho, o.Guionado) {
+			texto := l.renderizar(ancho, o.Alineado)
+			siguiente := estadoEstilos(estado, texto)
+			texto = estado + texto
+			if siguiente != "" {
+				texto += color.Resetear
+			}
+			estado = siguiente
+			resultado = append(resultado, texto)
+		}
+	}
+	return resultado
+}
+
+// Distribuye s en un párrafo: ajusta las líneas en los límites de palabra, las alinea y agrega Sangria, Prefijo y Sufijo.
+//
+// # Ejemplo:
+//
+//	cadena.Parrafo(descripcion, cadena.OpcionesParrafo{
+//		Ancho:    60,
+//		Alineado: cadena.JUSTIFICADO,
+//		Sangria:  "  - ",
+//		Prefijo:  "    ",
+//	})
+func Parrafo(s string, o OpcionesParrafo) string {
+	lineas := LineasParrafo(s, o)
+	var sb strings.Builder
+	for i, l := range lineas {
+		if i == 0 {
+			sb.WriteString(o.Sangria)
+		} else {
+			sb.WriteString("\n" + o.Prefijo)
+		}
+		sb.WriteString(l + o.Sufijo)
+	}
+	return sb.String()
+}
+
+func (c Cadena) Parrafo(o OpcionesParrafo) Cadena {
+	return Cadena(Parrafo(c.S(), o))
+}
+
+// Ajusta s a líneas de como máximo ancho columnas, alineadas a la izquierda.
+func Envolver(s string, ancho int) []string {
+	return LineasParrafo(s, OpcionesParrafo{Ancho: ancho})
+}
@@ -0,0 +1,188 @@
+package cadena
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+var rolesPlantilla = []Rol{
+	ROL_TITULO, ROL_SUBTITULO, ROL_ENFASIS, ROL_ATENUADO, ROL_SUGERENCIA, ROL_DEBUG, ROL_OK, ROL_EXITO,
+	ROL_ADVERTENCIA, ROL_ERROR, ROL_FATAL, ROL_SEÑALADOR, ROL_SELECCION, ROL_ENCABEZADO, ROL_PIE, ROL_FILA_ALTERNA,
+	ROL_CODIGO, ROL_PALABRA_CLAVE, ROL_LITERAL, ROL_COMENTARIO, ROL_ENLACE, ROL_CITA,
+}
+
+// Convierte el valor recibido por una función de plantilla en texto.
+func textoPlantilla(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case Cadena:
+		return t.S()
+	case fmt.Stringer:
+		return t.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// Devuelve las funciones disponibles en las plantillas de salida. Los estilos de los roles se toman de t.
+//
+// Cada función recibe el texto a formatear como último argumento, de modo que se pueden encadenar:
+//
+//	{{.Nombre | negrita}}  {{.Estado | colorear "verde"}}  {{.Descripcion | truncar 30 | atenuado}}
+//
+// Estilos: negrita, italica, subrayada, invertida, tachada, colorear <color>, fondo <color>, estilo <spec>
+// (ver InterpretarEstilo), rol <nombre> y una función por cada Rol (titulo, error, exito, codigo...).
+//
+// Texto: justificar <ancho>, ajustar <ancho>, truncar <ancho>, izquierda|derecha|centrar <ancho>, ancho,
+// mayusculas, minusculas, recortar, repetir <n>, unir <separador>, sinEstilos, markdown, hipervinculo <url>.
+//
+// Datos: tabla (un Tabulable, o encabezados y filas), json y jsonIndentado.
+func FuncionesPlantilla(t Tema) template.FuncMap {
+	return FuncionesPlantillaEn(t, os.Stdout)
+}
+
+// Como FuncionesPlantilla, para presentar en la terminal asociada a f: markdown e hipervinculo usan su ancho y
+// consultan si admite hipervínculos.
+func FuncionesPlantillaEn(t Tema, f *os.File) template.FuncMap {
+	if t.Estilos == nil {
+		t = TemaPredeterminado
+	}
+	aplicar := func(e Estilo) func(any) string {
+		return func(v any) string { return e.Aplicar(textoPlantilla(v)) }
+	}
+	rellenar := func(alineado TipoAlineado) func(int, any) string {
+		return func(ancho int, v any) string { return Rellenar(textoPlantilla(v), ancho, alineado) }
+	}
+	colorear := func(fondo bool) func(string, any) (string, error) {
+		return func(nombre string, v any) (string, error) {
+			seq, err := secuenciaColor(strings.ToLower(nombre), fondo)
+			if err != nil {
+				return "", err
+			}
+			return Colorear(textoPlantilla(v), seq), nil
+		}
+	}
+
+	funciones := template.FuncMap{
+		"negrita":   aplicar(Estilo{Negrita: true}),
+		"italica":   aplicar(Estilo{Italica: true}),
+		"subrayada": aplicar(Estilo{Subrayada: true}),
+		"invertida": aplicar(Estilo{Invertida: true}),
+		"tachada":   aplicar(Estilo{Tachada: true}),
+		"colorear":  colorear(false),
+		"fondo":     colorear(true),
+		"estilo": func(spec string, v any) (string, error) {
+			e, err := InterpretarEstilo(spec)
+			if err != nil {
+				return "", err
+			}
+			return e.Aplicar(textoPlantilla(v)), nil
+		},
+		"rol": func(nombre string, v any) (string, error) {
+			if err := t.validarRol(Rol(nombre)); err != nil {
+				return "", err
+			}
+			return t.Aplicar(Rol(nombre), textoPlantilla(v)).S(), nil
+		},
+
+		"justificar": func(ancho int, v any) string {
+			return TextoJustificado(textoPlantilla(v), ancho, OpcionesFormato{Alineado: JUSTIFICADO})
+		},
+		"ajustar": func(ancho int, v any) string {
+			return strings.Join(LineasParrafo(textoPlantilla(v), OpcionesParrafo{Ancho: ancho}), "\n")
+		},
+		"truncar": func(ancho int, v any) string {
+			return Truncar(textoPlantilla(v), ancho, "…")
+		},
+		"izquierda":  rellenar(IZQUIERDA),
+		"derecha":    rellenar(DERECHA),
+		"centrar":    rellenar(CENTRO),
+		"ancho":      func(v any) int { return Ancho(textoPlantilla(v)) },
+		"mayusculas": func(v any) string { return strings.ToUpper(textoPlantilla(v)) },
+		"minusculas": func(v any) string { return strings.ToLower(textoPlantilla(v)) },
+		"recortar":   func(v any) string { return strings.TrimSpace(textoPlantilla(v)) },
+		"repetir":    func(n int, v any) string { return strings.Repeat(textoPlantilla(v), max(n, 0)) },
+		"unir": func(separador string, v any) (string, error) {
+			switch l := v.(type) {
+			case []string:
+				return strings.Join(l, separador), nil
+			case []any:
+				partes := make([]string, len(l))
+				for i, e := range l {
+					partes[i] = textoPlantilla(e)
+				}
+				return strings.Join(partes, separador), nil
+			}
+			return "", fmt.Errorf("unir: se esperaba una lista, se recibió %T", v)
+		},
+		"sinEstilos": func(v any) string { return SinEstilos(textoPlantilla(v)) },
+		"markdown": func(v any) string {
+			return strings.TrimRight(Markdown(textoPlantilla(v), OpcionesMarkdown{Tema: t, Salida: f}), "\n")
+		},
+		"hipervinculo": func(url string, v any) string {
+			texto := textoPlantilla(v)
+			if !SoportaHipervinculosEn(f) {
+				return texto
+			}
+			return Hipervinculo(texto, url)
+		},
+
+		"tabla": func(args ...any) (string, error) {
+			var d DatosTabla
+			switch {
+			case len(args) == 1:
+				tabulable, ok := args[0].(Tabulable)
+				if !ok {
+					return "", fmt.Errorf("tabla: %T no es Tabulable", args[0])
+				}
+				d.Encabezados, d.Filas = tabulable.Tabular()
+			case len(args) == 2:
+				encabezados, ok1 := args[0].([]string)
+				filas, ok2 := args[1].([][]string)
+				if !ok1 || !ok2 {
+					return "", fmt.Errorf("tabla: se esperaban []string y [][]string, se recibió %T y %T", args[0], args[1])
+				}
+				d.Encabezados, d.Filas = encabezados, filas
+			default:
+				return "", fmt.Errorf("tabla: se esperaban 1 o 2 argumentos, se recibieron %d", len(args))
+			}
+			return strings.TrimRight(d.Cuadro().ConTema(t, false).Renderizar(), "\n"), nil
+		},
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"jsonIndentado": func(v any) (string, error) {
+			b, err := json.MarshalIndent(v, "", "  ")
+			return string(b), err
+		},
+	}
+	for _, r := range rolesPlantilla {
+		funciones[string(r)] = aplicar(t.Estilo(r))
+	}
+	return funciones
+}
+
+// Crea una plantilla de salida con las funciones de FuncionesPlantilla(t).
+func NuevaPlantilla(nombre string, texto string, t Tema) (*template.Template, error) {
+	return template.New(nombre).Funcs(FuncionesPlantilla(t)).Option("missingkey=zero").Parse(texto)
+}
+
+// Ejecuta la plantilla texto con datos y escribe el resultado en w.
+//
+// # Ejemplo:
+//
+//	cadena.EjecutarPlantilla(os.Stdout, "{{.Nombre | negrita}} ({{.Version | atenuado}})\n", paquete, cadena.TemaPredeterminado)
+func EjecutarPlantilla(w io.Writer, texto string, datos any, t Tema) error {
+	p, err := NuevaPlantilla("salida", texto, t)
+	if err != nil {
+		return err
+	}
+	return p.Execute(w, datos)
+}
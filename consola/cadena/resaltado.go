@@ -0,0 +1,220 @@
+package cadena
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Reglas léxicas mínimas de un lenguaje para el resaltado de sintaxis.
+type sintaxis struct {
+	comentarios   []string  // Inician un comentario hasta el fin de la línea
+	bloque        [2]string // Apertura y cierre de comentarios de varias líneas
+	comillas      string
+	palabras      []string
+	sinMayusculas bool // Las palabras clave no distinguen mayúsculas
+}
+
+var (
+	sintaxisGo = &sintaxis{
+		comentarios: []string{"//"},
+		bloque:      [2]string{"/*", "*/"},
+		comillas:    "\"'`",
+		palabras: []string{"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+			"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select",
+			"struct", "switch", "type", "var", "nil", "true", "false", "iota"},
+	}
+	sintaxisJavascript = &sintaxis{
+		comentarios: []string{"//"},
+		bloque:      [2]string{"/*", "*/"},
+		comillas:    "\"'`",
+		palabras: []string{"async", "await", "break", "case", "catch", "class", "const", "continue", "default",
+			"delete", "do", "else", "export", "extends", "false", "finally", "for", "from", "function", "if", "import",
+			"in", "instanceof", "interface", "let", "new", "null", "of", "return", "super", "switch", "this", "throw",
+			"true", "try", "type", "typeof", "undefined", "var", "void", "while", "yield"},
+	}
+	sintaxisPython = &sintaxis{
+		comentarios: []string{"#"},
+		comillas:    "\"'",
+		palabras: []string{"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del",
+			"elif", "else", "except", "False", "finally", "for", "from", "global", "if", "import", "in", "is",
+			"lambda", "None", "nonlocal", "not", "or", "pass", "raise", "return", "True", "try", "while", "with", "yield"},
+	}
+	sintaxisShell = &sintaxis{
+		comentarios: []string{"#"},
+		comillas:    "\"'",
+		palabras: []string{"if", "then", "else", "elif", "fi", "for", "while", "until", "do", "done", "case", "esac",
+			"in", "function", "return", "export", "local", "exit", "set", "unset"},
+	}
+	sintaxisSql = &sintaxis{
+		comentarios: []string{"--"},
+		bloque:      [2]string{"/*", "*/"},
+		comillas:    "\"'",
+		palabras: []string{"select", "from", "where", "insert", "into", "values", "update", "set", "delete", "create",
+			"table", "drop", "alter", "and", "or", "not", "null", "is", "in", "join", "left", "right", "inner", "outer",
+			"on", "group", "by", "order", "having", "limit", "as", "distinct", "primary", "key", "default"},
+		sinMayusculas: true,
+	}
+	sintaxisDatos = &sintaxis{
+		comentarios: []string{"#"},
+		comillas:    "\"'",
+		palabras:    []string{"true", "false", "null", "yes", "no", "on", "off"},
+	}
+)
+
+// Sintaxis por nombre de lenguaje, tal como se indica en los bloques de código de Markdown.
+var sintaxisPorLenguaje = map[string]*sintaxis{
+	"go":         sintaxisGo,
+	"golang":     sintaxisGo,
+	"js":         sintaxisJavascript,
+	"javascript": sintaxisJavascript,
+	"ts":         sintaxisJavascript,
+	"typescript": sintaxisJavascript,
+	"py":         sintaxisPython,
+	"python":     sintaxisPython,
+	"sh":         sintaxisShell,
+	"bash":       sintaxisShell,
+	"shell":      sintaxisShell,
+	"zsh":        sintaxisShell,
+	"console":    sintaxisShell,
+	"sql":        sintaxisSql,
+	"yaml":       sintaxisDatos,
+	"yml":        sintaxisDatos,
+	"toml":       sintaxisDatos,
+	"json":       sintaxisDatos,
+}
+
+func esLetra(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Resalta codigo según las reglas de lenguaje (go, javascript, python, sh, sql, yaml, json...) con los roles
+// ROL_PALABRA_CLAVE, ROL_LITERAL y ROL_COMENTARIO del Tema; el resto usa ROL_CODIGO.
+// Con un lenguaje desconocido todo el código usa ROL_CODIGO. Devuelve una línea resaltada por cada línea de codigo.
+func Resaltar(codigo string, lenguaje string, t Tema) []string {
+	s := sintaxisPorLenguaje[strings.ToLower(lenguaje)]
+	lineas := strings.Split(codigo, "\n")
+	if s == nil {
+		for i, l := range lineas {
+			lineas[i] = t.Estilo(ROL_CODIGO).Aplicar(l)
+		}
+		return lineas
+	}
+	enBloque := false
+	for i, l := range lineas {
+		lineas[i], enBloque = s.resaltarLinea(l, t, enBloque)
+	}
+	return lineas
+}
+
+// Resalta una línea. enBloque indica si la línea empieza dentro de un comentario de varias líneas; se devuelve el estado al terminarla.
+func (s *sintaxis) resaltarLinea(l string, t Tema, enBloque bool) (string, bool) {
+	var sb strings.Builder
+	var normal strings.Builder
+	emitir := func(rol Rol, texto string) {
+		if normal.Len() > 0 {
+			sb.WriteString(t.Estilo(ROL_CODIGO).Aplicar(normal.String()))
+			normal.Reset()
+		}
+		sb.WriteString(t.Estilo(rol).Aplicar(texto))
+	}
+
+	i := 0
+	if enBloque {
+		fin := strings.Index(l, s.bloque[1])
+		if fin < 0 {
+			emitir(ROL_COMENTARIO, l)
+			return sb.String(), true
+		}
+		i = fin + len(s.bloque[1])
+		emitir(ROL_COMENTARIO, l[:i])
+	}
+	anterior := ' '
+	for i < len(l) {
+		resto := l[i:]
+		if s.bloque[0] != "" && strings.HasPrefix(resto, s.bloque[0]) {
+			fin := strings.Index(resto[len(s.bloque[0]):], s.bloque[1])
+			if fin < 0 {
+				emitir(ROL_COMENTARIO, resto)
+				return sb.String(), true
+			}
+			largo := len(s.bloque[0]) + fin + len(s.bloque[1])
+			emitir(ROL_COMENTARIO, resto[:largo])
+			i += largo
+			continue
+		}
+		if tienePrefijo(resto, s.comentarios) && !esLetra(anterior) {
+			emitir(ROL_COMENTARIO, resto)
+			break
+		}
+		r, largo := utf8.DecodeRuneInString(resto)
+		switch {
+		case strings.ContainsRune(s.comillas, r):
+			j := largo
+			for j < len(resto) {
+				c, n := utf8.DecodeRuneInString(resto[j:])
+				j += n
+				if c == '\\' && j < len(resto) {
+					_, n = utf8.DecodeRuneInString(resto[j:])
+					j += n
+					continue
+				}
+				if c == r {
+					break
+				}
+			}
+			emitir(ROL_LITERAL, resto[:j])
+			i += j
+			anterior = r
+			continue
+		case unicode.IsDigit(r) && !esLetra(anterior):
+			j := strings.IndexFunc(resto, func(c rune) bool { return !esLetra(c) && c != '.' })
+			if j < 0 {
+				j = len(resto)
+			}
+			emitir(ROL_LITERAL, resto[:j])
+			i += j
+			anterior = '0'
+			continue
+		case esLetra(r):
+			j := strings.IndexFunc(resto, func(c rune) bool { return !esLetra(c) })
+			if j < 0 {
+				j = len(resto)
+			}
+			palabra := resto[:j]
+			if s.esPalabraClave(palabra) {
+				emitir(ROL_PALABRA_CLAVE, palabra)
+			} else {
+				normal.WriteString(palabra)
+			}
+			i += j
+			anterior = 'a'
+			continue
+		}
+		normal.WriteRune(r)
+		anterior = r
+		i += largo
+	}
+	if normal.Len() > 0 {
+		sb.WriteString(t.Estilo(ROL_CODIGO).Aplicar(normal.String()))
+	}
+	return sb.String(), false
+}
+
+func (s *sintaxis) esPalabraClave(p string) bool {
+	for _, clave := range s.palabras {
+		if p == clave || (s.sinMayusculas && strings.EqualFold(p, clave)) {
+			return true
+		}
+	}
+	return false
+}
+
+func tienePrefijo(s string, prefijos []string) bool {
+	for _, p := range prefijos {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
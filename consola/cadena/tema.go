@@ -0,0 +1,385 @@
+package cadena
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola/color"
+	"gopkg.in/yaml.v3"
+)
+
+// Rol semántico de un fragmento de texto. Cada Tema le asigna un Estilo.
+type Rol string
+
+const (
+	ROL_TITULO        Rol = "titulo"
+	ROL_SUBTITULO     Rol = "subtitulo"
+	ROL_ENFASIS       Rol = "enfasis"
+	ROL_ATENUADO      Rol = "atenuado"
+	ROL_SUGERENCIA    Rol = "sugerencia"
+	ROL_DEBUG         Rol = "debug"
+	ROL_OK            Rol = "ok"
+	ROL_EXITO         Rol = "exito"
+	ROL_ADVERTENCIA   Rol = "advertencia"
+	ROL_ERROR         Rol = "error"
+	ROL_FATAL         Rol = "fatal"
+	ROL_SEÑALADOR     Rol = "señalador"
+	ROL_SELECCION     Rol = "seleccion"
+	ROL_ENCABEZADO    Rol = "encabezado"
+	ROL_PIE           Rol = "pie"
+	ROL_FILA_ALTERNA  Rol = "fila_alterna"
+	ROL_CODIGO        Rol = "codigo"
+	ROL_PALABRA_CLAVE Rol = "palabra_clave"
+	ROL_LITERAL       Rol = "literal"
+	ROL_COMENTARIO    Rol = "comentario"
+	ROL_ENLACE        Rol = "enlace"
+	ROL_CITA          Rol = "cita"
+)
+
+// Roles que admiten los archivos de tema y la función rol de las plantillas. Una aplicación que use roles propios
+// puede agregarlos.
+var Roles = []Rol{
+	ROL_TITULO, ROL_SUBTITULO, ROL_ENFASIS, ROL_ATENUADO, ROL_SUGERENCIA, ROL_DEBUG, ROL_OK, ROL_EXITO, ROL_ADVERTENCIA,
+	ROL_ERROR, ROL_FATAL, ROL_SEÑALADOR, ROL_SELECCION, ROL_ENCABEZADO, ROL_PIE, ROL_FILA_ALTERNA, ROL_CODIGO,
+	ROL_PALABRA_CLAVE, ROL_LITERAL, ROL_COMENTARIO, ROL_ENLACE, ROL_CITA,
+}
+
+// Devuelve un error si r no está en Roles ni tiene un estilo en el Tema.
+func (t Tema) validarRol(r Rol) error {
+	if _, existe := t.Estilos[r]; existe || slices.Contains(Roles, r) {
+		return nil
+	}
+	return fmt.Errorf("rol desconocido: %q", r)
+}
+
+// Estilo de un Rol: colores de fuente y fondo (como secuencias de escape) y atributos.
+type Estilo struct {
+	Fuente    color.ColorFuente
+	Fondo     color.ColorFondo
+	Negrita   bool
+	Tenue     bool
+	Italica   bool
+	Subrayada bool
+	Invertida bool
+	Tachada   bool
+}
+
+// Devuelve las secuencias de escape que activan el estilo.
+func (e Estilo) Secuencia() color.Color {
+	var sb strings.Builder
+	sb.WriteString(e.Fuente)
+	sb.WriteString(e.Fondo)
+	for _, a := range []struct {
+		activo bool
+		codigo string
+	}{{e.Negrita, "1"}, {e.Tenue, "2"}, {e.Italica, "3"}, {e.Subrayada, "4"}, {e.Invertida, "7"}, {e.Tachada, "9"}} {
+		if a.activo {
+			sb.WriteString("\033[" + a.codigo + "m")
+		}
+	}
+	return sb.String()
+}
+
+// Aplica el estilo a s. Un Estilo vacío devuelve s sin cambios.
+func (e Estilo) Aplicar(s string) string {
+	seq := e.Secuencia()
+	if seq == "" {
+		return s
+	}
+	return seq + s + color.Resetear
+}
+
+func (e Estilo) ConNegrita() Estilo {
+	e.Negrita = true
+	return e
+}
+
+// Interpreta un estilo escrito como palabras separadas por espacios, por ejemplo "negrita cyan" o "italica #808080 fondo:azul".
+//
+// Se aceptan los atributos negrita, tenue, italica, subrayada, invertida y tachada; los colores por nombre
+// (negro, rojo, verde, amarillo, azul, magenta, cyan, blanco, gris) o en cualquier formato de color.Interpretar;
+// y el prefijo "fondo:" para el color de fondo.
+func InterpretarEstilo(spec string) (Estilo, error) {
+	var e Estilo
+	for _, palabra := range strings.Fields(strings.ToLower(spec)) {
+		switch palabra {
+		case "negrita", "bold":
+			e.Negrita = true
+		case "tenue", "dim":
+			e.Tenue = true
+		case "italica", "itálica", "italic":
+			e.Italica = true
+		case "subrayada", "subrayado", "underline":
+			e.Subrayada = true
+		case "invertida", "invertido", "reverse":
+			e.Invertida = true
+		case "tachada", "tachado", "strikethrough":
+			e.Tachada = true
+		default:
+			nombre, fondo := strings.CutPrefix(palabra, "fondo:")
+			seq, err := secuenciaColor(nombre, fondo)
+			if err != nil {
+				return Estilo{}, fmt.Errorf("estilo %q: %w", spec, err)
+			}
+			if fondo {
+				e.Fondo = seq
+			} else {
+				e.Fuente = seq
+			}
+		}
+	}
+	return e, nil
+}
+
+var coloresNombrados = map[string][2]color.Color{
+	"negro":    {"\033[30m", color.NegroFondo},
+	"rojo":     {color.RojoFuente, color.RojoFondo},
+	"verde":    {color.VerdeFuente, color.VerdeFondo},
+	"amarillo": {color.AmarilloFuente, color.AmarilloFondo},
+	"azul":     {color.AzulFuente, color.AzulFondo},
+	"magenta":  {color.MagentaFuente, color.MagentaFondo},
+	"cyan":     {color.CyanFuente, color.CyanFondo},
+	"blanco":   {color.BlancoFuente, color.BlancoFondo},
+	"gris":     {color.GrisFuente, color.GrisFondo},
+}
+
+func secuenciaColor(nombre string, fondo bool) (color.Color, error) {
+	indice := 0
+	if fondo {
+		indice = 1
+	}
+	if seq, existe := coloresNombrados[nombre]; existe {
+		return seq[indice], nil
+	}
+	c, err := color.Interpretar(nombre)
+	if err != nil {
+		return "", err
+	}
+	if fondo {
+		return c.Fondo(), nil
+	}
+	return c.Fuente(), nil
+}
+
+// Tema asigna un Estilo a cada Rol. Los roles sin estilo se presentan sin formato.
+type Tema struct {
+	Nombre  string
+	Estilos map[Rol]Estilo
+}
+
+func (t Tema) Estilo(r Rol) Estilo {
+	return t.Estilos[r]
+}
+
+// Aplica a s el estilo del rol r.
+func (t Tema) Aplicar(r Rol, s string) Cadena {
+	return Cadena(t.Estilo(r).Aplicar(s))
+}
+
+// Devuelve un Formateador que aplica el estilo del rol r.
+func (t Tema) Formateador(r Rol) Formateador {
+	return t.Estilo(r).Aplicar
+}
+
+// Devuelve una copia del tema con los estilos de otro superpuestos.
+func (t Tema) Combinar(otro Tema) Tema {
+	combinado := Tema{Nombre: t.Nombre, Estilos: make(map[Rol]Estilo, len(t.Estilos))}
+	for r, e := range t.Estilos {
+		combinado.Estilos[r] = e
+	}
+	for r, e := range otro.Estilos {
+		combinado.Estilos[r] = e
+	}
+	if otro.Nombre != "" {
+		combinado.Nombre = otro.Nombre
+	}
+	return combinado
+}
+
+func (t Tema) Titulo(s string) Cadena {
+	return t.Aplicar(ROL_TITULO, s) + "\n"
+}
+func (t Tema) Subtitulo(s string) Cadena {
+	return t.Aplicar(ROL_SUBTITULO, s) + "\n"
+}
+func (t Tema) Señalador(s string) Cadena {
+	return t.Aplicar(ROL_SEÑALADOR, s) + " "
+}
+func (t Tema) Sugerencia(msg string) string {
+	return t.Aplicar(ROL_SUGERENCIA, msg+".").S() + "\n"
+}
+func (t Tema) Debug(msg string, err error) string {
+	return t.etiquetado(ROL_DEBUG, ROL_DEBUG, "[DEBUG]", msg, err)
+}
+func (t Tema) Ok(msg string) string {
+	return t.Aplicar(ROL_OK, msg+".").S() + "\n"
+}
+func (t Tema) Exito(msg string) string {
+	return t.Aplicar(ROL_EXITO, msg+".").S() + "\n"
+}
+func (t Tema) Advertencia(msg string, err error) string {
+	return t.etiquetado(ROL_ADVERTENCIA, ROL_ADVERTENCIA, "[ADVERTENCIA]", msg, err)
+}
+func (t Tema) Error(msg string, err error) string {
+	return t.etiquetado(ROL_ERROR, ROL_ERROR, "[ERROR]", msg, err)
+}
+func (t Tema) Fatal(msg string, err error) string {
+	return t.etiquetado(ROL_FATAL, ROL_ERROR, "[FATAL]", msg, err)
+}
+
+// Etiqueta en negrita con el estilo rolEtiqueta, seguida del mensaje con el estilo rolMensaje.
+func (t Tema) etiquetado(rolEtiqueta Rol, rolMensaje Rol, etiqueta string, msg string, err error) string {
+	return t.Estilo(rolEtiqueta).ConNegrita().Aplicar(etiqueta) + t.Estilo(rolMensaje).Aplicar(fmt.Sprintf("\t%s. err: %v.", msg, err)) + "\n"
+}
+
+// Tema por defecto, pensado para terminales de fondo oscuro.
+var TemaOscuro = Tema{
+	Nombre: "oscuro",
+	Estilos: map[Rol]Estilo{
+		ROL_TITULO:        {Fuente: color.CyanFuente, Negrita: true},
+		ROL_SUBTITULO:     {Fuente: color.GrisFuente, Negrita: true, Italica: true},
+		ROL_ENFASIS:       {Negrita: true},
+		ROL_ATENUADO:      {Fuente: color.GrisFuente},
+		ROL_SUGERENCIA:    {Fuente: color.GrisFuente, Italica: true},
+		ROL_DEBUG:         {Fuente: color.GrisFuente},
+		ROL_OK:            {Fuente: color.VerdeFuente},
+		ROL_EXITO:         {Fondo: color.VerdeFondo, Negrita: true},
+		ROL_ADVERTENCIA:   {Fuente: color.AmarilloFuente},
+		ROL_ERROR:         {Fuente: color.RojoFuente},
+		ROL_FATAL:         {Fondo: color.RojoFondo},
+		ROL_SEÑALADOR:     {Fondo: color.CyanFondo},
+		ROL_SELECCION:     {Fondo: color.CyanFondo, Negrita: true},
+		ROL_ENCABEZADO:    {Fuente: color.CyanFuente, Negrita: true},
+		ROL_PIE:           {Negrita: true},
+		ROL_FILA_ALTERNA:  {Fondo: color.RGB{R: 38, G: 38, B: 38}.Fondo()},
+		ROL_CODIGO:        {Fuente: color.RGB{R: 230, G: 160, B: 100}.Fuente()},
+		ROL_PALABRA_CLAVE: {Fuente: color.MagentaFuente, Negrita: true},
+		ROL_LITERAL:       {Fuente: color.VerdeFuente},
+		ROL_COMENTARIO:    {Fuente: color.GrisFuente, Italica: true},
+		ROL_ENLACE:        {Fuente: color.AzulFuente, Subrayada: true},
+		ROL_CITA:          {Fuente: color.GrisFuente, Italica: true},
+	},
+}
+
+// Tema para terminales de fondo claro: colores más oscuros y grises legibles sobre blanco.
+var TemaClaro = Tema{
+	Nombre: "claro",
+	Estilos: map[Rol]Estilo{
+		ROL_TITULO:        {Fuente: color.AzulFuente, Negrita: true},
+		ROL_SUBTITULO:     {Fuente: color.RGB{R: 90, G: 90, B: 90}.Fuente(), Negrita: true, Italica: true},
+		ROL_ENFASIS:       {Negrita: true},
+		ROL_ATENUADO:      {Fuente: color.RGB{R: 120, G: 120, B: 120}.Fuente()},
+		ROL_SUGERENCIA:    {Fuente: color.RGB{R: 120, G: 120, B: 120}.Fuente(), Italica: true},
+		ROL_DEBUG:         {Fuente: color.RGB{R: 120, G: 120, B: 120}.Fuente()},
+		ROL_OK:            {Fuente: color.RGB{R: 0, G: 128, B: 0}.Fuente()},
+		ROL_EXITO:         {Fuente: color.BlancoFuente, Fondo: color.RGB{R: 0, G: 128, B: 0}.Fondo(), Negrita: true},
+		ROL_ADVERTENCIA:   {Fuente: color.RGB{R: 175, G: 95, B: 0}.Fuente()},
+		ROL_ERROR:         {Fuente: color.RGB{R: 175, G: 0, B: 0}.Fuente()},
+		ROL_FATAL:         {Fuente: color.BlancoFuente, Fondo: color.RGB{R: 175, G: 0, B: 0}.Fondo()},
+		ROL_SEÑALADOR:     {Fuente: color.BlancoFuente, Fondo: color.AzulFondo},
+		ROL_SELECCION:     {Fuente: color.BlancoFuente, Fondo: color.AzulFondo, Negrita: true},
+		ROL_ENCABEZADO:    {Fuente: color.AzulFuente, Negrita: true},
+		ROL_PIE:           {Negrita: true},
+		ROL_FILA_ALTERNA:  {Fondo: color.RGB{R: 235, G: 235, B: 235}.Fondo()},
+		ROL_CODIGO:        {Fuente: color.RGB{R: 160, G: 60, B: 0}.Fuente()},
+		ROL_PALABRA_CLAVE: {Fuente: color.RGB{R: 128, G: 0, B: 128}.Fuente(), Negrita: true},
+		ROL_LITERAL:       {Fuente: color.RGB{R: 0, G: 128, B: 0}.Fuente()},
+		ROL_COMENTARIO:    {Fuente: color.RGB{R: 120, G: 120, B: 120}.Fuente(), Italica: true},
+		ROL_ENLACE:        {Fuente: color.AzulFuente, Subrayada: true},
+		ROL_CITA:          {Fuente: color.RGB{R: 90, G: 90, B: 90}.Fuente(), Italica: true},
+	},
+}
+
+// Tema sin colores: distingue los roles sólo con atributos.
+var TemaMonocromo = Tema{
+	Nombre: "monocromo",
+	Estilos: map[Rol]Estilo{
+		ROL_TITULO:        {Negrita: true, Subrayada: true},
+		ROL_SUBTITULO:     {Negrita: true, Italica: true},
+		ROL_ENFASIS:       {Negrita: true},
+		ROL_ATENUADO:      {Tenue: true},
+		ROL_SUGERENCIA:    {Tenue: true, Italica: true},
+		ROL_DEBUG:         {Tenue: true},
+		ROL_OK:            {},
+		ROL_EXITO:         {Negrita: true},
+		ROL_ADVERTENCIA:   {Negrita: true},
+		ROL_ERROR:         {Negrita: true},
+		ROL_FATAL:         {Invertida: true},
+		ROL_SEÑALADOR:     {Invertida: true},
+		ROL_SELECCION:     {Invertida: true, Negrita: true},
+		ROL_ENCABEZADO:    {Negrita: true},
+		ROL_PIE:           {Negrita: true},
+		ROL_FILA_ALTERNA:  {Tenue: true},
+		ROL_CODIGO:        {},
+		ROL_PALABRA_CLAVE: {Negrita: true},
+		ROL_LITERAL:       {},
+		ROL_COMENTARIO:    {Tenue: true},
+		ROL_ENLACE:        {Subrayada: true},
+		ROL_CITA:          {Italica: true},
+	},
+}
+
+// Tema usado por las funciones del paquete (Titulo, Error, Advertencia...) y por las Consolas nuevas.
+var TemaPredeterminado = TemaOscuro
+
+// Temas incluidos, por nombre.
+var Temas = map[string]Tema{
+	TemaOscuro.Nombre:    TemaOscuro,
+	TemaClaro.Nombre:     TemaClaro,
+	TemaMonocromo.Nombre: TemaMonocromo,
+}
+
+type archivoTema struct {
+	Nombre  string            `yaml:"nombre"`
+	Base    string            `yaml:"base"`
+	Estilos map[string]string `yaml:"estilos"`
+}
+
+// Carga un tema desde un archivo YAML o JSON. Los roles no indicados se toman del tema base (oscuro si no se indica).
+//
+// # Ejemplo:
+//
+//	nombre: mi-tema
+//	base: claro
+//	estilos:
+//	  titulo: "negrita #1e90ff"
+//	  seleccion: "negrita blanco fondo:magenta"
+//
+// Los colores hexadecimales deben ir entre comillas, ya que '#' inicia un comentario en YAML.
+func CargarTema(ruta string) (Tema, error) {
+	datos, err := os.ReadFile(ruta)
+	if err != nil {
+		return Tema{}, err
+	}
+	return InterpretarTema(datos)
+}
+
+// Interpreta un tema en YAML o JSON. Ver CargarTema.
+func InterpretarTema(datos []byte) (Tema, error) {
+	var a archivoTema
+	if err := yaml.Unmarshal(datos, &a); err != nil {
+		return Tema{}, fmt.Errorf("tema inválido: %w", err)
+	}
+	base := TemaPredeterminado
+	if a.Base != "" {
+		b, existe := Temas[a.Base]
+		if !existe {
+			return Tema{}, fmt.Errorf("tema base desconocido: %q", a.Base)
+		}
+		base = b
+	}
+	propio := Tema{Nombre: a.Nombre, Estilos: make(map[Rol]Estilo, len(a.Estilos))}
+	for rol, spec := range a.Estilos {
+		if err := base.validarRol(Rol(rol)); err != nil {
+			return Tema{}, fmt.Errorf("tema inválido: %w", err)
+		}
+		e, err := InterpretarEstilo(spec)
+		if err != nil {
+			return Tema{}, err
+		}
+		propio.Estilos[Rol(rol)] = e
+	}
+	return base.Combinar(propio), nil
+}
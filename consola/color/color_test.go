@@ -0,0 +1,56 @@
+package color_test
+
+import (
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola/color"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDetectarNivel prueba la precedencia de las variables de entorno
+func TestDetectarNivel(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	t.Setenv("CLICOLOR", "")
+	t.Setenv("TERM", "xterm")
+
+	assert.Equal(t, color.SIN_ESTILOS, color.DetectarNivel(false))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.Equal(t, color.SIN_COLOR, color.DetectarNivel(true))
+	assert.Equal(t, color.SIN_ESTILOS, color.DetectarNivel(false))
+
+	t.Setenv("FORCE_COLOR", "2")
+	assert.Equal(t, color.COLOR_256, color.DetectarNivel(false))
+
+	t.Setenv("FORCE_COLOR", "0")
+	t.Setenv("TERM", "dumb")
+	assert.Equal(t, color.SIN_ESTILOS, color.DetectarNivel(true))
+}
+
+// TestModeloColor prueba las conversiones entre hexadecimal, RGB, HSL y la paleta de 256
+func TestModeloColor(t *testing.T) {
+	c, err := color.Hex("#1e90ff")
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGB{R: 30, G: 144, B: 255}, c)
+	assert.Equal(t, "#1e90ff", c.Hex())
+	assert.Equal(t, c, c.HSL().RGB())
+
+	_, err = color.Hex("#12")
+	assert.Error(t, err)
+
+	c, err = color.Interpretar("rgb(255, 135, 0)")
+	assert.NoError(t, err)
+	assert.Equal(t, color.Indice(208), c.Indice256())
+	assert.Equal(t, color.RGB{R: 255, G: 135, B: 0}, color.Indice(208).RGB())
+	assert.Equal(t, color.Indice(244), color.RGB{R: 128, G: 128, B: 128}.Indice256())
+	assert.Equal(t, "\x1b[38;5;208m", color.FuenteNivel(c, color.COLOR_256))
+
+	c, err = color.Interpretar("rgb(100%, 50%, 0%)")
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGB{R: 255, G: 128, B: 0}, c)
+	c, err = color.Interpretar("hsl(0, 100%, 50%)")
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGB{R: 255, G: 0, B: 0}, c)
+}
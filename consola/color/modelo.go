@@ -0,0 +1,282 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color de 24 bits.
+type RGB struct {
+	R, G, B uint8
+}
+
+// Color en el espacio Matiz (0-360), Saturación (0-1), Luminosidad (0-1).
+type HSL struct {
+	H, S, L float64
+}
+
+// Índice de la paleta xterm de 256 colores: 0-15 colores básicos, 16-231 cubo 6x6x6, 232-255 escala de grises.
+type Indice uint8
+
+// Valores por defecto de xterm para los 16 colores básicos.
+var paleta16 = [16]RGB{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// Niveles de cada componente en el cubo 6x6x6 de la paleta de 256 colores.
+var nivelesCubo = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// Interpreta un color hexadecimal: "#rgb", "#rrggbb", con o sin "#".
+func Hex(s string) (RGB, error) {
+	h := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	if len(h) != 6 {
+		return RGB{}, fmt.Errorf("color hexadecimal inválido: %q", s)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("color hexadecimal inválido: %q", s)
+	}
+	return RGB{uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// Como Hex, pero entra en pánico si s no es un color válido. Pensado para variables de paquete.
+func DebeHex(s string) RGB {
+	c, err := Hex(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Interpreta un color como hexadecimal ("#1e90ff"), "rgb(30,144,255)", "hsl(210,1,0.56)" o índice de la paleta de 256 ("208").
+// Los componentes se pueden dar en porcentajes: "rgb(100%,50%,0%)" o "hsl(210,100%,56%)".
+func Interpretar(s string) (RGB, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	// maximo es el valor de un componente al 100%: 255 en rgb, 1 en hsl.
+	componentes := func(prefijo string, maximo float64) ([]float64, error) {
+		partes := strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, prefijo+"("), ")"), ",")
+		if len(partes) != 3 {
+			return nil, fmt.Errorf("color inválido: %q", s)
+		}
+		v := make([]float64, 3)
+		for i, p := range partes {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(p), "%"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("color inválido: %q", s)
+			}
+			if strings.HasSuffix(strings.TrimSpace(p), "%") {
+				f = f * maximo / 100
+			}
+			v[i] = f
+		}
+		return v, nil
+	}
+	switch {
+	case strings.HasPrefix(s, "rgb("):
+		v, err := componentes("rgb", 255)
+		if err != nil {
+			return RGB{}, err
+		}
+		return RGB{acotar(v[0]), acotar(v[1]), acotar(v[2])}, nil
+	case strings.HasPrefix(s, "hsl("):
+		v, err := componentes("hsl", 1)
+		if err != nil {
+			return RGB{}, err
+		}
+		return HSL{v[0], v[1], v[2]}.RGB(), nil
+	}
+	if i, err := strconv.ParseUint(s, 10, 8); err == nil {
+		return Indice(i).RGB(), nil
+	}
+	return Hex(s)
+}
+
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func (c RGB) HSL() HSL {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	maximo, minimo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l := (maximo + minimo) / 2
+	if maximo == minimo {
+		return HSL{0, 0, l}
+	}
+	d := maximo - minimo
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch maximo {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return HSL{h, s, l}
+}
+
+func (h HSL) RGB() RGB {
+	c := (1 - math.Abs(2*h.L-1)) * h.S
+	hp := math.Mod(h.H, 360) / 60
+	if hp < 0 {
+		hp += 6
+	}
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g, b = c, x, 0
+	case hp < 2:
+		r, g, b = x, c, 0
+	case hp < 3:
+		r, g, b = 0, c, x
+	case hp < 4:
+		r, g, b = 0, x, c
+	case hp < 5:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	m := h.L - c/2
+	return RGB{acotar((r + m) * 255), acotar((g + m) * 255), acotar((b + m) * 255)}
+}
+
+// Devuelve el color con la luminosidad desplazada en delta (-1 a 1).
+func (c RGB) Aclarar(delta float64) RGB {
+	h := c.HSL()
+	h.L = math.Max(0, math.Min(1, h.L+delta))
+	return h.RGB()
+}
+
+func acotar(v float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(v))))
+}
+
+func (i Indice) RGB() RGB {
+	switch {
+	case i < 16:
+		return paleta16[i]
+	case i < 232:
+		n := int(i) - 16
+		return RGB{nivelesCubo[n/36], nivelesCubo[(n/6)%6], nivelesCubo[n%6]}
+	}
+	g := uint8(8 + 10*(int(i)-232))
+	return RGB{g, g, g}
+}
+
+// Distancia perceptual aproximada ("redmean") entre dos colores.
+func distancia(a, b RGB) float64 {
+	rm := (float64(a.R) + float64(b.R)) / 2
+	dr, dg, db := float64(a.R)-float64(b.R), float64(a.G)-float64(b.G), float64(a.B)-float64(b.B)
+	return (2+rm/256)*dr*dr + 4*dg*dg + (2+(255-rm)/256)*db*db
+}
+
+// Índice más cercano dentro del cubo y la escala de grises de la paleta de 256 (se omiten los 16 básicos, que cada terminal redefine).
+func (c RGB) Indice256() Indice {
+	cercano := func(v uint8) int {
+		mejor := 0
+		for i, n := range nivelesCubo {
+			if math.Abs(float64(n)-float64(v)) < math.Abs(float64(nivelesCubo[mejor])-float64(v)) {
+				mejor = i
+			}
+		}
+		return mejor
+	}
+	cubo := Indice(16 + 36*cercano(c.R) + 6*cercano(c.G) + cercano(c.B))
+
+	promedio := (int(c.R) + int(c.G) + int(c.B)) / 3
+	gris := Indice(232 + max(0, min(23, (promedio-3)/10)))
+
+	if distancia(c, gris.RGB()) < distancia(c, cubo.RGB()) {
+		return gris
+	}
+	return cubo
+}
+
+// Índice (0-15) del color básico más cercano.
+func (c RGB) Indice16() Indice {
+	var mejor Indice
+	for i, p := range paleta16 {
+		if distancia(c, p) < distancia(c, paleta16[mejor]) {
+			mejor = Indice(i)
+		}
+	}
+	return mejor
+}
+
+// Secuencia de color de fuente de 24 bits.
+func (c RGB) Fuente() ColorFuente {
+	return ColorFuente(fmt.Sprintf("\033[38;2;%d;%d;%dm", c.R, c.G, c.B))
+}
+
+// Secuencia de color de fondo de 24 bits.
+func (c RGB) Fondo() ColorFondo {
+	return ColorFondo(fmt.Sprintf("\033[48;2;%d;%d;%dm", c.R, c.G, c.B))
+}
+
+// Secuencia de color de fuente de la paleta de 256 (los índices 0-15 usan los códigos básicos).
+func (i Indice) Fuente() ColorFuente {
+	if i < 16 {
+		return ColorFuente(fmt.Sprintf("\033[%dm", CodigoBasico(i, false)))
+	}
+	return ColorFuente(fmt.Sprintf("\033[38;5;%dm", i))
+}
+
+// Secuencia de color de fondo de la paleta de 256 (los índices 0-15 usan los códigos básicos).
+func (i Indice) Fondo() ColorFondo {
+	if i < 16 {
+		return ColorFondo(fmt.Sprintf("\033[%dm", CodigoBasico(i, true)))
+	}
+	return ColorFondo(fmt.Sprintf("\033[48;5;%dm", i))
+}
+
+// Código SGR de uno de los 16 colores básicos: 30-37/90-97 para la fuente, 40-47/100-107 para el fondo.
+func CodigoBasico(i Indice, fondo bool) int {
+	base := 30
+	if fondo {
+		base = 40
+	}
+	if i >= 8 {
+		return base + 60 + int(i-8)
+	}
+	return base + int(i)
+}
+
+// Secuencia de color de fuente para c adaptada al nivel: 24 bits, índice de 256, color básico o ninguna.
+func FuenteNivel(c RGB, n Nivel) ColorFuente {
+	switch {
+	case n >= COLOR_VERDADERO:
+		return c.Fuente()
+	case n == COLOR_256:
+		return c.Indice256().Fuente()
+	case n == COLOR_16:
+		return c.Indice16().Fuente()
+	}
+	return ""
+}
+
+// Secuencia de color de fondo para c adaptada al nivel: 24 bits, índice de 256, color básico o ninguna.
+func FondoNivel(c RGB, n Nivel) ColorFondo {
+	switch {
+	case n >= COLOR_VERDADERO:
+		return c.Fondo()
+	case n == COLOR_256:
+		return c.Indice256().Fondo()
+	case n == COLOR_16:
+		return c.Indice16().Fondo()
+	}
+	return ""
+}
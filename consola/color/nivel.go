@@ -0,0 +1,98 @@
+package color
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Nivel de soporte de estilos y colores de una salida.
+type Nivel int
+
+const (
+	SIN_ESTILOS     Nivel = iota // Sin secuencias de escape: archivos, tuberías, TERM=dumb
+	SIN_COLOR                    // Negrita, itálica, subrayado... pero sin colores (NO_COLOR)
+	COLOR_16                     // Los 16 colores ANSI básicos
+	COLOR_256                    // Paleta xterm de 256 colores
+	COLOR_VERDADERO              // Color de 24 bits
+)
+
+func (n Nivel) String() string {
+	switch n {
+	case SIN_ESTILOS:
+		return "sin estilos"
+	case SIN_COLOR:
+		return "sin color"
+	case COLOR_16:
+		return "16 colores"
+	case COLOR_256:
+		return "256 colores"
+	case COLOR_VERDADERO:
+		return "color verdadero"
+	}
+	return "desconocido"
+}
+
+// Determina el Nivel de una salida según el entorno, con el siguiente orden de precedencia:
+//   - FORCE_COLOR (no vacía): "0"/"false" desactiva el color; "1", "2" y "3" fuerzan 16, 256 y color verdadero; cualquier otro valor fuerza 16 colores.
+//   - NO_COLOR (no vacía): desactiva el color, conservando los estilos si la salida es una terminal.
+//   - CLICOLOR_FORCE (distinta de "0"): fuerza el color aunque la salida no sea una terminal.
+//   - TERM=dumb o una salida que no es terminal: sin estilos.
+//   - CLICOLOR=0: desactiva el color.
+//
+// La paleta de una terminal con color se determina con COLORTERM y TERM (ver detectarPaleta).
+func DetectarNivel(esTerminal bool) Nivel {
+	if forzar := os.Getenv("FORCE_COLOR"); forzar != "" {
+		switch strings.ToLower(forzar) {
+		case "0", "false":
+			return nivelSinColor(esTerminal)
+		case "2":
+			return COLOR_256
+		case "3":
+			return COLOR_VERDADERO
+		default:
+			return COLOR_16
+		}
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return nivelSinColor(esTerminal)
+	}
+	if f := os.Getenv("CLICOLOR_FORCE"); f != "" && f != "0" {
+		return detectarPaleta()
+	}
+	if !esTerminal || os.Getenv("TERM") == "dumb" {
+		return SIN_ESTILOS
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return SIN_COLOR
+	}
+	return detectarPaleta()
+}
+
+func nivelSinColor(esTerminal bool) Nivel {
+	if !esTerminal || os.Getenv("TERM") == "dumb" {
+		return SIN_ESTILOS
+	}
+	return SIN_COLOR
+}
+
+// Paleta disponible en una terminal con color:
+//   - COLORTERM=truecolor|24bit, TERM terminado en -direct o -truecolor, o Windows Terminal: color verdadero;
+//   - TERM o COLORTERM con "256": 256 colores;
+//   - en cualquier otro caso: 16 colores.
+func detectarPaleta() Nivel {
+	colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+	terminal := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case colorterm == "truecolor" || colorterm == "24bit",
+		strings.HasSuffix(terminal, "-direct"), strings.HasSuffix(terminal, "truecolor"),
+		os.Getenv("WT_SESSION") != "":
+		return COLOR_VERDADERO
+	case strings.Contains(terminal, "256"), strings.Contains(colorterm, "256"):
+		return COLOR_256
+	case runtime.GOOS == "windows" && terminal == "":
+		// La consola de Windows 10+ con ENABLE_VIRTUAL_TERMINAL_PROCESSING admite color de 24 bits.
+		return COLOR_VERDADERO
+	}
+	return COLOR_16
+}
@@ -14,8 +14,12 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/color"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
 
 	"github.com/schollz/progressbar/v3"
 	"golang.org/x/sys/windows"
@@ -34,12 +38,18 @@ type Entrada struct {
 	*bufio.Reader
 	f          *os.File
 	esTerminal bool
+	eventos    *teclado.Decodificador
+	edicion    *edicion
 }
 
 type Salida struct {
 	*bufio.Writer
 	f          *os.File
 	esTerminal bool
+	nivel      color.Nivel
+	paginado   *paginado
+	raton      *estadoRaton
+	mu         *sync.Mutex // Serializa las escrituras de todas las copias de la Salida (ver bloquear)
 }
 
 type EntradaSalida struct {
@@ -78,10 +88,19 @@ type Consola interface {
 
 type consola struct {
 	EntradaSalida
+	tema        cadena.Tema
+	diagnostico *Salida // Ver SalidaDiagnostico
 }
 
+// Lee una línea de la Entrada. Si la entrada y la salida son terminales, la línea se puede editar con las teclas del
+// perfil elegido (ver MAPA_EMACS y MAPA_VI) y ^C devuelve ERROR_INTERRUMPIDO. El texto pegado conserva sus saltos de
+// línea, así que la línea devuelta puede tener varias.
 func (c consola) Leer(mensaje Cadena) (Cadena, error) {
-	c.ImprimirCadena(cadena.Señalador(">") + mensaje + Cadena(": "))
+	indicador := c.tema.Señalador(">") + mensaje + Cadena(": ")
+	if c.Entrada.esTerminal && c.Salida.esTerminal {
+		return c.editarLinea(indicador.S())
+	}
+	c.ImprimirCadena(indicador)
 	s, err := c.Entrada.ReadString('\n')
 	if err != nil {
 		return Cadena("\n"), err
@@ -90,8 +109,13 @@ func (c consola) Leer(mensaje Cadena) (Cadena, error) {
 	return Cadena(s).Limpiar(), nil
 }
 
+// Como Leer, con el prefijo p en el indicador.
 func (c consola) LeerPrefijo(p Cadena, mensaje Cadena) (Cadena, error) {
-	c.ImprimirCadena(cadena.Señalador("> ("+p.S()+")") + mensaje + Cadena(": "))
+	indicador := c.tema.Señalador("> ("+p.S()+")") + mensaje + Cadena(": ")
+	if c.Entrada.esTerminal && c.Salida.esTerminal {
+		return c.editarLinea(indicador.S())
+	}
+	c.ImprimirCadena(indicador)
 	s, err := c.Entrada.ReadString('\n')
 	if err != nil {
 		return Cadena("\n"), err
@@ -101,7 +125,7 @@ func (c consola) LeerPrefijo(p Cadena, mensaje Cadena) (Cadena, error) {
 }
 
 func (c consola) LeerContraseña(mensaje Cadena) (Cadena, error) {
-	c.ImprimirCadena(cadena.Señalador(">") + mensaje + Cadena(": "))
+	c.ImprimirCadena(c.tema.Señalador(">") + mensaje + Cadena(": "))
 	viejo, _ := term.MakeRaw(int(c.EntradaSalida.Entrada.Fd()))
 	t := term.NewTerminal(c.EntradaSalida, "")
 	defer term.Restore(int(c.EntradaSalida.Entrada.Fd()), viejo)
@@ -109,10 +133,22 @@ func (c consola) LeerContraseña(mensaje Cadena) (Cadena, error) {
 	return Cadena(contraseña), err
 }
 
+// Lee en modo crudo los bytes de la próxima tecla, sin decodificar. Devuelve primero los que LeerEvento haya dejado pendientes.
 func (c consola) LeerTecla(b *[]byte) (int, error) {
-	viejo, _ := term.MakeRaw(int(c.EntradaSalida.Entrada.Fd()))
-	defer term.Restore(int(c.EntradaSalida.Entrada.Fd()), viejo)
-	return c.Entrada.f.Read(*b)
+	if viejo, err := term.MakeRaw(int(c.EntradaSalida.Entrada.Fd())); err == nil {
+		defer term.Restore(int(c.EntradaSalida.Entrada.Fd()), viejo)
+	}
+	return c.Entrada.eventos.LeerBytes(*b)
+}
+
+// Lee la próxima tecla de la Entrada en modo crudo, decodificando secuencias de escape y modificadores.
+//
+// Los bytes leídos de más quedan pendientes para la próxima llamada (o para LeerTecla).
+func (c consola) LeerEvento() (teclado.Tecla, error) {
+	if viejo, err := term.MakeRaw(int(c.EntradaSalida.Entrada.Fd())); err == nil {
+		defer term.Restore(int(c.EntradaSalida.Entrada.Fd()), viejo)
+	}
+	return c.Entrada.eventos.Leer()
 }
 
 // Llama Flush() en la Salida subyacente
@@ -129,21 +165,21 @@ func (c consola) ImprimirCadena(cadena Cadena) error {
 
 // Escribe la Cadena al buffer, la formatea como Advertencia y llama Imprimir()
 func (c consola) ImprimirAdvertencia(ca Cadena, e error) error {
-	err1 := c.EscribirCadena(cadena.Cadena(cadena.Advertencia(ca.S(), e)))
+	err1 := c.EscribirCadena(cadena.Cadena(c.tema.Advertencia(ca.S(), e)))
 	err2 := c.Imprimir()
 	return errors.Join(err1, err2)
 }
 
 // Escribe la Cadena al buffer, la formatea como Error y llama Imprimir()
 func (c consola) ImprimirError(ca Cadena, e error) error {
-	err1 := c.EscribirCadena(cadena.Cadena(cadena.Error(ca.S(), e)))
+	err1 := c.EscribirCadena(cadena.Cadena(c.tema.Error(ca.S(), e)))
 	err2 := c.Imprimir()
 	return errors.Join(err1, err2)
 }
 
 // Escribe la Cadena al buffer, la formatea como Fatal y llama Imprimir()
 func (c consola) ImprimirFatal(ca Cadena, e error) error {
-	err1 := c.EscribirCadena(cadena.Cadena(cadena.Fatal(ca.S(), e)))
+	err1 := c.EscribirCadena(cadena.Cadena(c.tema.Fatal(ca.S(), e)))
 	err2 := c.Imprimir()
 	return errors.Join(err1, err2)
 }
@@ -164,39 +200,44 @@ func (c consola) ImprimirBytes(b []byte) error {
 
 // Escribe la Cadena al buffer
 func (c consola) EscribirCadena(cadena Cadena) error {
-	_, err := c.Salida.WriteString(cadena.S())
-	return err
+	return c.Salida.escribirCadena(cadena.S())
 }
 
 // Escribe la Cadena +\r\n al buffer
 func (c consola) EscribirLinea(cadena Cadena) error {
-	_, err := c.Salida.WriteString(cadena.S() + "\r\n")
-	return err
+	return c.Salida.escribirCadena(cadena.S() + "\r\n")
 }
 
 // Escribe los bytes al buffer
 func (c consola) EscribirBytes(b []byte) error {
-	_, err := c.Salida.Write(b)
+	_, err := c.Salida.escribir(b)
 	return err
 }
 
 func (c consola) EsTerminal() bool {
-	return true
+	return c.EntradaSalida.esTerminal
 }
 func NuevaEntrada(f *os.File) *Entrada {
 	return &Entrada{
 		bufio.NewReader(f),
 		f,
 		term.IsTerminal(int(f.Fd())),
+		nuevoDecodificador(f),
+		&edicion{},
 	}
 }
 
 // Crea una *Salida a partir de un *os.File
 func NuevaSalida(f *os.File) *Salida {
+	esTerminal := term.IsTerminal(int(f.Fd()))
 	return &Salida{
 		bufio.NewWriter(f),
 		f,
-		term.IsTerminal(int(f.Fd())),
+		esTerminal,
+		color.DetectarNivel(esTerminal),
+		&paginado{},
+		&estadoRaton{},
+		&sync.Mutex{},
 	}
 }
 
@@ -211,10 +252,34 @@ func NuevaSalida(f *os.File) *Salida {
 // Todas las operaciones de escritura se duplicaran entre buf y os.Stdout,
 // pero se considerará os.Stdout como la salida subyacente autoritativa para todos los procedimientos que dependen de Salida.f
 func NuevaMultiSalida(w *bufio.Writer, f *os.File) *Salida {
+	esTerminal := term.IsTerminal(int(f.Fd()))
 	return &Salida{
 		w,
 		f,
+		esTerminal,
+		color.DetectarNivel(esTerminal),
+		&paginado{},
+		&estadoRaton{},
+		&sync.Mutex{},
+	}
+}
+
+// Crea una *Entrada a partir de un *bufio.Reader y un *os.File (pensado para utilizar con io.TeeReader declarando el File autoritativo)
+//
+// # Ejemplo:
+//
+//	var buf bytes.Buffer
+//	lector := bufio.NewReader(io.TeeReader(os.Stdin, &buf))
+//	multiEntrada := NuevaMultiEntrada(lector, os.Stdin)
+//
+// Todo lo leído se copiará a buf, pero se considerará os.Stdin como la entrada subyacente autoritativa para todos los procedimientos que dependen de Entrada.f
+func NuevaMultiEntrada(r *bufio.Reader, f *os.File) *Entrada {
+	return &Entrada{
+		r,
+		f,
 		term.IsTerminal(int(f.Fd())),
+		nuevoDecodificador(f),
+		&edicion{},
 	}
 }
 
@@ -240,6 +305,8 @@ func NuevaConsola(fe *os.File, fs *os.File) *consola {
 	}
 	return &consola{
 		EntradaSalida: *NuevaEntradaSalida(fe, fs),
+		tema:          cadena.TemaPredeterminado,
+		diagnostico:   NuevaSalida(os.Stderr),
 	}
 }
 
@@ -307,7 +374,7 @@ func (s Salida) ImprimirSeparador() {
 }
 
 func (s *Salida) EscribirCadena(c Cadena) error {
-	_, err := s.WriteString(c.S())
+	err := s.escribirCadena(c.S())
 	if err != nil {
 		return err
 	}
@@ -334,7 +401,7 @@ func Separador(f *os.File) string {
 }
 
 func ImprimirCadena(c Cadena, s *Salida) error {
-	_, err := s.WriteString(c.S())
+	err := s.escribirCadena(c.S())
 
 	if err != nil {
 		return err
@@ -355,7 +422,7 @@ func (c consola) Read(p []byte) (n int, err error) {
 	return c.Entrada.Read(p)
 }
 func (c consola) Write(p []byte) (n int, err error) {
-	return c.Salida.Write(p)
+	return c.Salida.escribir(p)
 }
 
 func (c consola) FEntrada() *os.File {
@@ -364,3 +431,19 @@ func (c consola) FEntrada() *os.File {
 func (c consola) FSalida() *os.File {
 	return c.Salida.f
 }
+
+// Devuelve el ancho y el alto de la terminal de salida (ver Salida.DevolverTamaño).
+func (c consola) DevolverTamaño() (int, int, error) {
+	return c.Salida.DevolverTamaño()
+}
+
+// Decodificador de teclas que lee directamente de f, sin pasar por el *bufio.Reader de la Entrada.
+func nuevoDecodificador(f *os.File) *teclado.Decodificador {
+	return teclado.NuevoDecodificador(f.Read, func(espera time.Duration) bool { return hayEntrada(f, espera) })
+}
+
+// Devuelve true si hay bytes para leer de f antes de que pase espera.
+func hayEntrada(f *os.File, espera time.Duration) bool {
+	evento, err := windows.WaitForSingleObject(windows.Handle(f.Fd()), uint32(espera.Milliseconds()))
+	return err == nil && evento == windows.WAIT_OBJECT_0
+}
@@ -0,0 +1,26 @@
+package consola
+
+import (
+	"os"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+)
+
+// Devuelve la Salida para mensajes de diagnóstico, progreso y pasos que no deben mezclarse con el resultado (por
+// ejemplo, cuando este se presenta en un formato de máquina). Por defecto, os.Stderr.
+func (c consola) SalidaDiagnostico() *Salida {
+	if c.diagnostico == nil {
+		return NuevaSalida(os.Stderr)
+	}
+	return c.diagnostico
+}
+
+// Reemplaza la Salida de diagnóstico.
+func (c *consola) AsignarSalidaDiagnostico(s *Salida) {
+	c.diagnostico = s
+}
+
+// Escribe la Cadena, sin estilos, en la Salida de diagnóstico y la vuelca.
+func (c consola) ImprimirDiagnostico(cad Cadena) error {
+	return c.SalidaDiagnostico().escribirDirecto(cadena.SinEstilos(cad.S()))
+}
@@ -0,0 +1,346 @@
+package consola
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+)
+
+// Error que devuelven Leer y LeerPrefijo en una terminal cuando se interrumpe la lectura con ^C.
+var ERROR_INTERRUMPIDO = errors.New("lectura interrumpida por el usuario ^C")
+
+// Mapa de teclas del editor de líneas con el perfil emacs (nombre "editor" en la configuración de teclado).
+//
+// Acciones: caracter-anterior, caracter-siguiente, palabra-anterior, palabra-siguiente, inicio-linea, fin-linea,
+// borrar-anterior, borrar-siguiente, borrar-siguiente-o-fin, borrar-palabra-anterior, borrar-palabra-siguiente,
+// borrar-hasta-inicio, borrar-hasta-fin, pegar (lo último borrado), deshacer, historial-anterior, historial-siguiente,
+// limpiar-pantalla, aceptar e interrumpir.
+var MAPA_EMACS = teclado.NuevoMapa(
+	"izquierda", "caracter-anterior", "ctrl+b", "caracter-anterior",
+	"derecha", "caracter-siguiente", "ctrl+f", "caracter-siguiente",
+	"ctrl+izquierda", "palabra-anterior", "alt+b", "palabra-anterior",
+	"ctrl+derecha", "palabra-siguiente", "alt+f", "palabra-siguiente",
+	"inicio", "inicio-linea", "ctrl+a", "inicio-linea",
+	"fin", "fin-linea", "ctrl+e", "fin-linea",
+	"retroceso", "borrar-anterior",
+	"suprimir", "borrar-siguiente", "ctrl+d", "borrar-siguiente-o-fin",
+	"ctrl+w", "borrar-palabra-anterior", "alt+retroceso", "borrar-palabra-anterior",
+	"alt+d", "borrar-palabra-siguiente",
+	"ctrl+u", "borrar-hasta-inicio", "ctrl+k", "borrar-hasta-fin",
+	"ctrl+y", "pegar",
+	"ctrl+_", "deshacer",
+	"arriba", "historial-anterior", "ctrl+p", "historial-anterior",
+	"abajo", "historial-siguiente", "ctrl+n", "historial-siguiente",
+	"ctrl+l", "limpiar-pantalla",
+	"enter", "aceptar",
+	"ctrl+c", "interrumpir",
+)
+
+// Mapa de teclas del modo de inserción del perfil vi (nombre "editor-vi"). Esc pasa al modo normal (ver MAPA_VI_NORMAL).
+var MAPA_VI = teclado.NuevoMapa(
+	"esc", "modo-normal",
+	"izquierda", "caracter-anterior", "derecha", "caracter-siguiente",
+	"inicio", "inicio-linea", "fin", "fin-linea",
+	"retroceso", "borrar-anterior", "suprimir", "borrar-siguiente", "ctrl+d", "borrar-siguiente-o-fin",
+	"ctrl+w", "borrar-palabra-anterior", "ctrl+u", "borrar-hasta-inicio",
+	"arriba", "historial-anterior", "abajo", "historial-siguiente",
+	"ctrl+l", "limpiar-pantalla",
+	"enter", "aceptar",
+	"ctrl+c", "interrumpir",
+)
+
+// Mapa de teclas del modo normal del perfil vi (nombre "editor-vi-normal"). Además de las acciones de MAPA_EMACS tiene
+// insertar, insertar-despues, insertar-inicio, insertar-fin y cambiar-hasta-fin, que vuelven al modo de inserción.
+var MAPA_VI_NORMAL = teclado.NuevoMapa(
+	"h", "caracter-anterior", "izquierda", "caracter-anterior",
+	"l", "caracter-siguiente", "derecha", "caracter-siguiente",
+	"b", "palabra-anterior", "w", "palabra-siguiente",
+	"0", "inicio-linea", "inicio", "inicio-linea",
+	"$", "fin-linea", "fin", "fin-linea",
+	"x", "borrar-siguiente", "suprimir", "borrar-siguiente", "X", "borrar-anterior",
+	"D", "borrar-hasta-fin", "C", "cambiar-hasta-fin",
+	"i", "insertar", "a", "insertar-despues", "I", "insertar-inicio", "A", "insertar-fin",
+	"p", "pegar", "u", "deshacer",
+	"k", "historial-anterior", "arriba", "historial-anterior",
+	"j", "historial-siguiente", "abajo", "historial-siguiente",
+	"ctrl+l", "limpiar-pantalla",
+	"enter", "aceptar",
+	"ctrl+c", "interrumpir",
+)
+
+// Estado del editor de líneas que se conserva entre lecturas de una misma Entrada.
+type edicion struct {
+	historial []string
+	recortado []rune // Lo último borrado con borrar-palabra-*, borrar-hasta-*; lo inserta pegar
+}
+
+type estadoLinea struct {
+	texto  []rune
+	cursor int
+}
+
+// Línea en edición.
+type editor struct {
+	*edicion
+	estadoLinea
+	normal    bool // Modo normal de vi
+	deshacer  []estadoLinea
+	agrupando bool // La última edición insertó un carácter: las siguientes inserciones se deshacen juntas
+	posicion  int  // Línea del historial presentada; len(historial) es la línea nueva
+	borrador  []rune
+	fila      int // Fila del cursor respecto de la primera de la línea presentada, que puede ocupar varias
+}
+
+// Acciones que modifican la línea y por lo tanto se pueden deshacer.
+var accionesEdicion = []string{
+	"borrar-anterior", "borrar-siguiente", "borrar-siguiente-o-fin", "borrar-palabra-anterior", "borrar-palabra-siguiente",
+	"borrar-hasta-inicio", "borrar-hasta-fin", "cambiar-hasta-fin", "pegar",
+}
+
+func (e *editor) insertar(r ...rune) {
+	e.texto = slices.Insert(slices.Clone(e.texto), e.cursor, r...)
+	e.cursor += len(r)
+}
+
+// Borra el texto entre desde y hasta. Si recortar, lo guarda para pegar.
+func (e *editor) borrar(desde int, hasta int, recortar bool) {
+	if desde >= hasta {
+		return
+	}
+	if recortar {
+		e.recortado = slices.Clone(e.texto[desde:hasta])
+	}
+	e.texto = slices.Delete(slices.Clone(e.texto), desde, hasta)
+	e.cursor = desde
+}
+
+// Posición del comienzo de la palabra anterior al cursor.
+func (e editor) palabraAnterior() int {
+	i := e.cursor
+	for i > 0 && unicode.IsSpace(e.texto[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(e.texto[i-1]) {
+		i--
+	}
+	return i
+}
+
+// Posición del final de la palabra siguiente al cursor.
+func (e editor) palabraSiguiente() int {
+	i := e.cursor
+	for i < len(e.texto) && unicode.IsSpace(e.texto[i]) {
+		i++
+	}
+	for i < len(e.texto) && !unicode.IsSpace(e.texto[i]) {
+		i++
+	}
+	return i
+}
+
+func (e *editor) navegarHistorial(paso int) {
+	destino := e.posicion + paso
+	if destino < 0 || destino > len(e.historial) {
+		return
+	}
+	if e.posicion == len(e.historial) {
+		e.borrador = e.texto
+	}
+	e.posicion = destino
+	if destino == len(e.historial) {
+		e.texto = e.borrador
+	} else {
+		e.texto = []rune(e.historial[destino])
+	}
+	e.cursor = len(e.texto)
+	e.deshacer, e.agrupando = nil, false
+}
+
+// Aplica accion (o inserta la tecla si es imprimible y no tiene acción). Devuelve fin true si la lectura terminó,
+// con err ERROR_INTERRUMPIDO o io.EOF si se la interrumpió. El texto pegado se inserta completo y se deshace de una vez.
+func (e *editor) aplicar(accion string, t teclado.Tecla) (fin bool, err error) {
+	insercion := accion == "" && t.Imprimible() && !e.normal
+	pegado := accion == "" && t.Codigo == teclado.CODIGO_PEGADO
+	if insercion && !e.agrupando || pegado || slices.Contains(accionesEdicion, accion) {
+		e.deshacer = append(e.deshacer, estadoLinea{slices.Clone(e.texto), e.cursor})
+	}
+	e.agrupando = insercion
+
+	switch accion {
+	case "":
+		switch {
+		case insercion:
+			e.insertar(t.Runa)
+		case pegado:
+			e.insertar([]rune(t.Texto)...)
+		}
+	case "caracter-anterior":
+		e.cursor = max(e.cursor-1, 0)
+	case "caracter-siguiente":
+		e.cursor = min(e.cursor+1, len(e.texto))
+	case "palabra-anterior":
+		e.cursor = e.palabraAnterior()
+	case "palabra-siguiente":
+		e.cursor = e.palabraSiguiente()
+	case "inicio-linea":
+		e.cursor = 0
+	case "fin-linea":
+		e.cursor = len(e.texto)
+	case "borrar-anterior":
+		if e.cursor > 0 {
+			e.borrar(e.cursor-1, e.cursor, false)
+		}
+	case "borrar-siguiente-o-fin":
+		if len(e.texto) == 0 {
+			return true, io.EOF
+		}
+		fallthrough
+	case "borrar-siguiente":
+		if e.cursor < len(e.texto) {
+			e.borrar(e.cursor, e.cursor+1, false)
+		}
+	case "borrar-palabra-anterior":
+		e.borrar(e.palabraAnterior(), e.cursor, true)
+	case "borrar-palabra-siguiente":
+		inicio := e.cursor
+		e.borrar(inicio, e.palabraSiguiente(), true)
+	case "borrar-hasta-inicio":
+		e.borrar(0, e.cursor, true)
+	case "borrar-hasta-fin":
+		e.borrar(e.cursor, len(e.texto), true)
+	case "cambiar-hasta-fin":
+		e.borrar(e.cursor, len(e.texto), true)
+		e.normal = false
+	case "pegar":
+		if e.normal && len(e.texto) > 0 {
+			e.cursor++
+		}
+		e.insertar(e.recortado...)
+	case "deshacer":
+		if len(e.deshacer) > 0 {
+			e.estadoLinea = e.deshacer[len(e.deshacer)-1]
+			e.deshacer = e.deshacer[:len(e.deshacer)-1]
+		}
+	case "historial-anterior":
+		e.navegarHistorial(-1)
+	case "historial-siguiente":
+		e.navegarHistorial(1)
+	case "modo-normal":
+		e.normal = true
+		e.cursor = max(e.cursor-1, 0)
+	case "insertar":
+		e.normal = false
+	case "insertar-despues":
+		e.normal = false
+		e.cursor = min(e.cursor+1, len(e.texto))
+	case "insertar-inicio":
+		e.normal, e.cursor = false, 0
+	case "insertar-fin":
+		e.normal, e.cursor = false, len(e.texto)
+	case "aceptar":
+		return true, nil
+	case "interrumpir":
+		return true, ERROR_INTERRUMPIDO
+	}
+	// En el modo normal el cursor queda sobre un carácter.
+	if e.normal && e.cursor == len(e.texto) && e.cursor > 0 {
+		e.cursor--
+	}
+	return false, nil
+}
+
+// Lee una línea con edición: mueve el cursor, borra palabras, recorre el historial y deshace cambios según el
+// perfil de teclado (ver teclado.Perfil, MAPA_EMACS y MAPA_VI). indicador se presenta antes del texto.
+// Mientras se edita está activo el modo de pegado delimitado: el texto pegado conserva sus saltos de línea, que se
+// presentan como ↵.
+func (c consola) editarLinea(indicador string) (Cadena, error) {
+	c.ImprimirBytes([]byte(teclado.ACTIVAR_PEGADO))
+	defer c.ImprimirBytes([]byte(teclado.DESACTIVAR_PEGADO))
+
+	mapas := []teclado.Mapa{teclado.MapaDe("editor", MAPA_EMACS)}
+	if teclado.Perfil() == teclado.PERFIL_VI {
+		mapas = []teclado.Mapa{teclado.MapaDe("editor-vi", MAPA_VI), teclado.MapaDe("editor-vi-normal", MAPA_VI_NORMAL)}
+	}
+	e := &editor{edicion: c.Entrada.edicion, posicion: len(c.Entrada.edicion.historial)}
+	for {
+		c.dibujarLinea(indicador, e)
+		t, err := c.LeerEvento()
+		if err != nil {
+			c.ImprimirLinea("")
+			return Cadena(""), err
+		}
+		mapa := mapas[0]
+		if e.normal {
+			mapa = mapas[len(mapas)-1]
+		}
+		accion := mapa.Accion(t)
+		if accion == "limpiar-pantalla" {
+			c.ImprimirBytes([]byte(string(teclado.CURSOR_CASA) + BORRAR_PANTALLA))
+			e.fila = 0
+			continue
+		}
+		fin, err := e.aplicar(accion, t)
+		if !fin {
+			continue
+		}
+		e.cursor = len(e.texto)
+		c.dibujarLinea(indicador, e)
+		c.ImprimirLinea("")
+		if err != nil {
+			return Cadena(""), err
+		}
+		linea := Cadena(e.texto).Limpiar().S()
+		if linea != "" && (len(e.historial) == 0 || e.historial[len(e.historial)-1] != linea) {
+			e.historial = append(e.historial, linea)
+		}
+		return Cadena(linea), nil
+	}
+}
+
+// Devuelve, para un texto de largo columnas con el cursor en la columna cursor (contando desde el inicio del
+// indicador), la fila en la que queda la terminal al terminar de escribirlo y la fila y columna del cursor, en una
+// terminal de ancho columnas. Con ancho 0 todo ocupa una fila.
+func ubicarCursor(largo int, cursor int, ancho int) (filaFin int, fila int, columna int) {
+	if ancho <= 0 {
+		return 0, 0, cursor
+	}
+	return largo / ancho, cursor / ancho, cursor % ancho
+}
+
+// Presenta el indicador y la línea en edición, que puede ocupar varias filas de la terminal: sube a la primera, la
+// reescribe borrando lo que quedara debajo y deja el cursor en su lugar.
+func (c consola) dibujarLinea(indicador string, e *editor) {
+	presentar := strings.NewReplacer("\n", "↵").Replace
+	ancho, _, err := c.DevolverTamaño()
+	if err != nil {
+		ancho = 0
+	}
+	var sb strings.Builder
+	if e.fila > 0 {
+		fmt.Fprintf(&sb, "\x1b[%dA", e.fila)
+	}
+	sb.WriteString("\r" + indicador + presentar(string(e.texto)) + BORRAR_HASTA_FIN_PANTALLA)
+
+	inicio := cadena.Ancho(indicador)
+	largo := inicio + cadena.Ancho(presentar(string(e.texto)))
+	filaFin, fila, columna := ubicarCursor(largo, inicio+cadena.Ancho(presentar(string(e.texto[:e.cursor]))), ancho)
+	// Al completar la última fila la terminal no pasa a la siguiente hasta escribir otro carácter.
+	if ancho > 0 && largo > 0 && largo%ancho == 0 {
+		sb.WriteString("\r\n")
+	}
+	if filaFin > fila {
+		fmt.Fprintf(&sb, "\x1b[%dA", filaFin-fila)
+	}
+	sb.WriteString("\r")
+	if columna > 0 {
+		fmt.Fprintf(&sb, "\x1b[%dC", columna)
+	}
+	e.fila = fila
+	c.ImprimirBytes([]byte(sb.String()))
+}
@@ -0,0 +1,87 @@
+package consola
+
+import (
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"github.com/stretchr/testify/assert"
+)
+
+// Aplica cada tecla con su acción en mapa, como editarLinea, y devuelve el editor.
+func editar(t *testing.T, e *editor, mapas []teclado.Mapa, teclas ...string) *editor {
+	for _, nombre := range teclas {
+		tecla, err := teclado.ParsearTecla(nombre)
+		assert.NoError(t, err, nombre)
+		mapa := mapas[0]
+		if e.normal {
+			mapa = mapas[len(mapas)-1]
+		}
+		e.aplicar(mapa.Accion(tecla), tecla)
+	}
+	return e
+}
+
+// TestEditor prueba las acciones del editor de líneas con los perfiles emacs y vi
+func TestEditor(t *testing.T) {
+	emacs := []teclado.Mapa{MAPA_EMACS}
+	vi := []teclado.Mapa{MAPA_VI, MAPA_VI_NORMAL}
+	letras := func(s string) []string {
+		teclas := []string{}
+		for _, r := range s {
+			teclas = append(teclas, string(r))
+		}
+		return teclas
+	}
+	casos := []struct {
+		nombre    string
+		mapas     []teclado.Mapa
+		historial []string
+		teclas    []string
+		texto     string
+		cursor    int
+	}{
+		{"insertar y mover", emacs, nil, append(letras("hola"), "ctrl+a", "ctrl+f"), "hola", 1},
+		{"recortar y pegar", emacs, nil, append(letras("uno dos"), "ctrl+w", "ctrl+a", "ctrl+y"), "dosuno ", 3},
+		{"borrar hasta el fin", emacs, nil, append(letras("abc"), "ctrl+b", "ctrl+b", "ctrl+k", "ctrl+e", "ctrl+y"), "abc", 3},
+		{"deshacer agrupa lo escrito", emacs, nil, append(letras("uno"), append([]string{"ctrl+w"}, append(letras("dos"), "ctrl+_", "ctrl+_")...)...), "uno", 3},
+		{"deshacer todo", emacs, nil, append(letras("uno"), "ctrl+_"), "", 0},
+		{"historial", emacs, []string{"primera", "segunda"}, append(letras("nueva"), "arriba", "arriba", "abajo"), "segunda", 7},
+		{"historial conserva el borrador", emacs, []string{"vieja"}, append(letras("nueva"), "arriba", "abajo"), "nueva", 5},
+		{"vi modo normal", vi, nil, append(letras("hola"), "esc", "0", "x", "A", "!"), "ola!", 4},
+		{"vi deshacer", vi, nil, append(letras("hola"), "esc", "x", "u"), "hola", 3},
+		{"vi cambiar hasta el fin", vi, nil, append(letras("hola"), "esc", "0", "l", "C", "x"), "hx", 2},
+	}
+	for _, c := range casos {
+		e := &editor{edicion: &edicion{historial: c.historial}, posicion: len(c.historial)}
+		editar(t, e, c.mapas, c.teclas...)
+		assert.Equal(t, c.texto, string(e.texto), c.nombre)
+		assert.Equal(t, c.cursor, e.cursor, c.nombre)
+	}
+
+	e := &editor{edicion: &edicion{}}
+	fin, err := e.aplicar("interrumpir", teclado.Tecla{})
+	assert.True(t, fin)
+	assert.ErrorIs(t, err, ERROR_INTERRUMPIDO)
+
+	// El texto pegado se inserta y se deshace de una vez.
+	e = editar(t, &editor{edicion: &edicion{}}, emacs, letras("a")...)
+	e.aplicar("", teclado.Tecla{Codigo: teclado.CODIGO_PEGADO, Texto: "b\nc"})
+	assert.Equal(t, "ab\nc", string(e.texto))
+	e.aplicar("deshacer", teclado.Tecla{})
+	assert.Equal(t, "a", string(e.texto))
+}
+
+// TestUbicarCursor prueba la posición del cursor en una línea que ocupa varias filas
+func TestUbicarCursor(t *testing.T) {
+	casos := []struct{ largo, cursor, ancho, filaFin, fila, columna int }{
+		{10, 4, 0, 0, 0, 4},
+		{10, 4, 80, 0, 0, 4},
+		{100, 100, 80, 1, 1, 20},
+		{160, 30, 80, 2, 0, 30},
+		{160, 160, 80, 2, 2, 0},
+	}
+	for _, c := range casos {
+		filaFin, fila, columna := ubicarCursor(c.largo, c.cursor, c.ancho)
+		assert.Equal(t, []int{c.filaFin, c.fila, c.columna}, []int{filaFin, fila, columna}, "%+v", c)
+	}
+}
@@ -0,0 +1,154 @@
+package consola
+
+import (
+	"bufio"
+	"errors"
+	"os"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/color"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"golang.org/x/term"
+)
+
+// ConsolaExtendida agrega a Consola las capacidades de las Consolas de este paquete: eventos de teclado, colores y
+// temas, paginado, progreso, salida de diagnóstico, tamaño de la terminal y ratón.
+//
+// Los componentes (menús, preguntas, visores...) aceptan cualquier Consola y usan estas capacidades a través de
+// Extender, por lo que una implementación propia de Consola no necesita implementarlas.
+type ConsolaExtendida interface {
+	Consola
+
+	LeerEvento() (teclado.Tecla, error)
+
+	NivelColor() color.Nivel
+	AsignarNivelColor(color.Nivel)
+	Tema() cadena.Tema
+	AsignarTema(cadena.Tema)
+
+	IniciarPaginado()
+	FinalizarPaginado() error
+	Paginar(Cadena) error
+
+	NuevaBarra(string, int64) *BarraProgreso
+	NuevaEspera(string) *BarraProgreso
+	NuevoGrupoProgreso() *GrupoProgreso
+	NuevaListaPasos() *ListaPasos
+
+	SalidaDiagnostico() *Salida
+	AsignarSalidaDiagnostico(*Salida)
+	ImprimirDiagnostico(Cadena) error
+
+	DevolverTamaño() (int, int, error)
+	PosicionCursor() (int, int, error)
+
+	UsarRaton(bool)
+	HabilitarRaton() bool
+	DeshabilitarRaton()
+}
+
+// Devuelve c como ConsolaExtendida. Si c no implementa ConsolaExtendida, la envuelve con comportamientos por defecto:
+// las teclas se decodifican de LeerTecla, se usa cadena.TemaPredeterminado, el paginado imprime el texto sin paginar,
+// el progreso y el diagnóstico se escriben en c y en os.Stderr, y no se usa el ratón. Lo que se asigne a la envoltura
+// (Tema, nivel de color...) no se conserva entre llamadas a Extender.
+func Extender(c Consola) ConsolaExtendida {
+	if e, ok := c.(ConsolaExtendida); ok {
+		return e
+	}
+	salida := NuevaMultiSalida(bufio.NewWriter(c), c.FSalida())
+	return &extendida{Consola: c, tema: cadena.TemaPredeterminado, salida: salida}
+}
+
+// Envoltura de una Consola que no implementa ConsolaExtendida (ver Extender).
+type extendida struct {
+	Consola
+	tema        cadena.Tema
+	salida      *Salida // Escribe en la Consola, para el progreso y la lista de pasos
+	diagnostico *Salida
+}
+
+func (e *extendida) LeerEvento() (teclado.Tecla, error) {
+	buf := make([]byte, 32)
+	n, err := e.LeerTecla(&buf)
+	if err != nil {
+		return teclado.Tecla{}, err
+	}
+	t, _, _ := teclado.Decodificar(buf[:n], true)
+	return t, nil
+}
+
+func (e *extendida) NivelColor() color.Nivel {
+	return e.salida.NivelColor()
+}
+
+func (e *extendida) AsignarNivelColor(n color.Nivel) {
+	e.salida.AsignarNivelColor(n)
+}
+
+func (e *extendida) Tema() cadena.Tema {
+	return e.tema
+}
+
+func (e *extendida) AsignarTema(t cadena.Tema) {
+	e.tema = t
+}
+
+func (e *extendida) IniciarPaginado() {}
+
+func (e *extendida) FinalizarPaginado() error {
+	return nil
+}
+
+func (e *extendida) Paginar(texto Cadena) error {
+	return e.ImprimirCadena(texto)
+}
+
+func (e *extendida) NuevaBarra(descripcion string, total int64) *BarraProgreso {
+	return e.NuevoGrupoProgreso().Agregar(OpcionesProgreso{Descripcion: descripcion, Total: total})
+}
+
+func (e *extendida) NuevaEspera(descripcion string) *BarraProgreso {
+	return e.NuevoGrupoProgreso().Agregar(OpcionesProgreso{Descripcion: descripcion})
+}
+
+func (e *extendida) NuevoGrupoProgreso() *GrupoProgreso {
+	return NuevoGrupoProgreso(e.salida, e.tema)
+}
+
+func (e *extendida) NuevaListaPasos() *ListaPasos {
+	return NuevaListaPasos(e.salida, e.tema)
+}
+
+func (e *extendida) SalidaDiagnostico() *Salida {
+	if e.diagnostico == nil {
+		e.diagnostico = NuevaSalida(os.Stderr)
+	}
+	return e.diagnostico
+}
+
+func (e *extendida) AsignarSalidaDiagnostico(s *Salida) {
+	e.diagnostico = s
+}
+
+func (e *extendida) ImprimirDiagnostico(cad Cadena) error {
+	return e.SalidaDiagnostico().escribirDirecto(cadena.SinEstilos(cad.S()))
+}
+
+func (e *extendida) DevolverTamaño() (int, int, error) {
+	if f := e.FSalida(); f != nil {
+		return term.GetSize(int(f.Fd()))
+	}
+	return 0, 0, errors.New("la consola no tiene una terminal asociada")
+}
+
+func (e *extendida) PosicionCursor() (int, int, error) {
+	return 0, 0, errors.New("la consola no informa la posición del cursor")
+}
+
+func (e *extendida) UsarRaton(bool) {}
+
+func (e *extendida) HabilitarRaton() bool {
+	return false
+}
+
+func (e *extendida) DeshabilitarRaton() {}
@@ -0,0 +1,415 @@
+/*
+Grabación y reproducción de sesiones de Consola en formato asciicast v2.
+*/
+
+package consola
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"golang.org/x/term"
+)
+
+const (
+	EVENTO_SALIDA  = "o"
+	EVENTO_ENTRADA = "i"
+)
+
+// Encabezado de una grabación asciicast v2 (primera línea del archivo).
+type EncabezadoGrabacion struct {
+	Version   int               `json:"version"`
+	Ancho     int               `json:"width"`
+	Alto      int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Titulo    string            `json:"title,omitempty"`
+	Entorno   map[string]string `json:"env,omitempty"`
+}
+
+// Un evento de la grabación: segundos desde el inicio, tipo ("o" salida, "i" entrada) y datos.
+type EventoGrabacion struct {
+	Tiempo float64
+	Tipo   string
+	Datos  string
+}
+
+func (e EventoGrabacion) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{e.Tiempo, e.Tipo, e.Datos})
+}
+
+func (e *EventoGrabacion) UnmarshalJSON(b []byte) error {
+	var crudo []json.RawMessage
+	if err := json.Unmarshal(b, &crudo); err != nil {
+		return err
+	}
+	if len(crudo) != 3 {
+		return fmt.Errorf("evento asciicast inválido: se esperaban 3 elementos, se encontraron %d", len(crudo))
+	}
+	return errors.Join(
+		json.Unmarshal(crudo[0], &e.Tiempo),
+		json.Unmarshal(crudo[1], &e.Tipo),
+		json.Unmarshal(crudo[2], &e.Datos),
+	)
+}
+
+// Grabacion es una sesión completa leída desde un archivo asciicast v2.
+type Grabacion struct {
+	Encabezado EncabezadoGrabacion
+	Eventos    []EventoGrabacion
+}
+
+// Lee una grabación asciicast v2 desde r.
+func LeerGrabacion(r io.Reader) (*Grabacion, error) {
+	lector := bufio.NewScanner(r)
+	lector.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	if !lector.Scan() {
+		if err := lector.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("grabación vacía: falta el encabezado asciicast")
+	}
+	g := &Grabacion{}
+	if err := json.Unmarshal(lector.Bytes(), &g.Encabezado); err != nil {
+		return nil, fmt.Errorf("encabezado asciicast inválido: %w", err)
+	}
+	if g.Encabezado.Version != 2 {
+		return nil, fmt.Errorf("versión asciicast no soportada: %d", g.Encabezado.Version)
+	}
+	for lector.Scan() {
+		linea := strings.TrimSpace(lector.Text())
+		if linea == "" {
+			continue
+		}
+		var e EventoGrabacion
+		if err := json.Unmarshal([]byte(linea), &e); err != nil {
+			return nil, err
+		}
+		g.Eventos = append(g.Eventos, e)
+	}
+	return g, lector.Err()
+}
+
+// Devuelve la concatenación de todos los eventos del tipo indicado.
+func (g Grabacion) datos(tipo string) string {
+	var sb strings.Builder
+	for _, e := range g.Eventos {
+		if e.Tipo == tipo {
+			sb.WriteString(e.Datos)
+		}
+	}
+	return sb.String()
+}
+
+// Devuelve toda la salida grabada, útil para comparar en pruebas de regresión.
+func (g Grabacion) Salida() string {
+	return g.datos(EVENTO_SALIDA)
+}
+
+// Devuelve toda la entrada grabada.
+func (g Grabacion) Entrada() string {
+	return g.datos(EVENTO_ENTRADA)
+}
+
+// Escribe la salida grabada a w respetando los tiempos originales divididos por velocidad.
+// Con velocidad <= 0 escribe todo sin esperar.
+func (g Grabacion) Reproducir(w io.Writer, velocidad float64) error {
+	var anterior float64
+	for _, e := range g.Eventos {
+		if e.Tipo != EVENTO_SALIDA {
+			continue
+		}
+		if velocidad > 0 && e.Tiempo > anterior {
+			time.Sleep(time.Duration((e.Tiempo - anterior) / velocidad * float64(time.Second)))
+		}
+		anterior = e.Tiempo
+		if _, err := io.WriteString(w, e.Datos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registro serializa eventos asciicast a un io.Writer a medida que ocurren.
+type registro struct {
+	mu        sync.Mutex
+	w         io.Writer
+	inicio    time.Time
+	pendiente map[string][]byte
+	ocultar   bool
+	err       error
+}
+
+func nuevoRegistro(w io.Writer, encabezado EncabezadoGrabacion) (*registro, error) {
+	b, err := json.Marshal(encabezado)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return nil, err
+	}
+	return &registro{
+		w:         w,
+		inicio:    time.Now(),
+		pendiente: make(map[string][]byte),
+	}, nil
+}
+
+// Registra p como evento de tipo tipo. Los bytes de una runa UTF-8 incompleta quedan pendientes hasta la próxima escritura.
+func (r *registro) escribir(tipo string, p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	datos := append(r.pendiente[tipo], p...)
+	corte := len(datos)
+	for i := len(datos) - 1; i >= 0 && i >= len(datos)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(datos[i]) {
+			if !utf8.FullRune(datos[i:]) {
+				corte = i
+			}
+			break
+		}
+	}
+	r.pendiente[tipo] = append([]byte(nil), datos[corte:]...)
+	if corte == 0 {
+		return
+	}
+	s := string(datos[:corte])
+	if tipo == EVENTO_ENTRADA && r.ocultar {
+		s = ocultarEntrada(s)
+	}
+	r.emitir(EventoGrabacion{Tiempo: time.Since(r.inicio).Seconds(), Tipo: tipo, Datos: s})
+}
+
+func (r *registro) emitir(e EventoGrabacion) {
+	if r.err != nil {
+		return
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		r.err = err
+		return
+	}
+	_, r.err = r.w.Write(append(b, '\n'))
+}
+
+func (r *registro) cerrar() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tipo := range []string{EVENTO_SALIDA, EVENTO_ENTRADA} {
+		if len(r.pendiente[tipo]) > 0 {
+			r.emitir(EventoGrabacion{Tiempo: time.Since(r.inicio).Seconds(), Tipo: tipo, Datos: string(r.pendiente[tipo])})
+			r.pendiente[tipo] = nil
+		}
+	}
+	return r.err
+}
+
+func (r *registro) ocultarContraseña(ocultar bool) {
+	r.mu.Lock()
+	r.ocultar = ocultar
+	r.mu.Unlock()
+}
+
+// Reemplaza todo caracter imprimible por '*', conservando los de control (Enter, Retroceso...).
+func ocultarEntrada(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return r
+		}
+		return '*'
+	}, s)
+}
+
+type escritorRegistro struct {
+	r    *registro
+	tipo string
+}
+
+func (e escritorRegistro) Write(p []byte) (int, error) {
+	e.r.escribir(e.tipo, p)
+	return len(p), nil
+}
+
+// Grabadora envuelve una Consola y registra, con marcas de tiempo, toda su entrada y salida en formato asciicast v2.
+//
+// # Ejemplo:
+//
+//	archivo, _ := os.Create("sesion.cast")
+//	con, _ := consola.NuevaConsolaGrabada(os.Stdin, os.Stdout, archivo, "demo")
+//	defer con.Cerrar()
+//	app := aplicacion.NuevaAplicacion("app", "app", "demo", nil, con)
+//	app.Correr()
+//
+// Las contraseñas leídas con LeerContraseña se graban enmascaradas salvo que OcultarContraseñas sea false.
+type Grabadora struct {
+	ConsolaExtendida
+	OcultarContraseñas bool
+
+	registro *registro
+}
+
+// Crea una Consola sobre fe y fs que graba la sesión en destino.
+func NuevaConsolaGrabada(fe *os.File, fs *os.File, destino io.Writer, titulo string) (*Grabadora, error) {
+	ancho, alto, err := term.GetSize(int(fs.Fd()))
+	if err != nil {
+		ancho, alto = 80, 24
+	}
+	reg, err := nuevoRegistro(destino, EncabezadoGrabacion{
+		Version:   2,
+		Ancho:     ancho,
+		Alto:      alto,
+		Timestamp: time.Now().Unix(),
+		Titulo:    titulo,
+		Entorno: map[string]string{
+			"TERM":  os.Getenv("TERM"),
+			"SHELL": os.Getenv("SHELL"),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entrada := NuevaMultiEntrada(bufio.NewReader(io.TeeReader(fe, escritorRegistro{reg, EVENTO_ENTRADA})), fe)
+	salida := NuevaMultiSalida(bufio.NewWriter(io.MultiWriter(fs, escritorRegistro{reg, EVENTO_SALIDA})), fs)
+	// LeerEvento y LeerTecla leen directamente del archivo subyacente.
+	entrada.eventos = teclado.NuevoDecodificador(func(b []byte) (int, error) {
+		n, err := fe.Read(b)
+		if n > 0 {
+			reg.escribir(EVENTO_ENTRADA, b[:n])
+		}
+		return n, err
+	}, func(espera time.Duration) bool { return hayEntrada(fe, espera) })
+	return &Grabadora{
+		ConsolaExtendida: &consola{
+			EntradaSalida: EntradaSalida{
+				*entrada,
+				*salida,
+				entrada.esTerminal || salida.esTerminal,
+			},
+			tema:        cadena.TemaPredeterminado,
+			diagnostico: NuevaSalida(os.Stderr),
+		},
+		OcultarContraseñas: true,
+		registro:           reg,
+	}, nil
+}
+
+func (g *Grabadora) LeerContraseña(mensaje Cadena) (Cadena, error) {
+	g.registro.ocultarContraseña(g.OcultarContraseñas)
+	defer g.registro.ocultarContraseña(false)
+	return g.ConsolaExtendida.LeerContraseña(mensaje)
+}
+
+// Vuelca los datos pendientes al destino. No cierra el destino.
+func (g *Grabadora) Cerrar() error {
+	return errors.Join(g.ConsolaExtendida.Imprimir(), g.registro.cerrar())
+}
+
+// Lector de la entrada de una Consola reproducida: avisa en leido cada vez que se leen bytes.
+type lectorReproduccion struct {
+	f     *os.File
+	leido chan struct{}
+}
+
+func (l lectorReproduccion) Read(b []byte) (int, error) {
+	n, err := l.f.Read(b)
+	if n > 0 {
+		select {
+		case l.leido <- struct{}{}:
+		default:
+		}
+	}
+	return n, err
+}
+
+// Reproductora es una Consola cuya entrada es la de una Grabacion (ver NuevaConsolaReproducida).
+type Reproductora struct {
+	ConsolaExtendida
+
+	lector *os.File
+	fin    chan struct{}
+	cerrar sync.Once
+}
+
+// Crea una Consola que escribe a fs y cuya entrada es la entrada grabada en g, para volver a correr una Aplicacion con ella.
+// Si tiempoReal es true la entrada se entrega respetando los tiempos originales; si no, se entrega de inmediato.
+// Al agotarse la grabación la entrada devuelve io.EOF.
+//
+// Los eventos se entregan de a uno, cuando se terminó de leer el anterior, para que una lectura de líneas (Leer) no
+// consuma las teclas que siguen en la grabación y que corresponden a LeerEvento o LeerTecla. Si la aplicación puede
+// terminar sin leer toda la grabación, se debe llamar a Cerrar.
+//
+// # Ejemplo:
+//
+//	con, _ := consola.NuevaConsolaReproducida(g, os.Stdout, false)
+//	defer con.Cerrar()
+func NuevaConsolaReproducida(g *Grabacion, fs *os.File, tiempoReal bool) (*Reproductora, error) {
+	lector, escritor, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	reproduccion := lectorReproduccion{lector, make(chan struct{}, 1)}
+	fin := make(chan struct{})
+	go func() {
+		defer escritor.Close()
+		var anterior float64
+		for _, e := range g.Eventos {
+			if e.Tipo != EVENTO_ENTRADA {
+				continue
+			}
+			if tiempoReal && e.Tiempo > anterior {
+				select {
+				case <-time.After(time.Duration((e.Tiempo - anterior) * float64(time.Second))):
+				case <-fin:
+					return
+				}
+			}
+			anterior = e.Tiempo
+			if _, err := io.WriteString(escritor, e.Datos); err != nil {
+				return
+			}
+			select {
+			case <-reproduccion.leido:
+			case <-fin:
+				return
+			}
+		}
+	}()
+
+	entrada := NuevaMultiEntrada(bufio.NewReader(reproduccion), lector)
+	entrada.eventos = teclado.NuevoDecodificador(reproduccion.Read, func(espera time.Duration) bool { return hayEntrada(lector, espera) })
+	salida := NuevaSalida(fs)
+	return &Reproductora{
+		ConsolaExtendida: &consola{
+			EntradaSalida: EntradaSalida{
+				*entrada,
+				*salida,
+				entrada.esTerminal || salida.esTerminal,
+			},
+			tema:        cadena.TemaPredeterminado,
+			diagnostico: NuevaSalida(os.Stderr),
+		},
+		lector: lector,
+		fin:    fin,
+	}, nil
+}
+
+// Deja de entregar la grabación, vuelca la salida y cierra la entrada. Las lecturas siguientes devuelven error.
+func (r *Reproductora) Cerrar() error {
+	var err error
+	r.cerrar.Do(func() {
+		close(r.fin)
+		err = errors.Join(r.ConsolaExtendida.Imprimir(), r.lector.Close())
+	})
+	return err
+}
@@ -0,0 +1,128 @@
+package consola_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/menu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGrabacionIdaYVuelta graba una lectura y una escritura y verifica que la grabación se pueda leer y reproducir
+func TestGrabacionIdaYVuelta(t *testing.T) {
+	le, ee, err := os.Pipe()
+	require.NoError(t, err)
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer ls.Close()
+
+	var cast bytes.Buffer
+	con, err := consola.NuevaConsolaGrabada(le, es, &cast, "prueba")
+	require.NoError(t, err)
+
+	_, err = io.WriteString(ee, "hola\n")
+	require.NoError(t, err)
+	ee.Close()
+
+	leido, err := con.Leer("nombre")
+	require.NoError(t, err)
+	assert.Equal(t, "hola", leido.S())
+	con.ImprimirLinea("Hola Mundo")
+	require.NoError(t, con.Cerrar())
+	es.Close()
+	io.ReadAll(ls)
+
+	g, err := consola.LeerGrabacion(&cast)
+	require.NoError(t, err)
+	assert.Equal(t, 2, g.Encabezado.Version)
+	assert.Equal(t, "prueba", g.Encabezado.Titulo)
+	assert.Equal(t, "hola\n", g.Entrada())
+	assert.Contains(t, g.Salida(), "Hola Mundo")
+
+	var reproducida bytes.Buffer
+	require.NoError(t, g.Reproducir(&reproducida, 0))
+	assert.Equal(t, g.Salida(), reproducida.String())
+}
+
+// TestConsolaReproducida verifica que la entrada grabada se entregue a la nueva Consola
+func TestConsolaReproducida(t *testing.T) {
+	g := &consola.Grabacion{
+		Encabezado: consola.EncabezadoGrabacion{Version: 2, Ancho: 80, Alto: 24},
+		Eventos: []consola.EventoGrabacion{
+			{Tiempo: 0.1, Tipo: consola.EVENTO_SALIDA, Datos: "> "},
+			{Tiempo: 0.2, Tipo: consola.EVENTO_ENTRADA, Datos: "ayuda\n"},
+		},
+	}
+	_, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer es.Close()
+
+	con, err := consola.NuevaConsolaReproducida(g, es, false)
+	require.NoError(t, err)
+	defer con.Cerrar()
+	leido, err := con.Leer("")
+	require.NoError(t, err)
+	assert.Equal(t, "ayuda", leido.S())
+}
+
+// TestConsolaReproducidaConMenu verifica que una lectura de líneas no consuma las teclas grabadas para un menú
+func TestConsolaReproducidaConMenu(t *testing.T) {
+	g := &consola.Grabacion{
+		Encabezado: consola.EncabezadoGrabacion{Version: 2, Ancho: 80, Alto: 24},
+		Eventos: []consola.EventoGrabacion{
+			{Tiempo: 0.1, Tipo: consola.EVENTO_ENTRADA, Datos: "hola\n"},
+			{Tiempo: 0.2, Tipo: consola.EVENTO_ENTRADA, Datos: "\x1b[B"},
+			{Tiempo: 0.3, Tipo: consola.EVENTO_ENTRADA, Datos: "\r"},
+			{Tiempo: 0.4, Tipo: consola.EVENTO_ENTRADA, Datos: "chau\n"},
+		},
+	}
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer es.Close()
+	go io.Copy(io.Discard, ls)
+
+	con, err := consola.NuevaConsolaReproducida(g, es, false)
+	require.NoError(t, err)
+	defer con.Cerrar()
+
+	leido, err := con.Leer("")
+	require.NoError(t, err)
+	assert.Equal(t, "hola", leido.S())
+
+	m := menu.NuevoMenu(con, '>')
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "uno"})
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "dos"})
+	elegida, err := m.Correr()
+	require.NoError(t, err)
+	require.NotNil(t, elegida)
+	assert.Equal(t, "dos", elegida.Nombre)
+
+	leido, err = con.Leer("")
+	require.NoError(t, err)
+	assert.Equal(t, "chau", leido.S())
+}
+
+// TestCerrarConsolaReproducida verifica que Cerrar corte una reproducción que la aplicación dejó de leer
+func TestCerrarConsolaReproducida(t *testing.T) {
+	g := &consola.Grabacion{
+		Encabezado: consola.EncabezadoGrabacion{Version: 2, Ancho: 80, Alto: 24},
+		Eventos: []consola.EventoGrabacion{
+			{Tiempo: 0.1, Tipo: consola.EVENTO_ENTRADA, Datos: "uno\n"},
+			{Tiempo: 0.2, Tipo: consola.EVENTO_ENTRADA, Datos: "dos\n"},
+		},
+	}
+	_, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer es.Close()
+
+	con, err := consola.NuevaConsolaReproducida(g, es, true)
+	require.NoError(t, err)
+	require.NoError(t, con.Cerrar())
+	require.NoError(t, con.Cerrar())
+	_, err = con.Leer("")
+	assert.Error(t, err)
+}
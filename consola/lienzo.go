@@ -0,0 +1,65 @@
+package consola
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+)
+
+// Región de líneas al final de la Salida que se redibuja en su lugar (barras de progreso, listas de pasos...).
+// Escribe directamente en la Salida, sin pasar por el paginado, tomando su bloqueo en cada cuadro. No es seguro para uso
+// concurrente.
+type lienzo struct {
+	salida    *Salida
+	dibujadas int // Líneas de la región en pantalla
+}
+
+// Ancho disponible para cada línea, dejando libre la última columna para que la terminal no salte de línea.
+func (l *lienzo) ancho() int {
+	ancho, _, err := l.salida.DevolverTamaño()
+	if err != nil || ancho <= 1 {
+		return 79
+	}
+	return ancho - 1
+}
+
+// Reemplaza la región por lineas, escribiendo antes por encima de ella las líneas de encima (que no se vuelven a dibujar).
+// Con final la región queda fija y el cursor pasa a la línea siguiente.
+func (l *lienzo) dibujar(encima []string, lineas []string, final bool) {
+	ancho := l.ancho()
+	var sb strings.Builder
+	if l.dibujadas == 0 {
+		sb.WriteString(OCULTAR_CURSOR)
+	}
+	sb.WriteString("\r")
+	if l.dibujadas > 1 {
+		fmt.Fprintf(&sb, "\x1b[%dA", l.dibujadas-1)
+	}
+	for _, m := range encima {
+		sb.WriteString(m + BORRAR_HASTA_FIN + "\n")
+	}
+	for i, linea := range lineas {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(cadena.Truncar(linea, ancho, "") + BORRAR_HASTA_FIN)
+	}
+	// Si la región se achicó, se borran las líneas sobrantes.
+	if l.dibujadas > len(lineas) {
+		sb.WriteString("\x1b[J")
+	}
+	l.dibujadas = len(lineas)
+	if final {
+		if len(lineas) > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(MOSTRAR_CURSOR)
+		l.dibujadas = 0
+	}
+	l.escribir(sb.String())
+}
+
+func (l *lienzo) escribir(s string) {
+	l.salida.escribirDirecto(s)
+}
@@ -0,0 +1,46 @@
+package consola
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProgresoEnTerminal verifica que la animación y las escrituras de la Consola no se pisen y que el cuadro final de
+// una barra quede antes de lo que se imprime después de Finalizar
+func TestProgresoEnTerminal(t *testing.T) {
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	con := NuevaConsola(os.Stdin, escritor)
+	con.Salida.esTerminal = true
+	leido := make(chan string)
+	go func() {
+		salida, _ := io.ReadAll(lector)
+		leido <- string(salida)
+	}()
+
+	b := con.NuevaBarra("copia", 100)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for range 50 {
+			b.Incrementar()
+			con.ImprimirCadena("mensaje\n")
+		}
+	}()
+	wg.Wait()
+	b.Finalizar()
+	con.ImprimirCadena("después\n")
+	escritor.Close()
+
+	salida := <-leido
+	require.Contains(t, salida, "✓")
+	assert.Less(t, strings.LastIndex(salida, "✓"), strings.Index(salida, "después"))
+}
@@ -0,0 +1,58 @@
+package consola
+
+import (
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/color"
+)
+
+// Devuelve el nivel de soporte de estilos y colores de la Salida.
+func (s Salida) NivelColor() color.Nivel {
+	return s.nivel
+}
+
+// Reemplaza el nivel detectado automáticamente (por ejemplo, a partir de una opción --color de la aplicación).
+func (s *Salida) AsignarNivelColor(n color.Nivel) {
+	s.nivel = n
+}
+
+// Escribe p al buffer (o al del paginado en curso) adaptando sus secuencias de escape al nivel de la Salida. Devuelve len(p) si no hubo error.
+func (s Salida) escribir(p []byte) (int, error) {
+	defer s.bloquear()()
+	if s.nivel >= color.COLOR_VERDADERO {
+		return s.destino().Write(p)
+	}
+	if _, err := s.destino().WriteString(cadena.Degradar(string(p), s.nivel)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Escribe c directamente en la Salida, sin pasar por el paginado, y la vuelca.
+func (s Salida) escribirDirecto(c string) error {
+	defer s.bloquear()()
+	if _, err := s.Writer.WriteString(cadena.Degradar(c, s.nivel)); err != nil {
+		return err
+	}
+	return s.Writer.Flush()
+}
+
+// Vuelca el buffer de la Salida con su bloqueo tomado.
+func (s Salida) Flush() error {
+	defer s.bloquear()()
+	return s.Writer.Flush()
+}
+
+func (s Salida) escribirCadena(c string) error {
+	defer s.bloquear()()
+	_, err := s.destino().WriteString(cadena.Degradar(c, s.nivel))
+	return err
+}
+
+// Devuelve el Tema con el que la Consola presenta títulos, errores, el señalador y demás elementos.
+func (c consola) Tema() cadena.Tema {
+	return c.tema
+}
+
+func (c *consola) AsignarTema(t cadena.Tema) {
+	c.tema = t
+}
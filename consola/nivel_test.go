@@ -0,0 +1,29 @@
+package consola_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/consola/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEscribirDegradado verifica que lo escrito con Write (fmt.Fprint, codificadores) se adapte al nivel de la Salida
+func TestEscribirDegradado(t *testing.T) {
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	con := consola.NuevaConsola(os.Stdin, escritor)
+	con.AsignarNivelColor(color.COLOR_256)
+
+	fmt.Fprint(con, "\x1b[38;2;255;0;0mrojo\x1b[0m")
+	require.NoError(t, con.Imprimir())
+	escritor.Close()
+
+	salida, _ := io.ReadAll(lector)
+	assert.Equal(t, "\x1b[38;5;196mrojo\x1b[0m", string(salida))
+}
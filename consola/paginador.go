@@ -0,0 +1,354 @@
+/*
+Paginado de salidas largas: con un visor propio similar a less o con el programa indicado en $PAGER.
+*/
+
+package consola
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+)
+
+// Salida retenida entre IniciarPaginado y FinalizarPaginado. Se comparte entre las copias de una Salida y se accede
+// con su bloqueo tomado.
+type paginado struct {
+	profundidad int
+	buffer      bytes.Buffer
+}
+
+type escritor interface {
+	io.Writer
+	io.StringWriter
+}
+
+// Toma el bloqueo de escritura de la Salida y devuelve la función que lo libera.
+func (s Salida) bloquear() func() {
+	if s.mu == nil {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// Devuelve dónde escribir: el buffer del paginado en curso o el de la Salida. Se llama con el bloqueo tomado.
+func (s Salida) destino() escritor {
+	if s.paginado != nil && s.paginado.profundidad > 0 {
+		return &s.paginado.buffer
+	}
+	return s.Writer
+}
+
+// Retiene todo lo que se escriba a la Consola hasta FinalizarPaginado. Las llamadas se pueden anidar.
+//
+// # Ejemplo:
+//
+//	con.IniciarPaginado()
+//	defer con.FinalizarPaginado()
+//	for _, l := range registros {
+//		con.EscribirLinea(l)
+//	}
+func (c consola) IniciarPaginado() {
+	if c.Salida.paginado == nil {
+		return
+	}
+	c.Imprimir()
+	defer c.Salida.bloquear()()
+	c.Salida.paginado.profundidad++
+}
+
+// Termina el paginado iniciado con IniciarPaginado y presenta lo retenido con Paginar.
+func (c consola) FinalizarPaginado() error {
+	p := c.Salida.paginado
+	if p == nil {
+		return nil
+	}
+	liberar := c.Salida.bloquear()
+	if p.profundidad == 0 {
+		liberar()
+		return nil
+	}
+	p.profundidad--
+	if p.profundidad > 0 {
+		liberar()
+		return nil
+	}
+	texto := p.buffer.String()
+	p.buffer.Reset()
+	liberar()
+	return c.Paginar(Cadena(texto))
+}
+
+// Presenta texto de a una pantalla si no entra en la terminal: con el programa de $PAGER si está definido
+// o con un visor propio (flechas, Espacio/b, g/G, "/" para buscar, n/N para repetir la búsqueda y q para salir).
+// Si la Entrada o la Salida no son terminales, o el texto entra en la pantalla, lo imprime tal cual.
+func (c consola) Paginar(texto Cadena) error {
+	ancho, alto, err := c.Salida.DevolverTamaño()
+	if err != nil || alto == 0 || !c.Entrada.esTerminal || entraEnPantalla(texto.S(), ancho, alto) {
+		return errors.Join(c.Salida.escribirCadena(texto.S()), c.Imprimir())
+	}
+	if err := c.Imprimir(); err != nil {
+		return err
+	}
+	degradado := cadena.Degradar(texto.S(), c.Salida.nivel)
+	if programa := strings.TrimSpace(os.Getenv("PAGER")); programa != "" {
+		if err := c.paginarExterno(programa, degradado); err == nil {
+			return nil
+		}
+	}
+	return c.paginarInterno(degradado, ancho, alto)
+}
+
+// Ejecuta programa con texto como entrada. Devuelve error sólo si no se lo pudo ejecutar, para paginar con el visor propio.
+func (c consola) paginarExterno(programa string, texto string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", programa)
+	} else {
+		cmd = exec.Command("sh", "-c", programa)
+	}
+	cmd.Stdin = strings.NewReader(texto)
+	cmd.Stdout = c.Salida.f
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if os.Getenv("LESS") == "" {
+		// Para less: respetar colores, salir si entra en una pantalla y no limpiar la pantalla al salir.
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	err := cmd.Run()
+	var salida *exec.ExitError
+	if errors.As(err, &salida) {
+		// El intérprete devuelve 126 o 127 (9009 en cmd) si no pudo ejecutar el programa: el texto no se presentó.
+		// Los demás códigos son del programa, que ya recibió el texto.
+		switch salida.ExitCode() {
+		case 126, 127, 9009:
+			return fmt.Errorf("no se pudo ejecutar el paginador %q: %w", programa, err)
+		}
+		return nil
+	}
+	return err
+}
+
+const (
+	PANTALLA_ALTERNATIVA      = "\x1b[?1049h"
+	PANTALLA_PRINCIPAL        = "\x1b[?1049l"
+	OCULTAR_CURSOR            = "\x1b[?25l"
+	MOSTRAR_CURSOR            = "\x1b[?25h"
+	BORRAR_HASTA_FIN          = "\x1b[K"
+	BORRAR_HASTA_FIN_PANTALLA = "\x1b[J"
+	BORRAR_PANTALLA           = "\x1b[2J"
+)
+
+// Mapa de teclas del paginador propio (nombre "paginador" en la configuración de teclado). Acciones: arriba, abajo,
+// pagina-anterior, pagina-siguiente, primera, ultima, desplazar-arriba, desplazar-abajo (de a LINEAS_RUEDA), buscar,
+// coincidencia-siguiente, coincidencia-anterior y salir.
+var MAPA_PAGINADOR = teclado.NuevoMapa(
+	"j", "abajo", "enter", "abajo", "abajo", "abajo",
+	"k", "arriba", "arriba", "arriba",
+	"espacio", "pagina-siguiente", "f", "pagina-siguiente", "avpag", "pagina-siguiente",
+	"b", "pagina-anterior", "repag", "pagina-anterior",
+	"g", "primera", "inicio", "primera",
+	"G", "ultima", "fin", "ultima",
+	"/", "buscar",
+	"n", "coincidencia-siguiente",
+	"N", "coincidencia-anterior",
+	"rueda-abajo", "desplazar-abajo", "rueda-arriba", "desplazar-arriba",
+	"q", "salir", "Q", "salir", "esc", "salir", "ctrl+c", "salir",
+)
+
+// Líneas que se desplaza el paginador con cada paso de la rueda del ratón.
+const LINEAS_RUEDA = 3
+
+type paginador struct {
+	con      consola
+	lineas   []string
+	planas   []string // lineas sin secuencias de escape, para buscar
+	desde    int
+	alto     int // Líneas de texto por pantalla (sin la línea de estado)
+	busqueda string
+	mensaje  string
+}
+
+// Reemplaza las tabulaciones por espacios hasta la siguiente columna múltiplo de 8.
+func expandirTabulaciones(l string) string {
+	if !strings.Contains(l, "\t") {
+		return l
+	}
+	var sb strings.Builder
+	for i, parte := range strings.Split(l, "\t") {
+		if i > 0 {
+			sb.WriteString(strings.Repeat(" ", 8-cadena.Ancho(sb.String())%8))
+		}
+		sb.WriteString(parte)
+	}
+	return sb.String()
+}
+
+// Devuelve true si texto, con sus líneas largas partidas, entra en una pantalla de alto líneas dejando una libre.
+func entraEnPantalla(texto string, ancho int, alto int) bool {
+	return len(lineasPantalla(texto, ancho)) < alto
+}
+
+// Divide texto en líneas de como máximo ancho columnas.
+func lineasPantalla(texto string, ancho int) []string {
+	var lineas []string
+	for _, l := range strings.Split(strings.TrimSuffix(texto, "\n"), "\n") {
+		l = expandirTabulaciones(strings.TrimSuffix(l, "\r"))
+		for cadena.Ancho(l) > ancho {
+			cabeza, resto := cadena.Cortar(l, ancho)
+			lineas = append(lineas, cabeza)
+			l = resto
+		}
+		lineas = append(lineas, l)
+	}
+	return lineas
+}
+
+func (c consola) paginarInterno(texto string, ancho int, alto int) error {
+	p := &paginador{con: c, lineas: lineasPantalla(texto, ancho), alto: max(alto-1, 1)}
+	p.planas = make([]string, len(p.lineas))
+	for i, l := range p.lineas {
+		p.planas[i] = cadena.SinEstilos(l)
+	}
+
+	c.Salida.escribirDirecto(PANTALLA_ALTERNATIVA + OCULTAR_CURSOR)
+	defer c.Salida.escribirDirecto(MOSTRAR_CURSOR + PANTALLA_PRINCIPAL)
+	if c.HabilitarRaton() {
+		defer c.DeshabilitarRaton()
+	}
+
+	teclas := teclado.MapaDe("paginador", MAPA_PAGINADOR)
+	for {
+		if err := p.dibujar(""); err != nil {
+			return err
+		}
+		tecla, err := c.LeerEvento()
+		if err != nil {
+			return err
+		}
+		p.mensaje = ""
+		switch teclas.Accion(tecla) {
+		case "salir":
+			return nil
+		case "abajo":
+			p.mover(1)
+		case "arriba":
+			p.mover(-1)
+		case "desplazar-abajo":
+			p.mover(LINEAS_RUEDA)
+		case "desplazar-arriba":
+			p.mover(-LINEAS_RUEDA)
+		case "pagina-siguiente":
+			p.mover(p.alto)
+		case "pagina-anterior":
+			p.mover(-p.alto)
+		case "primera":
+			p.mover(-len(p.lineas))
+		case "ultima":
+			p.mover(len(p.lineas))
+		case "buscar":
+			busqueda, ok, err := p.leerBusqueda()
+			if err != nil {
+				return err
+			}
+			if ok && busqueda != "" {
+				p.busqueda = busqueda
+				p.buscar(1, p.desde)
+			}
+		case "coincidencia-siguiente":
+			p.buscar(1, p.desde+1)
+		case "coincidencia-anterior":
+			p.buscar(-1, p.desde-1)
+		}
+	}
+}
+
+func (p *paginador) mover(delta int) {
+	p.desde = min(max(p.desde+delta, 0), max(len(p.lineas)-p.alto, 0))
+}
+
+// Lleva a la primera línea de la pantalla la siguiente coincidencia de la búsqueda a partir de inicio en la dirección indicada.
+func (p *paginador) buscar(direccion int, inicio int) {
+	if p.busqueda == "" {
+		p.mensaje = "No hay búsqueda previa"
+		return
+	}
+	for i := inicio; i >= 0 && i < len(p.lineas); i += direccion {
+		if strings.Contains(p.planas[i], p.busqueda) {
+			p.desde = i
+			p.mover(0)
+			return
+		}
+	}
+	p.mensaje = "Patrón no encontrado: " + p.busqueda
+}
+
+// Lee el texto a buscar en la línea de estado. ok es false si se canceló con Esc.
+func (p *paginador) leerBusqueda() (busqueda string, ok bool, err error) {
+	for {
+		if err := p.dibujar("/" + busqueda); err != nil {
+			return "", false, err
+		}
+		tecla, err := p.con.LeerEvento()
+		if err != nil {
+			return "", false, err
+		}
+		switch {
+		case tecla.Codigo == teclado.CODIGO_ENTER:
+			return busqueda, true, nil
+		case tecla.Codigo == teclado.CODIGO_ESCAPE, tecla.EsCtrl('c'):
+			return "", false, nil
+		case tecla.Codigo == teclado.CODIGO_RETROCESO:
+			if r := []rune(busqueda); len(r) > 0 {
+				busqueda = string(r[:len(r)-1])
+			}
+		case tecla.Imprimible():
+			busqueda += string(tecla.Runa)
+		}
+	}
+}
+
+// Resalta las coincidencias de la búsqueda en la línea i. Las líneas con coincidencias pierden sus estilos propios.
+func (p paginador) linea(i int) string {
+	if p.busqueda == "" || !strings.Contains(p.planas[i], p.busqueda) {
+		return p.lineas[i]
+	}
+	resaltado := p.con.tema.Aplicar(cadena.ROL_SELECCION, p.busqueda).S()
+	return strings.ReplaceAll(p.planas[i], p.busqueda, resaltado)
+}
+
+// Dibuja la pantalla actual. Si indicador no está vacío se muestra en la línea de estado en lugar de la posición.
+func (p paginador) dibujar(indicador string) error {
+	var sb strings.Builder
+	sb.WriteString(string(teclado.CURSOR_CASA))
+	for i := p.desde; i < p.desde+p.alto; i++ {
+		if i < len(p.lineas) {
+			sb.WriteString(p.linea(i))
+		} else {
+			sb.WriteString("~")
+		}
+		sb.WriteString(BORRAR_HASTA_FIN + "\r\n")
+	}
+	switch {
+	case indicador != "":
+	case p.mensaje != "":
+		indicador = p.con.tema.Aplicar(cadena.ROL_ADVERTENCIA, p.mensaje).S()
+	default:
+		fin := min(p.desde+p.alto, len(p.lineas))
+		estado := fmt.Sprintf(" líneas %d-%d de %d (%d%%)", p.desde+1, fin, len(p.lineas), fin*100/max(len(p.lineas), 1))
+		if fin == len(p.lineas) {
+			estado += " (FIN)"
+		}
+		indicador = p.con.tema.Aplicar(cadena.ROL_SELECCION, estado+" · q para salir ").S()
+	}
+	sb.WriteString(indicador + BORRAR_HASTA_FIN)
+	return p.con.Salida.escribirDirecto(sb.String())
+}
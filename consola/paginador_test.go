@@ -0,0 +1,57 @@
+package consola
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPaginarExterno verifica que un $PAGER que no se puede ejecutar se informe, para paginar con el visor propio
+func TestPaginarExterno(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("usa sh")
+	}
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	con := NuevaConsola(os.Stdin, escritor)
+
+	assert.Error(t, con.paginarExterno("paginador-que-no-existe", "texto\n"))
+	assert.NoError(t, con.paginarExterno("false", "texto\n"), "un código del propio paginador no es un error")
+	assert.NoError(t, con.paginarExterno("cat", "texto\n"))
+	escritor.Close()
+
+	salida, _ := io.ReadAll(lector)
+	assert.Equal(t, "texto\n", string(salida))
+}
+
+// TestPaginarSinTerminal verifica que sin terminal el texto se imprima tal cual, sin $PAGER
+func TestPaginarSinTerminal(t *testing.T) {
+	t.Setenv("PAGER", "paginador-que-no-existe")
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	con := NuevaConsola(os.Stdin, escritor)
+
+	con.IniciarPaginado()
+	con.EscribirLinea("uno")
+	con.EscribirLinea("dos")
+	require.NoError(t, con.FinalizarPaginado())
+	escritor.Close()
+
+	salida, _ := io.ReadAll(lector)
+	assert.Equal(t, "uno\r\ndos\r\n", string(salida))
+}
+
+// TestEntraEnPantalla verifica que las líneas más anchas que la pantalla cuenten por cada línea que ocupan al partirse
+func TestEntraEnPantalla(t *testing.T) {
+	assert.True(t, entraEnPantalla("a\nb\n", 10, 3))
+	assert.False(t, entraEnPantalla("a\nb\nc\n", 10, 3))
+	assert.False(t, entraEnPantalla(strings.Repeat("x", 25)+"\n", 10, 3), "una línea de 25 columnas ocupa 3 en una pantalla de 10")
+	assert.True(t, entraEnPantalla(strings.Repeat("x", 15)+"\n", 10, 3))
+}
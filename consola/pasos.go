@@ -0,0 +1,419 @@
+/*
+Listas de pasos: ejecución de tareas secuenciales o concurrentes con una lista de estado que se actualiza en su lugar.
+*/
+
+package consola
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+)
+
+type EstadoPaso int
+
+const (
+	PASO_PENDIENTE EstadoPaso = iota
+	PASO_EN_CURSO
+	PASO_OK
+	PASO_FALLIDO
+	PASO_OMITIDO
+)
+
+// Tarea de un Paso. Puede devolver Omitir(motivo) para indicar que no hizo falta ejecutarla.
+type Tarea func(p *Paso) error
+
+type pasoOmitido struct {
+	motivo string
+}
+
+func (o pasoOmitido) Error() string {
+	return "omitido: " + o.motivo
+}
+
+// Devuelve el error que una Tarea retorna para marcar su Paso como omitido en lugar de fallido.
+//
+// # Ejemplo:
+//
+//	func(p *consola.Paso) error {
+//		if existe(destino) {
+//			return consola.Omitir("ya instalado")
+//		}
+//		return instalar(destino)
+//	}
+func Omitir(motivo string) error {
+	return pasoOmitido{motivo}
+}
+
+// Un paso de una ListaPasos. Puede tener una Tarea, subpasos o ambos: primero se ejecuta la tarea y, si no falla, los subpasos.
+type Paso struct {
+	Nombre string
+
+	lista        *ListaPasos
+	padre        *Paso
+	tarea        Tarea
+	subpasos     []*Paso
+	concurrencia int  // Subpasos simultáneos; 1 los ejecuta en orden y 0 no impone límite
+	continuar    bool // Seguir con los subpasos restantes aunque uno falle
+
+	// Protegidos por lista.mu
+	estado   EstadoPaso
+	err      error
+	propio   bool // El error es de la tarea del paso y no de sus subpasos
+	detalle  string
+	inicio   time.Time
+	duracion time.Duration
+}
+
+// Lista de pasos que se presenta como una lista de estado actualizada en su lugar mientras se ejecuta.
+// Si la Salida no es una terminal se registra una línea al iniciar y al terminar cada paso.
+//
+// # Ejemplo:
+//
+//	l := con.NuevaListaPasos()
+//	l.Agregar("Descargar dependencias", descargar)
+//	compilar := l.Agregar("Compilar", nil).Concurrencia(2)
+//	compilar.Agregar("cliente", compilarCliente)
+//	compilar.Agregar("servidor", compilarServidor)
+//	if err := l.Ejecutar(); err != nil {
+//		return nil, comando.ERROR, err
+//	}
+type ListaPasos struct {
+	raiz   *Paso
+	salida *Salida
+	tema   cadena.Tema
+
+	mu       sync.Mutex
+	lienzo   lienzo
+	mensajes []string
+	inicio   time.Time
+}
+
+// Crea una ListaPasos que escribe en s con el Tema t. Los pasos se ejecutan en orden salvo que se indique una Concurrencia.
+func NuevaListaPasos(s *Salida, t cadena.Tema) *ListaPasos {
+	l := &ListaPasos{salida: s, tema: t, lienzo: lienzo{salida: s}}
+	l.raiz = &Paso{lista: l, concurrencia: 1}
+	return l
+}
+
+// Agrega un paso al final de la lista.
+func (l *ListaPasos) Agregar(nombre string, t Tarea) *Paso {
+	return l.raiz.Agregar(nombre, t)
+}
+
+// Limita la cantidad de pasos de la lista que se ejecutan a la vez. Con 0 no hay límite.
+func (l *ListaPasos) Concurrencia(n int) *ListaPasos {
+	l.raiz.Concurrencia(n)
+	return l
+}
+
+// Sigue ejecutando los pasos restantes aunque uno falle.
+func (l *ListaPasos) ContinuarSiFalla() *ListaPasos {
+	l.raiz.ContinuarSiFalla()
+	return l
+}
+
+// Agrega un subpaso al final de los de p.
+func (p *Paso) Agregar(nombre string, t Tarea) *Paso {
+	sub := &Paso{Nombre: nombre, lista: p.lista, padre: p, tarea: t, concurrencia: 1}
+	p.subpasos = append(p.subpasos, sub)
+	return sub
+}
+
+// Limita la cantidad de subpasos de p que se ejecutan a la vez. Con 0 no hay límite.
+func (p *Paso) Concurrencia(n int) *Paso {
+	p.concurrencia = max(n, 0)
+	return p
+}
+
+// Sigue ejecutando los subpasos restantes de p aunque uno falle.
+func (p *Paso) ContinuarSiFalla() *Paso {
+	p.continuar = true
+	return p
+}
+
+// Muestra detalle junto al nombre del paso mientras está en curso, por ejemplo "3 de 10 archivos".
+func (p *Paso) Describir(detalle string) {
+	p.lista.mu.Lock()
+	defer p.lista.mu.Unlock()
+	p.detalle = detalle
+}
+
+// Escribe c por encima de la lista sin interrumpirla. Las tareas deben usarlo en lugar de escribir en la Consola.
+func (p *Paso) Mensaje(c Cadena) {
+	l := p.lista
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	linea := strings.TrimSuffix(c.S(), "\n")
+	if l.salida.esTerminal {
+		l.mensajes = append(l.mensajes, linea)
+		return
+	}
+	l.lienzo.escribir(linea + "\n")
+}
+
+// Devuelve el estado del paso.
+func (p *Paso) Estado() EstadoPaso {
+	p.lista.mu.Lock()
+	defer p.lista.mu.Unlock()
+	return p.estado
+}
+
+// Ejecuta los pasos, presenta la lista mientras tanto y al final un resumen con los tiempos y los errores.
+// Devuelve los errores de los pasos fallidos, cada uno precedido por el nombre del paso.
+func (l *ListaPasos) Ejecutar() error {
+	l.inicio = time.Now()
+	fin := make(chan struct{})
+	listo := make(chan struct{})
+	if l.salida.esTerminal {
+		go l.animar(fin, listo)
+	} else {
+		close(listo)
+	}
+
+	l.raiz.ejecutarSubpasos()
+
+	close(fin)
+	<-listo
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.salida.esTerminal {
+		l.lienzo.dibujar(l.mensajes, l.lineas(0), true)
+		l.mensajes = nil
+	}
+	l.lienzo.escribir(l.resumen())
+	return l.errores()
+}
+
+func (l *ListaPasos) animar(fin <-chan struct{}, listo chan<- struct{}) {
+	defer close(listo)
+	t := time.NewTicker(INTERVALO_ANIMACION)
+	defer t.Stop()
+	for cuadro := 0; ; cuadro++ {
+		l.mu.Lock()
+		l.lienzo.dibujar(l.mensajes, l.lineas(cuadro), false)
+		l.mensajes = nil
+		l.mu.Unlock()
+		select {
+		case <-fin:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (p *Paso) ejecutar() error {
+	p.lista.cambiar(p, PASO_EN_CURSO, nil)
+	var err error
+	if p.tarea != nil {
+		err = p.tarea(p)
+	}
+	propio := err != nil
+	if err == nil {
+		err = p.ejecutarSubpasos()
+	}
+	var omitido pasoOmitido
+	switch {
+	case errors.As(err, &omitido) && propio:
+		p.lista.cambiar(p, PASO_OMITIDO, err)
+		p.omitirSubpasos(omitido)
+		return nil
+	case err != nil:
+		p.lista.mu.Lock()
+		p.propio = propio
+		p.lista.mu.Unlock()
+		p.lista.cambiar(p, PASO_FALLIDO, err)
+		return err
+	}
+	p.lista.cambiar(p, PASO_OK, nil)
+	return nil
+}
+
+// Ejecuta los subpasos respetando la concurrencia. Si uno falla y no se indicó ContinuarSiFalla, los que no empezaron se omiten.
+func (p *Paso) ejecutarSubpasos() error {
+	if len(p.subpasos) == 0 {
+		return nil
+	}
+	limite := p.concurrencia
+	if limite <= 0 {
+		limite = len(p.subpasos)
+	}
+	lugares := make(chan struct{}, limite)
+	errs := make([]error, len(p.subpasos))
+	var fallo atomic.Bool
+	var wg sync.WaitGroup
+	for i, s := range p.subpasos {
+		lugares <- struct{}{}
+		if fallo.Load() && !p.continuar {
+			<-lugares
+			s.omitir(pasoOmitido{"falló un paso anterior"})
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-lugares }()
+			if err := s.ejecutar(); err != nil {
+				errs[i] = err
+				fallo.Store(true)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Marca p y sus subpasos como omitidos.
+func (p *Paso) omitir(motivo pasoOmitido) {
+	p.lista.cambiar(p, PASO_OMITIDO, motivo)
+	p.omitirSubpasos(motivo)
+}
+
+func (p *Paso) omitirSubpasos(motivo pasoOmitido) {
+	for _, s := range p.subpasos {
+		if s.Estado() == PASO_PENDIENTE {
+			s.omitir(motivo)
+		}
+	}
+}
+
+func (l *ListaPasos) cambiar(p *Paso, estado EstadoPaso, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p.estado, p.err = estado, err
+	if estado == PASO_EN_CURSO {
+		p.inicio = time.Now()
+	} else if !p.inicio.IsZero() {
+		p.duracion = time.Since(p.inicio)
+	}
+	if !l.salida.esTerminal {
+		if estado == PASO_EN_CURSO {
+			l.lienzo.escribir("… " + p.ruta() + "\n")
+		} else {
+			l.lienzo.escribir(p.linea(0, p.ruta()) + "\n")
+		}
+	}
+}
+
+// Nombres de los pasos desde el primer nivel hasta p, por ejemplo "Compilar › servidor".
+func (p *Paso) ruta() string {
+	var nombres []string
+	for s := p; s.padre != nil; s = s.padre {
+		nombres = append([]string{s.Nombre}, nombres...)
+	}
+	return strings.Join(nombres, " › ")
+}
+
+// Presenta todos los pasos, con los subpasos sangrados bajo su paso. Se llama con l.mu tomado.
+func (l *ListaPasos) lineas(cuadro int) []string {
+	var lineas []string
+	var recorrer func(p *Paso, nivel int)
+	recorrer = func(p *Paso, nivel int) {
+		for _, s := range p.subpasos {
+			lineas = append(lineas, strings.Repeat("  ", nivel)+s.linea(cuadro, s.Nombre))
+			recorrer(s, nivel+1)
+		}
+	}
+	recorrer(l.raiz, 0)
+	return lineas
+}
+
+// Presenta el paso con su estado. Se llama con lista.mu tomado.
+func (p *Paso) linea(cuadro int, nombre string) string {
+	t := p.lista.tema
+	atenuado := t.Formateador(cadena.ROL_ATENUADO)
+	switch p.estado {
+	case PASO_EN_CURSO:
+		linea := t.Aplicar(cadena.ROL_OK, ANIMACION_PUNTOS[cuadro%len(ANIMACION_PUNTOS)]).S() + " " + nombre
+		if p.detalle != "" {
+			linea += " " + atenuado(p.detalle)
+		}
+		return linea + " " + atenuado(duracion(time.Since(p.inicio)))
+	case PASO_OK:
+		return t.Aplicar(cadena.ROL_OK, "✓").S() + " " + nombre + " " + atenuado(duracion(p.duracion))
+	case PASO_FALLIDO:
+		linea := t.Aplicar(cadena.ROL_ERROR, "✕").S() + " " + nombre + " " + atenuado(duracion(p.duracion))
+		if p.propio {
+			primera, _, _ := strings.Cut(p.err.Error(), "\n")
+			linea += " " + t.Aplicar(cadena.ROL_ERROR, primera).S()
+		}
+		return linea
+	case PASO_OMITIDO:
+		return atenuado("- "+nombre) + " " + atenuado("("+p.err.Error()+")")
+	}
+	return atenuado("○ " + nombre)
+}
+
+// Recorre los pasos (sin la raíz) en orden.
+func (l *ListaPasos) pasos() []*Paso {
+	var pasos []*Paso
+	var recorrer func(p *Paso)
+	recorrer = func(p *Paso) {
+		for _, s := range p.subpasos {
+			pasos = append(pasos, s)
+			recorrer(s)
+		}
+	}
+	recorrer(l.raiz)
+	return pasos
+}
+
+// Resumen de la ejecución: cantidad de pasos por estado, tiempo total y los errores de los pasos fallidos. Se llama con l.mu tomado.
+func (l *ListaPasos) resumen() string {
+	t := l.tema
+	cuenta := map[EstadoPaso]int{}
+	var fallidos []*Paso
+	for _, p := range l.pasos() {
+		if len(p.subpasos) > 0 && !p.propio {
+			continue // Los pasos que sólo agrupan se cuentan a través de sus subpasos
+		}
+		cuenta[p.estado]++
+		if p.estado == PASO_FALLIDO {
+			fallidos = append(fallidos, p)
+		}
+	}
+	partes := []string{cantidadPasos(cuenta[PASO_OK], "correcto")}
+	if cuenta[PASO_FALLIDO] > 0 {
+		partes = append(partes, t.Aplicar(cadena.ROL_ERROR, cantidadPasos(cuenta[PASO_FALLIDO], "fallido")).S())
+	}
+	if cuenta[PASO_OMITIDO] > 0 {
+		partes = append(partes, cantidadPasos(cuenta[PASO_OMITIDO], "omitido"))
+	}
+	var sb strings.Builder
+	sb.WriteString("\n" + strings.Join(partes, " · ") + " " + t.Aplicar(cadena.ROL_ATENUADO, "en "+duracion(time.Since(l.inicio))).S() + "\n")
+	for _, p := range fallidos {
+		sb.WriteString("\n" + t.Aplicar(cadena.ROL_ERROR, "✕ "+p.ruta()).S() + "\n")
+		for _, l := range strings.Split(p.err.Error(), "\n") {
+			sb.WriteString("    " + l + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func cantidadPasos(n int, estado string) string {
+	if n == 1 {
+		return "1 " + estado
+	}
+	return fmt.Sprintf("%d %ss", n, estado)
+}
+
+// Errores de los pasos que fallaron por su propia tarea. Se llama con l.mu tomado.
+func (l *ListaPasos) errores() error {
+	var errs []error
+	for _, p := range l.pasos() {
+		if p.estado == PASO_FALLIDO && p.propio {
+			errs = append(errs, fmt.Errorf("%s: %w", p.ruta(), p.err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Crea una ListaPasos en la Salida de la Consola, con su Tema.
+func (c consola) NuevaListaPasos() *ListaPasos {
+	return NuevaListaPasos(&c.Salida, c.tema)
+}
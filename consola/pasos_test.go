@@ -0,0 +1,58 @@
+package consola_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListaPasos verifica el orden, la concurrencia, las omisiones y el resumen de una lista de pasos sin terminal
+func TestListaPasos(t *testing.T) {
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	con := consola.NuevaConsola(os.Stdin, escritor)
+
+	var simultaneos, maximo atomic.Int32
+	tarea := func(p *consola.Paso) error {
+		n := simultaneos.Add(1)
+		defer simultaneos.Add(-1)
+		for {
+			m := maximo.Load()
+			if n <= m || maximo.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		return nil
+	}
+
+	l := con.NuevaListaPasos()
+	l.Agregar("preparar", func(p *consola.Paso) error { return consola.Omitir("ya preparado") })
+	compilar := l.Agregar("compilar", nil).Concurrencia(2)
+	for _, n := range []string{"a", "b", "c", "d"} {
+		compilar.Agregar(n, tarea)
+	}
+	l.Agregar("probar", func(p *consola.Paso) error { return errors.New("3 pruebas fallaron") })
+	publicar := l.Agregar("publicar", nil)
+	publicar.Agregar("subir", tarea)
+
+	err = l.Ejecutar()
+	escritor.Close()
+	salida, _ := io.ReadAll(lector)
+
+	require.Error(t, err)
+	assert.Equal(t, "probar: 3 pruebas fallaron", err.Error())
+	assert.LessOrEqual(t, maximo.Load(), int32(2))
+	assert.Equal(t, consola.PASO_OMITIDO, publicar.Estado())
+	assert.Contains(t, string(salida), "- preparar (omitido: ya preparado)\n")
+	assert.Contains(t, string(salida), "✓ compilar › a ")
+	assert.Contains(t, string(salida), "- publicar › subir (omitido: falló un paso anterior)\n")
+	assert.Contains(t, string(salida), "4 correctos · 1 fallido · 2 omitidos en ")
+	assert.Contains(t, string(salida), "✕ probar\n    3 pruebas fallaron\n")
+}
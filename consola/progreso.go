@@ -0,0 +1,381 @@
+/*
+Barras de progreso, indicadores de espera y grupos de barras para tareas concurrentes.
+*/
+
+package consola
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+)
+
+// Cuadros de la animación de un indicador de espera.
+type Animacion []string
+
+var (
+	ANIMACION_PUNTOS  = Animacion{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	ANIMACION_LINEA   = Animacion{"-", "\\", "|", "/"}
+	ANIMACION_CIRCULO = Animacion{"◐", "◓", "◑", "◒"}
+	ANIMACION_FLECHA  = Animacion{"←", "↖", "↑", "↗", "→", "↘", "↓", "↙"}
+	ANIMACION_BLOQUE  = Animacion{"▁", "▃", "▄", "▅", "▆", "▇", "█", "▇", "▆", "▅", "▄", "▃"}
+)
+
+const (
+	INTERVALO_ANIMACION = 100 * time.Millisecond
+	INTERVALO_REGISTRO  = 5 * time.Second // Cada cuánto se registra el avance si la Salida no es una terminal
+	UNIDAD_BYTES        = "B"
+)
+
+// Opciones de una barra de progreso.
+type OpcionesProgreso struct {
+	Descripcion string
+	Total       int64     // Con 0 o menos la barra es un indicador de espera (sin porcentaje)
+	Unidad      string    // Se muestra junto a las cantidades; con UNIDAD_BYTES se usan KiB, MiB...
+	Animacion   Animacion // Para los indicadores de espera; por defecto ANIMACION_PUNTOS
+	AnchoBarra  int       // Por defecto se ajusta al ancho de la terminal (hasta 40 columnas)
+}
+
+// Una barra de progreso o un indicador de espera dentro de un GrupoProgreso. Se puede actualizar desde varias goroutines.
+// Implementa io.Writer sumando los bytes escritos, para usarla con io.Copy o io.TeeReader.
+type BarraProgreso struct {
+	grupo    *GrupoProgreso
+	opciones OpcionesProgreso
+	actual   atomic.Int64
+	total    atomic.Int64
+	inicio   time.Time
+
+	// Protegidos por grupo.mu
+	terminada bool
+	err       error
+	duracion  time.Duration
+}
+
+// Agrupa barras que se presentan juntas, una por línea, y se redibujan en su lugar.
+// Si la Salida no es una terminal, en lugar de animarse se registra el avance en una línea cada Intervalo.
+type GrupoProgreso struct {
+	salida    *Salida
+	tema      cadena.Tema
+	Intervalo time.Duration
+
+	mu       sync.Mutex
+	lienzo   lienzo
+	barras   []*BarraProgreso
+	mensajes []string
+	registro time.Time     // Último registro del avance (sin terminal)
+	fin      chan struct{} // Se cierra cuando terminan todas las barras; nil si no hay ninguna en curso
+}
+
+// Crea un GrupoProgreso que escribe en s con el Tema t.
+//
+// # Ejemplo:
+//
+//	g := consola.NuevoGrupoProgreso(consola.NuevaSalida(os.Stderr), cadena.TemaPredeterminado)
+//	for _, a := range archivos {
+//		b := g.Agregar(consola.OpcionesProgreso{Descripcion: a.Nombre, Total: a.Tamaño, Unidad: consola.UNIDAD_BYTES})
+//		go descargar(a, b)
+//	}
+//	g.Esperar()
+func NuevoGrupoProgreso(s *Salida, t cadena.Tema) *GrupoProgreso {
+	return &GrupoProgreso{salida: s, tema: t, Intervalo: INTERVALO_REGISTRO, lienzo: lienzo{salida: s}}
+}
+
+// Agrega una barra al grupo y la empieza a presentar.
+func (g *GrupoProgreso) Agregar(o OpcionesProgreso) *BarraProgreso {
+	if len(o.Animacion) == 0 {
+		o.Animacion = ANIMACION_PUNTOS
+	}
+	b := &BarraProgreso{grupo: g, opciones: o, inicio: time.Now()}
+	b.total.Store(o.Total)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.barras = append(g.barras, b)
+	if !g.salida.esTerminal {
+		g.escribir(o.Descripcion + "...\n")
+	}
+	if g.fin == nil {
+		g.fin = make(chan struct{})
+		g.registro = time.Now()
+		go g.animar(g.fin)
+	}
+	return b
+}
+
+// Escribe c por encima de las barras del grupo sin interrumpirlas.
+func (g *GrupoProgreso) Mensaje(c Cadena) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.fin == nil || !g.salida.esTerminal {
+		g.escribir(strings.TrimSuffix(c.S(), "\n") + "\n")
+		return
+	}
+	g.mensajes = append(g.mensajes, strings.TrimSuffix(c.S(), "\n"))
+}
+
+// Espera a que terminen todas las barras del grupo.
+func (g *GrupoProgreso) Esperar() {
+	g.mu.Lock()
+	fin := g.fin
+	g.mu.Unlock()
+	if fin != nil {
+		<-fin
+	}
+}
+
+func (g *GrupoProgreso) animar(fin chan struct{}) {
+	t := time.NewTicker(INTERVALO_ANIMACION)
+	defer t.Stop()
+	for cuadro := 0; ; cuadro++ {
+		if g.actualizar(fin, cuadro) {
+			return
+		}
+		<-t.C
+	}
+}
+
+// Presenta un cuadro del grupo. Devuelve true si la tanda de barras de fin ya terminó.
+func (g *GrupoProgreso) actualizar(fin chan struct{}, cuadro int) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.fin == fin {
+		g.presentar(cuadro)
+	}
+	return g.fin != fin
+}
+
+// Presenta el estado del grupo. Si terminaron todas las barras dibuja el cuadro final, deja el grupo vacío y cierra
+// fin. Se llama con g.mu tomado.
+func (g *GrupoProgreso) presentar(cuadro int) {
+	terminadas := true
+	for _, b := range g.barras {
+		terminadas = terminadas && b.terminada
+	}
+	if g.salida.esTerminal {
+		lineas := make([]string, len(g.barras))
+		for i, b := range g.barras {
+			lineas[i] = b.linea(cuadro, g.lienzo.ancho(), g.tema)
+		}
+		g.lienzo.dibujar(g.mensajes, lineas, terminadas)
+		g.mensajes = nil
+	} else if time.Since(g.registro) >= g.Intervalo {
+		g.registro = time.Now()
+		for _, b := range g.barras {
+			if !b.terminada {
+				g.escribir(b.registro() + "\n")
+			}
+		}
+	}
+	if terminadas {
+		close(g.fin)
+		g.barras, g.fin = nil, nil
+	}
+}
+
+// Escribe s directamente en la Salida (sin pasar por el paginado). Se llama con g.mu tomado.
+func (g *GrupoProgreso) escribir(s string) {
+	g.lienzo.escribir(s)
+}
+
+// Suma n al avance de la barra.
+func (b *BarraProgreso) Agregar(n int64) {
+	b.actual.Add(n)
+}
+
+// Suma 1 al avance de la barra.
+func (b *BarraProgreso) Incrementar() {
+	b.actual.Add(1)
+}
+
+// Establece el avance de la barra.
+func (b *BarraProgreso) Establecer(n int64) {
+	b.actual.Store(n)
+}
+
+// Cambia el total de la barra. Con 0 o menos pasa a ser un indicador de espera.
+func (b *BarraProgreso) AsignarTotal(n int64) {
+	b.total.Store(n)
+}
+
+// Cambia la descripción de la barra.
+func (b *BarraProgreso) Describir(descripcion string) {
+	b.grupo.mu.Lock()
+	defer b.grupo.mu.Unlock()
+	b.opciones.Descripcion = descripcion
+}
+
+// Devuelve el avance de la barra.
+func (b *BarraProgreso) Actual() int64 {
+	return b.actual.Load()
+}
+
+func (b *BarraProgreso) Write(p []byte) (int, error) {
+	b.actual.Add(int64(len(p)))
+	return len(p), nil
+}
+
+// Marca la barra como completa.
+func (b *BarraProgreso) Finalizar() {
+	b.terminar(nil)
+}
+
+// Marca la barra como fallida con err.
+func (b *BarraProgreso) Fallar(err error) {
+	b.terminar(err)
+}
+
+func (b *BarraProgreso) terminar(err error) {
+	g := b.grupo
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if b.terminada {
+		return
+	}
+	b.terminada, b.err, b.duracion = true, err, time.Since(b.inicio)
+	if total := b.total.Load(); err == nil && total > 0 {
+		b.actual.Store(total)
+	}
+	if !g.salida.esTerminal {
+		g.escribir(b.registro() + "\n")
+	}
+	// La última barra en terminar dibuja el cuadro final antes de volver, para que quede antes de lo que se escriba después.
+	for _, o := range g.barras {
+		if !o.terminada {
+			return
+		}
+	}
+	g.presentar(0)
+}
+
+// Presenta la barra en una línea de como máximo ancho columnas.
+func (b *BarraProgreso) linea(cuadro int, ancho int, t cadena.Tema) string {
+	atenuado := t.Formateador(cadena.ROL_ATENUADO)
+	actual, total := b.actual.Load(), b.total.Load()
+	switch {
+	case b.terminada && b.err != nil:
+		return t.Aplicar(cadena.ROL_ERROR, "✗").S() + " " + b.opciones.Descripcion + ": " + t.Aplicar(cadena.ROL_ERROR, b.err.Error()).S()
+	case b.terminada:
+		return t.Aplicar(cadena.ROL_OK, "✓").S() + " " + b.opciones.Descripcion + " " + atenuado(b.resumen())
+	case total <= 0:
+		cuadros := b.opciones.Animacion
+		estado := duracion(time.Since(b.inicio))
+		if actual > 0 {
+			estado = cantidad(actual, b.opciones.Unidad) + " · " + estado
+		}
+		return t.Aplicar(cadena.ROL_OK, cuadros[cuadro%len(cuadros)]).S() + " " + b.opciones.Descripcion + " " + atenuado(estado)
+	}
+	fraccion := min(float64(actual)/float64(total), 1)
+	porcentaje := fmt.Sprintf("%3.0f%%", fraccion*100)
+	estado := b.estadistica()
+	anchoBarra := b.opciones.AnchoBarra
+	if anchoBarra <= 0 {
+		// Se reserva lugar fijo para las estadísticas para que la barra no cambie de largo entre cuadros.
+		anchoBarra = min(max(ancho-cadena.Ancho(b.opciones.Descripcion)-cadena.Ancho(porcentaje)-max(cadena.Ancho(estado), 36)-3, 10), 40)
+	}
+	llenos := int(fraccion * float64(anchoBarra))
+	barra := t.Aplicar(cadena.ROL_OK, strings.Repeat("█", llenos)).S() + atenuado(strings.Repeat("░", anchoBarra-llenos))
+	return b.opciones.Descripcion + " " + barra + " " + porcentaje + " " + atenuado(estado)
+}
+
+// Cantidades, ritmo y tiempo restante, por ejemplo "450/1000 · 12.3/s · ETA 45s".
+func (b *BarraProgreso) estadistica() string {
+	actual, total := b.actual.Load(), b.total.Load()
+	partes := []string{cantidad(actual, b.opciones.Unidad) + "/" + cantidad(total, b.opciones.Unidad)}
+	// El ritmo de las primeras décimas de segundo no es representativo.
+	if transcurrido := time.Since(b.inicio).Seconds(); transcurrido >= 0.5 && actual > 0 {
+		ritmo := float64(actual) / transcurrido
+		partes = append(partes, ritmoTexto(ritmo, b.opciones.Unidad))
+		if total > actual {
+			partes = append(partes, "ETA "+duracion(time.Duration(float64(total-actual)/ritmo*float64(time.Second))))
+		}
+	}
+	return strings.Join(partes, " · ")
+}
+
+// Resumen de una barra terminada, por ejemplo "1000/1000 · 3s".
+func (b *BarraProgreso) resumen() string {
+	if total := b.total.Load(); total > 0 {
+		return cantidad(b.actual.Load(), b.opciones.Unidad) + "/" + cantidad(total, b.opciones.Unidad) + " · " + duracion(b.duracion)
+	}
+	if actual := b.actual.Load(); actual > 0 {
+		return cantidad(actual, b.opciones.Unidad) + " · " + duracion(b.duracion)
+	}
+	return duracion(b.duracion)
+}
+
+// Línea sin estilos para registrar el avance cuando la Salida no es una terminal.
+func (b *BarraProgreso) registro() string {
+	switch {
+	case b.terminada && b.err != nil:
+		return b.opciones.Descripcion + ": error: " + b.err.Error()
+	case b.terminada:
+		return b.opciones.Descripcion + ": listo (" + b.resumen() + ")"
+	case b.total.Load() > 0:
+		fraccion := min(float64(b.actual.Load())/float64(b.total.Load()), 1)
+		return fmt.Sprintf("%s: %.0f%% (%s)", b.opciones.Descripcion, fraccion*100, b.estadistica())
+	}
+	return b.opciones.Descripcion + ": en curso (" + b.resumen() + ")"
+}
+
+func duracion(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
+
+// Presenta n con su unidad. Los bytes se presentan con el múltiplo binario más adecuado.
+func cantidad(n int64, unidad string) string {
+	if unidad != UNIDAD_BYTES {
+		return strings.TrimSpace(fmt.Sprintf("%d %s", n, unidad))
+	}
+	return bytesTexto(float64(n))
+}
+
+func ritmoTexto(ritmo float64, unidad string) string {
+	if unidad == UNIDAD_BYTES {
+		return bytesTexto(ritmo) + "/s"
+	}
+	return strings.TrimSpace(fmt.Sprintf("%.1f %s", ritmo, unidad)) + "/s"
+}
+
+func bytesTexto(n float64) string {
+	unidades := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for n >= 1024 && i < len(unidades)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", n, unidades[i])
+	}
+	return fmt.Sprintf("%.1f %s", n, unidades[i])
+}
+
+// Crea una barra de progreso con total pasos en la Salida de la Consola.
+//
+// # Ejemplo:
+//
+//	b := con.NuevaBarra("Procesando", int64(len(elementos)))
+//	for _, e := range elementos {
+//		procesar(e)
+//		b.Incrementar()
+//	}
+//	b.Finalizar()
+func (c consola) NuevaBarra(descripcion string, total int64) *BarraProgreso {
+	return c.NuevoGrupoProgreso().Agregar(OpcionesProgreso{Descripcion: descripcion, Total: total})
+}
+
+// Crea un indicador de espera (sin total) en la Salida de la Consola.
+func (c consola) NuevaEspera(descripcion string) *BarraProgreso {
+	return c.NuevoGrupoProgreso().Agregar(OpcionesProgreso{Descripcion: descripcion})
+}
+
+// Crea un GrupoProgreso en la Salida de la Consola, con su Tema.
+func (c consola) NuevoGrupoProgreso() *GrupoProgreso {
+	return NuevoGrupoProgreso(&c.Salida, c.tema)
+}
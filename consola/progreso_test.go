@@ -0,0 +1,37 @@
+package consola_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProgresoSinTerminal verifica que sin terminal el progreso se registre en líneas en lugar de animarse
+func TestProgresoSinTerminal(t *testing.T) {
+	lector, escritor, err := os.Pipe()
+	require.NoError(t, err)
+	defer lector.Close()
+	con := consola.NuevaConsola(os.Stdin, escritor)
+
+	g := con.NuevoGrupoProgreso()
+	copia := g.Agregar(consola.OpcionesProgreso{Descripcion: "copia", Total: 2048, Unidad: consola.UNIDAD_BYTES})
+	indice := g.Agregar(consola.OpcionesProgreso{Descripcion: "índice"})
+	_, err = io.WriteString(copia, "datos")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), copia.Actual())
+	copia.Finalizar()
+	indice.Fallar(errors.New("sin espacio"))
+	g.Esperar()
+	escritor.Close()
+
+	salida, _ := io.ReadAll(lector)
+	assert.Contains(t, string(salida), "copia...\níndice...\n")
+	assert.Contains(t, string(salida), "copia: listo (2.0 KiB/2.0 KiB · ")
+	assert.Contains(t, string(salida), "índice: error: sin espacio\n")
+	assert.NotContains(t, string(salida), "\x1b[")
+}
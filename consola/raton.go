@@ -0,0 +1,98 @@
+package consola
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"golang.org/x/term"
+)
+
+// Tiempo que se espera la respuesta de la terminal a PosicionCursor.
+const ESPERA_POSICION = 300 * time.Millisecond
+
+// Uso del ratón en una Salida: si se pidió (UsarRaton) y cuántos componentes lo tienen habilitado.
+type estadoRaton struct {
+	sync.Mutex
+	usar    bool
+	activos int
+}
+
+// Indica si los componentes interactivos (menús, visores) deben responder al ratón. Por defecto no lo hacen.
+// Con false se desactiva de inmediato el informe de eventos, aunque algún componente lo tenga habilitado.
+func (c consola) UsarRaton(usar bool) {
+	r := c.Salida.raton
+	r.Lock()
+	defer r.Unlock()
+	r.usar = usar
+	if !usar && r.activos > 0 {
+		r.activos = 0
+		c.ImprimirBytes([]byte(teclado.DESACTIVAR_RATON))
+	}
+}
+
+// Activa el informe de eventos del ratón (ver teclado.Raton) si se pidió con UsarRaton y la salida es una terminal.
+// Devuelve true si quedó activo; en ese caso se debe llamar DeshabilitarRaton al terminar, por ejemplo con defer.
+// Las llamadas se pueden anidar: el informe se desactiva con el último DeshabilitarRaton.
+func (c consola) HabilitarRaton() bool {
+	r := c.Salida.raton
+	r.Lock()
+	defer r.Unlock()
+	if !r.usar || !c.Salida.esTerminal {
+		return false
+	}
+	if r.activos == 0 {
+		c.ImprimirBytes([]byte(teclado.ACTIVAR_RATON))
+	}
+	r.activos++
+	return true
+}
+
+func (c consola) DeshabilitarRaton() {
+	r := c.Salida.raton
+	r.Lock()
+	defer r.Unlock()
+	if r.activos == 0 {
+		return
+	}
+	if r.activos--; r.activos == 0 {
+		c.ImprimirBytes([]byte(teclado.DESACTIVAR_RATON))
+	}
+}
+
+// Devuelve la fila y la columna del cursor en la terminal, desde 1. Las teclas que lleguen mientras se espera
+// la respuesta quedan para LeerEvento.
+func (c consola) PosicionCursor() (int, int, error) {
+	if viejo, err := term.MakeRaw(int(c.EntradaSalida.Entrada.Fd())); err == nil {
+		defer term.Restore(int(c.EntradaSalida.Entrada.Fd()), viejo)
+	}
+	if err := c.ImprimirBytes([]byte(teclado.PEDIR_POSICION)); err != nil {
+		return 0, 0, err
+	}
+	return c.Entrada.eventos.LeerPosicion(ESPERA_POSICION)
+}
+
+// Habilita el ratón en con (ver HabilitarRaton) y devuelve la fila de la terminal en la que está el cursor, que es
+// donde empieza lo que se presente a continuación, o 0 si no se conoce. Si habilitado es true, hay que llamar a
+// con.DeshabilitarRaton (ver Extender) al terminar.
+func UbicarComponente(con Consola) (fila int, habilitado bool) {
+	e := Extender(con)
+	if !e.HabilitarRaton() {
+		return 0, false
+	}
+	fila, _, err := e.PosicionCursor()
+	if err != nil {
+		return 0, true
+	}
+	return fila, true
+}
+
+// Devuelve la fila en la que quedó la primera de las lineas presentadas a partir de fila, que sube si la terminal se
+// desplazó para mostrarlas todas. Con fila 0 (desconocida) devuelve 0.
+func FilaPresentada(con Consola, fila int, lineas int) int {
+	_, alto, err := Extender(con).DevolverTamaño()
+	if fila <= 0 || err != nil || alto <= 0 {
+		return fila
+	}
+	return max(min(fila, alto-lineas), 1)
+}
@@ -0,0 +1,117 @@
+package teclado
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Tiempo que se espera tras un ESC antes de considerarlo la tecla Esc y no el comienzo de una secuencia.
+const ESPERA_ESCAPE = 50 * time.Millisecond
+
+// Pide a la terminal la posición del cursor; responde ESC [ fila ; columna R (ver Decodificador.LeerPosicion).
+const PEDIR_POSICION = "\x1b[6n"
+
+// Decodificador lee bytes de una terminal en modo crudo y los devuelve de a una Tecla por vez.
+//
+// Los bytes leídos de más (por ejemplo, al escribir rápido o al pegar) quedan pendientes para las siguientes lecturas.
+// Un ESC solo se resuelve esperando Espera: si en ese tiempo no llega nada, es la tecla Esc.
+type Decodificador struct {
+	Espera time.Duration
+
+	leer      func([]byte) (int, error)
+	hayMas    func(time.Duration) bool
+	pendiente []byte
+	cola      []Tecla // Teclas leídas mientras se esperaba la posición del cursor
+}
+
+// Crea un *Decodificador que lee con leer. hayMas debe devolver true si hay bytes para leer antes de que pase la espera;
+// si es nil, las secuencias incompletas se interpretan sin esperar.
+func NuevoDecodificador(leer func([]byte) (int, error), hayMas func(time.Duration) bool) *Decodificador {
+	return &Decodificador{
+		Espera: ESPERA_ESCAPE,
+		leer:   leer,
+		hayMas: hayMas,
+	}
+}
+
+// Devuelve true si quedan bytes leídos sin decodificar.
+func (d Decodificador) Pendiente() bool {
+	return len(d.pendiente) > 0 || len(d.cola) > 0
+}
+
+// Lee la próxima tecla. Sólo bloquea si no hay bytes pendientes.
+func (d *Decodificador) Leer() (Tecla, error) {
+	if len(d.cola) > 0 {
+		t := d.cola[0]
+		d.cola = d.cola[1:]
+		return t, nil
+	}
+	return d.leerTecla()
+}
+
+// Lee la respuesta a PEDIR_POSICION (ESC [ fila ; columna R), que se debe haber escrito antes. Las teclas que lleguen
+// antes de la respuesta quedan para Leer. Devuelve un error si la respuesta no llega en espera.
+func (d *Decodificador) LeerPosicion(espera time.Duration) (fila int, columna int, err error) {
+	for {
+		if len(d.pendiente) == 0 && (d.hayMas == nil || !d.hayMas(espera)) {
+			return 0, 0, errors.New("la terminal no informó la posición del cursor")
+		}
+		t, err := d.leerTecla()
+		if err != nil {
+			return 0, 0, err
+		}
+		if _, err := fmt.Sscanf(string(t.Secuencia), "\x1b[%d;%dR", &fila, &columna); err == nil {
+			return fila, columna, nil
+		}
+		d.cola = append(d.cola, t)
+	}
+}
+
+// Lee bytes sin decodificar: primero los ya leídos (de las teclas en espera y los pendientes) y, si no hay, de la entrada.
+// Permite alternar Leer con lecturas crudas sin perder bytes.
+func (d *Decodificador) LeerBytes(b []byte) (int, error) {
+	if len(d.cola) > 0 {
+		var leidos []byte
+		for _, t := range d.cola {
+			leidos = append(leidos, t.Secuencia...)
+		}
+		d.pendiente, d.cola = append(leidos, d.pendiente...), nil
+	}
+	if len(d.pendiente) > 0 {
+		n := copy(b, d.pendiente)
+		d.pendiente = d.pendiente[n:]
+		return n, nil
+	}
+	return d.leer(b)
+}
+
+func (d *Decodificador) leerTecla() (Tecla, error) {
+	buf := make([]byte, 64)
+	for {
+		if len(d.pendiente) > 0 {
+			if t, n, completa := Decodificar(d.pendiente, false); completa {
+				d.pendiente = d.pendiente[n:]
+				return t, nil
+			}
+			// Secuencia incompleta: si no llega el resto a tiempo, se interpreta lo que haya.
+			if d.hayMas == nil || !d.hayMas(d.Espera) {
+				return d.forzar(), nil
+			}
+		}
+		n, err := d.leer(buf)
+		d.pendiente = append(d.pendiente, buf[:n]...)
+		if err != nil {
+			if len(d.pendiente) > 0 {
+				return d.forzar(), nil
+			}
+			return Tecla{}, err
+		}
+	}
+}
+
+func (d *Decodificador) forzar() Tecla {
+	t, n, _ := Decodificar(d.pendiente, true)
+	d.pendiente = d.pendiente[n:]
+	return t
+}
@@ -0,0 +1,267 @@
+package teclado
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Acción que se desactiva al asignarla a una tecla: la tecla deja de hacer algo en el mapa.
+const SIN_ACCION = "ninguna"
+
+// Mapa asocia teclas, escritas como las devuelve Tecla.String ("ctrl+a", "shift+tab", "f5", "q"), con nombres de acción.
+//
+// # Ejemplo:
+//
+//	teclas := teclado.NuevoMapa("enter", "aceptar", "q", "salir", "esc", "salir")
+//	switch teclas.Accion(tecla) {
+//	case "aceptar":
+//		...
+//	}
+type Mapa map[string]string
+
+// Construye un Mapa a partir de pares tecla, acción. Las teclas se normalizan con ParsearTecla; paniquea si alguna es inválida.
+func NuevoMapa(pares ...string) Mapa {
+	m := Mapa{}
+	for i := 0; i+1 < len(pares); i += 2 {
+		if err := m.Asignar(pares[i], pares[i+1]); err != nil {
+			panic(err)
+		}
+	}
+	return m
+}
+
+// Devuelve la acción asociada a t, o "" si no tiene.
+func (m Mapa) Accion(t Tecla) string {
+	if a := m[t.String()]; a != SIN_ACCION {
+		return a
+	}
+	return ""
+}
+
+// Asocia tecla (por ejemplo "ctrl+x" o "alt+enter") con accion. Con accion SIN_ACCION, la tecla queda desactivada.
+func (m Mapa) Asignar(tecla string, accion string) error {
+	t, err := ParsearTecla(tecla)
+	if err != nil {
+		return err
+	}
+	m[t.String()] = accion
+	return nil
+}
+
+// Devuelve las teclas asociadas a accion, de la más corta a la más larga (para mostrar la más simple en las ayudas).
+func (m Mapa) Teclas(accion string) []string {
+	teclas := []string{}
+	for t, a := range m {
+		if a == accion {
+			teclas = append(teclas, t)
+		}
+	}
+	slices.SortFunc(teclas, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(a, b))
+	})
+	return teclas
+}
+
+// Devuelve un Mapa nuevo con las asociaciones de m, reemplazadas por las de cada uno de otros en orden.
+// Las teclas con SIN_ACCION en otros quedan desactivadas.
+func (m Mapa) Combinar(otros ...Mapa) Mapa {
+	r := maps.Clone(m)
+	if r == nil {
+		r = Mapa{}
+	}
+	for _, o := range otros {
+		maps.Copy(r, o)
+	}
+	return r
+}
+
+var nombresTeclas = map[string]Codigo{
+	"enter": CODIGO_ENTER, "intro": CODIGO_ENTER, "return": CODIGO_ENTER,
+	"tab":       CODIGO_TAB,
+	"retroceso": CODIGO_RETROCESO, "backspace": CODIGO_RETROCESO,
+	"esc": CODIGO_ESCAPE, "escape": CODIGO_ESCAPE,
+	"arriba": CODIGO_ARRIBA, "up": CODIGO_ARRIBA,
+	"abajo": CODIGO_ABAJO, "down": CODIGO_ABAJO,
+	"derecha": CODIGO_DERECHA, "right": CODIGO_DERECHA,
+	"izquierda": CODIGO_IZQUIERDA, "left": CODIGO_IZQUIERDA,
+	"inicio": CODIGO_INICIO, "home": CODIGO_INICIO,
+	"fin": CODIGO_FIN, "end": CODIGO_FIN,
+	"repag": CODIGO_REPAG, "pgup": CODIGO_REPAG,
+	"avpag": CODIGO_AVPAG, "pgdn": CODIGO_AVPAG,
+	"insertar": CODIGO_INSERTAR, "insert": CODIGO_INSERTAR,
+	"suprimir": CODIGO_SUPRIMIR, "supr": CODIGO_SUPRIMIR, "delete": CODIGO_SUPRIMIR,
+	"pegado": CODIGO_PEGADO, "paste": CODIGO_PEGADO,
+}
+
+var nombresModificadores = map[string]Modificador{
+	"shift": MOD_SHIFT, "mayus": MOD_SHIFT,
+	"alt": MOD_ALT, "opt": MOD_ALT,
+	"ctrl": MOD_CTRL, "control": MOD_CTRL,
+	"meta": MOD_META, "super": MOD_META,
+}
+
+// Interpreta una tecla escrita como "ctrl+a", "alt+shift+arriba", "f5", "espacio", "G" o "rueda-abajo" (los nombres de
+// Tecla.String y sus equivalentes en inglés). "shift+" con una letra equivale a la letra en mayúscula.
+func ParsearTecla(s string) (Tecla, error) {
+	partes := strings.Split(s, "+")
+	// "+" y "ctrl++" nombran la tecla +.
+	if strings.HasSuffix(s, "++") || s == "+" {
+		partes = append(partes[:len(partes)-2], "+")
+	}
+	var t Tecla
+	for _, p := range partes[:len(partes)-1] {
+		mod, existe := nombresModificadores[strings.ToLower(p)]
+		if !existe {
+			return Tecla{}, fmt.Errorf("tecla %q: modificador desconocido %q", s, p)
+		}
+		t.Modificadores |= mod
+	}
+
+	nombre := partes[len(partes)-1]
+	minuscula := strings.ToLower(nombre)
+	var numero int
+	switch {
+	case nombre == "":
+		return Tecla{}, fmt.Errorf("tecla %q: falta la tecla", s)
+	case utf8.RuneCountInString(nombre) == 1:
+		t.Codigo, t.Runa = CODIGO_RUNA, []rune(nombre)[0]
+		if t.Modificadores&MOD_SHIFT != 0 && unicode.IsLetter(t.Runa) {
+			t.Runa, t.Modificadores = unicode.ToUpper(t.Runa), t.Modificadores&^MOD_SHIFT
+		}
+		if t.Modificadores&MOD_CTRL != 0 {
+			t.Runa = unicode.ToLower(t.Runa)
+			// ^H, ^I, ^J, ^M y ^[ llegan de la terminal como Retroceso, Tab, Enter y Esc.
+			if t.Runa >= 'a' && t.Runa <= 'z' || t.Runa == '[' {
+				if c := teclaControl(byte(t.Runa & 0x1F)); c.Codigo != CODIGO_RUNA {
+					t.Codigo, t.Runa, t.Modificadores = c.Codigo, 0, t.Modificadores&^MOD_CTRL
+				}
+			}
+		}
+	case minuscula == "espacio" || minuscula == "space":
+		t.Codigo, t.Runa = CODIGO_RUNA, ' '
+	case nombresTeclas[minuscula] != 0:
+		t.Codigo = nombresTeclas[minuscula]
+	case esRaton(minuscula):
+		t.Codigo = CODIGO_RATON
+		t.Raton, _ = ratonPorNombre(minuscula)
+	case len(minuscula) > 1 && minuscula[0] == 'f':
+		if _, err := fmt.Sscanf(minuscula[1:], "%d", &numero); err != nil || numero < 1 || numero > 12 {
+			return Tecla{}, fmt.Errorf("tecla %q: tecla de función inválida", s)
+		}
+		t.Codigo = CODIGO_F1 + Codigo(numero-1)
+	default:
+		return Tecla{}, fmt.Errorf("tecla %q: nombre desconocido %q", s, nombre)
+	}
+	return t, nil
+}
+
+var (
+	personalizaciones = map[string]Mapa{}
+	perfil            = PERFIL_EMACS
+	mutexMapas        sync.RWMutex
+)
+
+// Reemplaza, en el mapa del componente nombre (por ejemplo "menu" o "visor"), las teclas de m.
+func Personalizar(nombre string, m Mapa) {
+	mutexMapas.Lock()
+	defer mutexMapas.Unlock()
+	personalizaciones[nombre] = personalizaciones[nombre].Combinar(m)
+}
+
+// Devuelve el mapa predeterminado del componente nombre con las personalizaciones que se le hayan hecho.
+// Es una copia: modificarlo no cambia predeterminado ni las personalizaciones.
+func MapaDe(nombre string, predeterminado Mapa) Mapa {
+	mutexMapas.RLock()
+	defer mutexMapas.RUnlock()
+	return predeterminado.Combinar(personalizaciones[nombre])
+}
+
+const (
+	PERFIL_EMACS = "emacs"
+	PERFIL_VI    = "vi"
+)
+
+// Devuelve el perfil del editor de líneas: PERFIL_EMACS (predeterminado) o PERFIL_VI.
+func Perfil() string {
+	mutexMapas.RLock()
+	defer mutexMapas.RUnlock()
+	return perfil
+}
+
+// Elige el perfil del editor de líneas.
+func AsignarPerfil(p string) error {
+	if p != PERFIL_EMACS && p != PERFIL_VI {
+		return fmt.Errorf("perfil de teclado desconocido %q (se esperaba %q o %q)", p, PERFIL_EMACS, PERFIL_VI)
+	}
+	mutexMapas.Lock()
+	defer mutexMapas.Unlock()
+	perfil = p
+	return nil
+}
+
+// Configuracion de teclado tal como se guarda en un archivo JSON:
+//
+//	{
+//	  "perfil": "vi",
+//	  "teclas": {
+//	    "menu":  {"alt+j": "abajo", "alt+k": "arriba"},
+//	    "visor": {"q": "ninguna"}
+//	  }
+//	}
+type Configuracion struct {
+	Perfil string                       `json:"perfil,omitempty"`
+	Teclas map[string]map[string]string `json:"teclas,omitempty"`
+}
+
+// Aplica la configuración: elige el perfil y personaliza los mapas. Las teclas inválidas se informan juntas y no se aplican.
+func (c Configuracion) Aplicar() error {
+	var errores []error
+	if c.Perfil != "" {
+		errores = append(errores, AsignarPerfil(c.Perfil))
+	}
+	for nombre, teclas := range c.Teclas {
+		m := Mapa{}
+		for tecla, accion := range teclas {
+			if err := m.Asignar(tecla, accion); err != nil {
+				errores = append(errores, fmt.Errorf("%s: %w", nombre, err))
+			}
+		}
+		Personalizar(nombre, m)
+	}
+	return errors.Join(errores...)
+}
+
+// Lee una Configuracion en JSON de r y la aplica.
+func LeerConfiguracion(r io.Reader) error {
+	var c Configuracion
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return fmt.Errorf("configuración de teclado: %w", err)
+	}
+	return c.Aplicar()
+}
+
+// Lee y aplica la configuración del archivo ruta. No es un error que el archivo no exista.
+func CargarConfiguracion(ruta string) error {
+	f, err := os.Open(ruta)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := LeerConfiguracion(f); err != nil {
+		return fmt.Errorf("%s: %w", ruta, err)
+	}
+	return nil
+}
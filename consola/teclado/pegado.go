@@ -0,0 +1,38 @@
+package teclado
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+)
+
+// Secuencias para activar y desactivar el modo de pegado delimitado (2004): la terminal encierra el texto pegado entre
+// INICIO_PEGADO y FIN_PEGADO, y el decodificador lo devuelve como una sola Tecla con CODIGO_PEGADO.
+const (
+	ACTIVAR_PEGADO    = "\x1b[?2004h"
+	DESACTIVAR_PEGADO = "\x1b[?2004l"
+	INICIO_PEGADO     = "\x1b[200~"
+	FIN_PEGADO        = "\x1b[201~"
+)
+
+// Decodifica el texto pegado que empieza en buf[inicio], hasta FIN_PEGADO. Si final y no llegó FIN_PEGADO, toma todo
+// lo que haya. Los saltos de línea \r\n y \r se normalizan a \n, las tabulaciones pasan a ser espacios y se
+// descartan los demás caracteres de control.
+func decodificarPegado(buf []byte, inicio int, final bool) (Tecla, int, bool) {
+	fin := bytes.Index(buf[inicio:], []byte(FIN_PEGADO))
+	n := inicio + fin + len(FIN_PEGADO)
+	switch {
+	case fin < 0 && !final:
+		return Tecla{}, 0, false
+	case fin < 0:
+		fin, n = len(buf)-inicio, len(buf)
+	}
+	texto := strings.NewReplacer("\r\n", "\n", "\r", "\n", "\t", " ").Replace(string(buf[inicio : inicio+fin]))
+	texto = strings.Map(func(r rune) rune {
+		if r != '\n' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, texto)
+	return Tecla{Codigo: CODIGO_PEGADO, Texto: texto}, n, true
+}
@@ -0,0 +1,121 @@
+package teclado
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Secuencias para activar y desactivar el informe de eventos del ratón en formato SGR (1006): pulsaciones,
+// arrastres con un botón pulsado y rueda.
+const (
+	ACTIVAR_RATON    = "\x1b[?1000h\x1b[?1002h\x1b[?1006h"
+	DESACTIVAR_RATON = "\x1b[?1006l\x1b[?1002l\x1b[?1000l"
+)
+
+type BotonRaton int
+
+const (
+	BOTON_IZQUIERDO BotonRaton = iota
+	BOTON_MEDIO
+	BOTON_DERECHO
+	BOTON_NINGUNO
+	RUEDA_ARRIBA
+	RUEDA_ABAJO
+	RUEDA_IZQUIERDA
+	RUEDA_DERECHA
+)
+
+type AccionRaton int
+
+const (
+	RATON_PULSAR AccionRaton = iota
+	RATON_SOLTAR
+	RATON_ARRASTRAR // Movimiento con un botón pulsado
+	RATON_MOVER     // Movimiento sin botones (sólo si la terminal lo informa)
+	RATON_RUEDA
+)
+
+// Evento del ratón. Columna y Fila cuentan desde 1, desde la esquina superior izquierda de la terminal.
+type Raton struct {
+	Boton   BotonRaton
+	Accion  AccionRaton
+	Columna int
+	Fila    int
+}
+
+var nombresBotones = []string{"", "-medio", "-derecho"}
+var nombresRuedas = []string{"rueda-arriba", "rueda-abajo", "rueda-izquierda", "rueda-derecha"}
+
+// Devuelve el nombre del evento, que es el que se usa en los mapas de teclas: "clic", "clic-derecho", "soltar",
+// "arrastrar", "rueda-arriba", "rueda-abajo"...
+func (r Raton) String() string {
+	switch {
+	case r.Accion == RATON_RUEDA && r.Boton >= RUEDA_ARRIBA:
+		return nombresRuedas[r.Boton-RUEDA_ARRIBA]
+	case r.Accion == RATON_MOVER || r.Boton > BOTON_DERECHO:
+		return "mover"
+	case r.Accion == RATON_SOLTAR:
+		return "soltar" + nombresBotones[r.Boton]
+	case r.Accion == RATON_ARRASTRAR:
+		return "arrastrar" + nombresBotones[r.Boton]
+	}
+	return "clic" + nombresBotones[r.Boton]
+}
+
+// Devuelve el evento con el nombre dado (ver Raton.String), sin coordenadas.
+func ratonPorNombre(nombre string) (Raton, bool) {
+	for _, accion := range []AccionRaton{RATON_PULSAR, RATON_SOLTAR, RATON_ARRASTRAR, RATON_MOVER, RATON_RUEDA} {
+		for boton := BOTON_IZQUIERDO; boton <= RUEDA_DERECHA; boton++ {
+			if r := (Raton{Boton: boton, Accion: accion}); r.String() == nombre {
+				return r, true
+			}
+		}
+	}
+	return Raton{}, false
+}
+
+func esRaton(nombre string) bool {
+	_, existe := ratonPorNombre(nombre)
+	return existe
+}
+
+// Decodifica los parámetros de ESC [ < b ; x ; y M (pulsación o movimiento) o m (soltar).
+func decodificarRaton(parametros string, fin byte) Tecla {
+	partes := strings.Split(parametros, ";")
+	if len(partes) != 3 {
+		return Tecla{Codigo: CODIGO_DESCONOCIDO}
+	}
+	var numeros [3]int
+	for i, p := range partes {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return Tecla{Codigo: CODIGO_DESCONOCIDO}
+		}
+		numeros[i] = v
+	}
+	b := numeros[0]
+	t := Tecla{Codigo: CODIGO_RATON, Raton: Raton{Columna: numeros[1], Fila: numeros[2]}}
+	// Bits 2 a 4: shift, meta (alt) y ctrl.
+	if b&4 != 0 {
+		t.Modificadores |= MOD_SHIFT
+	}
+	if b&8 != 0 {
+		t.Modificadores |= MOD_ALT
+	}
+	if b&16 != 0 {
+		t.Modificadores |= MOD_CTRL
+	}
+	switch {
+	case b&64 != 0:
+		t.Raton.Accion, t.Raton.Boton = RATON_RUEDA, RUEDA_ARRIBA+BotonRaton(b&3)
+	case b&32 != 0 && b&3 == 3:
+		t.Raton.Accion, t.Raton.Boton = RATON_MOVER, BOTON_NINGUNO
+	case b&32 != 0:
+		t.Raton.Accion, t.Raton.Boton = RATON_ARRASTRAR, BotonRaton(b&3)
+	case fin == 'm':
+		t.Raton.Accion, t.Raton.Boton = RATON_SOLTAR, BotonRaton(b&3)
+	default:
+		t.Raton.Accion, t.Raton.Boton = RATON_PULSAR, BotonRaton(b&3)
+	}
+	return t
+}
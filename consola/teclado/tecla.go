@@ -0,0 +1,347 @@
+package teclado
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Código de una tecla con nombre. CODIGO_RUNA indica una tecla que produce un carácter (ver Tecla.Runa).
+type Codigo int
+
+const (
+	CODIGO_DESCONOCIDO Codigo = iota
+	CODIGO_RUNA
+	CODIGO_ENTER
+	CODIGO_TAB
+	CODIGO_RETROCESO
+	CODIGO_ESCAPE
+	CODIGO_ARRIBA
+	CODIGO_ABAJO
+	CODIGO_DERECHA
+	CODIGO_IZQUIERDA
+	CODIGO_INICIO
+	CODIGO_FIN
+	CODIGO_REPAG
+	CODIGO_AVPAG
+	CODIGO_INSERTAR
+	CODIGO_SUPRIMIR
+	CODIGO_F1
+	CODIGO_F2
+	CODIGO_F3
+	CODIGO_F4
+	CODIGO_F5
+	CODIGO_F6
+	CODIGO_F7
+	CODIGO_F8
+	CODIGO_F9
+	CODIGO_F10
+	CODIGO_F11
+	CODIGO_F12
+	CODIGO_RATON  // Evento del ratón, ver Tecla.Raton y Consola.HabilitarRaton
+	CODIGO_PEGADO // Texto pegado con el modo de pegado delimitado, ver Tecla.Texto y ACTIVAR_PEGADO
+)
+
+var nombresCodigos = map[Codigo]string{
+	CODIGO_DESCONOCIDO: "desconocida",
+	CODIGO_ENTER:       "enter",
+	CODIGO_TAB:         "tab",
+	CODIGO_RETROCESO:   "retroceso",
+	CODIGO_ESCAPE:      "esc",
+	CODIGO_ARRIBA:      "arriba",
+	CODIGO_ABAJO:       "abajo",
+	CODIGO_DERECHA:     "derecha",
+	CODIGO_IZQUIERDA:   "izquierda",
+	CODIGO_INICIO:      "inicio",
+	CODIGO_FIN:         "fin",
+	CODIGO_REPAG:       "repag",
+	CODIGO_AVPAG:       "avpag",
+	CODIGO_INSERTAR:    "insertar",
+	CODIGO_SUPRIMIR:    "suprimir",
+	CODIGO_RATON:       "raton",
+	CODIGO_PEGADO:      "pegado",
+}
+
+func (c Codigo) String() string {
+	if c >= CODIGO_F1 && c <= CODIGO_F12 {
+		return "f" + strconv.Itoa(int(c-CODIGO_F1)+1)
+	}
+	if n, existe := nombresCodigos[c]; existe {
+		return n
+	}
+	return "runa"
+}
+
+// Teclas modificadoras, combinables con |.
+type Modificador uint8
+
+const (
+	MOD_SHIFT Modificador = 1 << iota
+	MOD_ALT
+	MOD_CTRL
+	MOD_META
+)
+
+// Tecla es un evento de teclado decodificado: una tecla con nombre o un carácter, con sus modificadores.
+//
+// Las combinaciones con Ctrl de letras y símbolos se representan como la runa en minúscula con MOD_CTRL
+// (^A es Tecla{Codigo: CODIGO_RUNA, Runa: 'a', Modificadores: MOD_CTRL}), salvo Tab, Enter, Retroceso y Esc.
+type Tecla struct {
+	Codigo        Codigo
+	Runa          rune // Sólo para CODIGO_RUNA
+	Modificadores Modificador
+	Raton         Raton  // Sólo para CODIGO_RATON
+	Texto         string // Sólo para CODIGO_PEGADO: el texto, con los saltos de línea como \n y sin otros caracteres de control
+	Secuencia     []byte // Bytes leídos de la terminal
+}
+
+// Devuelve true si la tecla produce un carácter sin Ctrl ni Alt (puede tener Shift).
+func (t Tecla) Imprimible() bool {
+	return t.Codigo == CODIGO_RUNA && t.Modificadores&(MOD_CTRL|MOD_ALT|MOD_META) == 0
+}
+
+// Devuelve true si la tecla es la runa r sin modificadores (salvo Shift).
+func (t Tecla) EsRuna(r rune) bool {
+	return t.Imprimible() && t.Runa == r
+}
+
+// Devuelve true si la tecla es la runa r con Ctrl.
+func (t Tecla) EsCtrl(r rune) bool {
+	return t.Codigo == CODIGO_RUNA && t.Runa == r && t.Modificadores == MOD_CTRL
+}
+
+// Devuelve la tecla como la escriben los usuarios, por ejemplo "ctrl+a", "shift+arriba", "alt+enter" o "f5".
+// Los eventos del ratón se nombran según Raton.String, por ejemplo "rueda-abajo" o "ctrl+clic".
+func (t Tecla) String() string {
+	var sb strings.Builder
+	for _, m := range []struct {
+		mod    Modificador
+		nombre string
+	}{{MOD_CTRL, "ctrl+"}, {MOD_ALT, "alt+"}, {MOD_META, "meta+"}, {MOD_SHIFT, "shift+"}} {
+		if t.Modificadores&m.mod != 0 {
+			sb.WriteString(m.nombre)
+		}
+	}
+	switch {
+	case t.Codigo == CODIGO_RATON:
+		sb.WriteString(t.Raton.String())
+	case t.Codigo != CODIGO_RUNA:
+		sb.WriteString(t.Codigo.String())
+	case t.Runa == ' ':
+		sb.WriteString("espacio")
+	default:
+		sb.WriteRune(t.Runa)
+	}
+	return sb.String()
+}
+
+// Tecla de control ^x para los bytes 0x00 a 0x1F que no tienen nombre propio.
+func teclaControl(b byte) Tecla {
+	switch b {
+	case CR, LF:
+		return Tecla{Codigo: CODIGO_ENTER}
+	case HT:
+		return Tecla{Codigo: CODIGO_TAB}
+	case BS, DEL:
+		return Tecla{Codigo: CODIGO_RETROCESO}
+	case ESC:
+		return Tecla{Codigo: CODIGO_ESCAPE}
+	case NUL:
+		return Tecla{Codigo: CODIGO_RUNA, Runa: ' ', Modificadores: MOD_CTRL}
+	}
+	// ^A-^Z son 0x01-0x1A; ^\ ^] ^^ ^_ son 0x1C-0x1F.
+	if b >= FS {
+		return Tecla{Codigo: CODIGO_RUNA, Runa: rune(b) + 0x40, Modificadores: MOD_CTRL}
+	}
+	return Tecla{Codigo: CODIGO_RUNA, Runa: rune(b) + 0x60, Modificadores: MOD_CTRL}
+}
+
+// Finales de las secuencias CSI y SS3 con letra: ESC [ A, ESC [ 1;5 A, ESC O P...
+var finalesLetra = map[byte]Codigo{
+	'A': CODIGO_ARRIBA,
+	'B': CODIGO_ABAJO,
+	'C': CODIGO_DERECHA,
+	'D': CODIGO_IZQUIERDA,
+	'H': CODIGO_INICIO,
+	'F': CODIGO_FIN,
+	'P': CODIGO_F1,
+	'Q': CODIGO_F2,
+	'R': CODIGO_F3,
+	'S': CODIGO_F4,
+}
+
+// Primer parámetro de las secuencias ESC [ n ~ (y de rxvt, que termina en $, ^ o @).
+var finalesTilde = map[int]Codigo{
+	1:  CODIGO_INICIO,
+	2:  CODIGO_INSERTAR,
+	3:  CODIGO_SUPRIMIR,
+	4:  CODIGO_FIN,
+	5:  CODIGO_REPAG,
+	6:  CODIGO_AVPAG,
+	7:  CODIGO_INICIO,
+	8:  CODIGO_FIN,
+	11: CODIGO_F1,
+	12: CODIGO_F2,
+	13: CODIGO_F3,
+	14: CODIGO_F4,
+	15: CODIGO_F5,
+	17: CODIGO_F6,
+	18: CODIGO_F7,
+	19: CODIGO_F8,
+	20: CODIGO_F9,
+	21: CODIGO_F10,
+	23: CODIGO_F11,
+	24: CODIGO_F12,
+}
+
+// Interpreta el parámetro de modificadores de xterm (1 + shift 1, alt 2, ctrl 4, meta 8).
+func modificadoresXterm(p int) Modificador {
+	if p <= 1 {
+		return 0
+	}
+	return Modificador(p - 1)
+}
+
+// Decodifica la primera tecla de buf, leído de una terminal en modo crudo, y devuelve cuántos bytes ocupa.
+//
+// Si buf empieza con una secuencia incompleta (por ejemplo, un ESC que puede ser la tecla Esc o el comienzo de ESC [ A)
+// y final es false, devuelve completa false para que se lean más bytes. Con final se interpreta lo que haya:
+// un ESC solo es CODIGO_ESCAPE y una secuencia cortada, CODIGO_DESCONOCIDO.
+func Decodificar(buf []byte, final bool) (t Tecla, n int, completa bool) {
+	if len(buf) == 0 {
+		return Tecla{}, 0, false
+	}
+	defer func() {
+		if completa {
+			t.Secuencia = append([]byte(nil), buf[:n]...)
+		}
+	}()
+
+	b := buf[0]
+	switch {
+	case b == ESC:
+		if len(buf) == 1 {
+			return Tecla{Codigo: CODIGO_ESCAPE}, 1, final
+		}
+		switch buf[1] {
+		case CSI:
+			return decodificarCSI(buf, final)
+		case O:
+			if len(buf) == 2 {
+				if final {
+					return Tecla{Codigo: CODIGO_RUNA, Runa: 'O', Modificadores: MOD_ALT}, 2, true
+				}
+				return Tecla{}, 0, false
+			}
+			if c, existe := finalesLetra[buf[2]]; existe {
+				return Tecla{Codigo: c}, 3, true
+			}
+			return Tecla{Codigo: CODIGO_DESCONOCIDO}, 3, true
+		}
+		// ESC seguido de otra tecla: la tecla con Alt.
+		t, n, completa := Decodificar(buf[1:], final)
+		if !completa {
+			return Tecla{}, 0, false
+		}
+		t.Modificadores |= MOD_ALT
+		return t, n + 1, true
+	case b < ESPACIO || b == DEL:
+		return teclaControl(b), 1, true
+	}
+
+	if !utf8.FullRune(buf) {
+		if final {
+			return Tecla{Codigo: CODIGO_DESCONOCIDO}, len(buf), true
+		}
+		return Tecla{}, 0, false
+	}
+	r, largo := utf8.DecodeRune(buf)
+	if r == utf8.RuneError {
+		return Tecla{Codigo: CODIGO_DESCONOCIDO}, largo, true
+	}
+	return Tecla{Codigo: CODIGO_RUNA, Runa: r}, largo, true
+}
+
+// Decodifica una secuencia ESC [ parámetros intermedios final.
+func decodificarCSI(buf []byte, final bool) (Tecla, int, bool) {
+	i := 2
+	for i < len(buf) && buf[i] >= 0x30 && buf[i] <= 0x3F {
+		i++
+	}
+	parametros := string(buf[2:i])
+	for i < len(buf) && buf[i] >= 0x20 && buf[i] <= 0x2F {
+		i++
+	}
+	if i >= len(buf) {
+		if final {
+			if i == 2 {
+				return Tecla{Codigo: CODIGO_RUNA, Runa: '[', Modificadores: MOD_ALT}, 2, true
+			}
+			return Tecla{Codigo: CODIGO_DESCONOCIDO}, len(buf), true
+		}
+		return Tecla{}, 0, false
+	}
+	fin := buf[i]
+	n := i + 1
+	if fin < 0x40 || fin > 0x7E {
+		return Tecla{Codigo: CODIGO_DESCONOCIDO}, i, true
+	}
+
+	// Texto pegado: ESC [ 200 ~ texto ESC [ 201 ~.
+	if fin == '~' && parametros == "200" {
+		return decodificarPegado(buf, n, final)
+	}
+
+	// Ratón en formato SGR: ESC [ < b ; x ; y M o m.
+	if strings.HasPrefix(parametros, "<") && (fin == M || fin == m) {
+		return decodificarRaton(parametros[1:], fin), n, true
+	}
+
+	// Consola de Linux: ESC [ [ A a ESC [ [ E son F1 a F5.
+	if fin == CSI && parametros == "" {
+		if n >= len(buf) {
+			if final {
+				return Tecla{Codigo: CODIGO_DESCONOCIDO}, n, true
+			}
+			return Tecla{}, 0, false
+		}
+		if buf[n] >= 'A' && buf[n] <= 'E' {
+			return Tecla{Codigo: CODIGO_F1 + Codigo(buf[n]-'A')}, n + 1, true
+		}
+		return Tecla{Codigo: CODIGO_DESCONOCIDO}, n, true
+	}
+
+	numeros := []int{}
+	for _, p := range strings.Split(parametros, ";") {
+		v, _ := strconv.Atoi(p)
+		numeros = append(numeros, v)
+	}
+	mod := Modificador(0)
+	if len(numeros) > 1 {
+		mod = modificadoresXterm(numeros[1])
+	}
+
+	switch {
+	case fin == Z:
+		return Tecla{Codigo: CODIGO_TAB, Modificadores: MOD_SHIFT | mod}, n, true
+	case fin == '~' || fin == '$' || fin == '^' || fin == '@':
+		c, existe := finalesTilde[numeros[0]]
+		if !existe {
+			return Tecla{Codigo: CODIGO_DESCONOCIDO}, n, true
+		}
+		// rxvt indica Shift con $, Ctrl con ^ y ambos con @.
+		switch fin {
+		case '$':
+			mod |= MOD_SHIFT
+		case '^':
+			mod |= MOD_CTRL
+		case '@':
+			mod |= MOD_SHIFT | MOD_CTRL
+		}
+		return Tecla{Codigo: c, Modificadores: mod}, n, true
+	}
+	if c, existe := finalesLetra[fin]; existe {
+		return Tecla{Codigo: c, Modificadores: mod}, n, true
+	}
+	return Tecla{Codigo: CODIGO_DESCONOCIDO}, n, true
+}
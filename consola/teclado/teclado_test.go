@@ -0,0 +1,144 @@
+package teclado_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodificar comprueba secuencias CSI y SS3, modificadores de xterm, teclas de control, runas y eventos del ratón
+func TestDecodificar(t *testing.T) {
+	casos := map[string]string{
+		"\x1b[A":        "arriba",
+		"\x1bOB":        "abajo",
+		"\x1b[1;5C":     "ctrl+derecha",
+		"\x1b[1;2H":     "shift+inicio",
+		"\x1b[3~":       "suprimir",
+		"\x1b[6;3~":     "alt+avpag",
+		"\x1b[24~":      "f12",
+		"\x1bOP":        "f1",
+		"\x1b[[B":       "f2",
+		"\x1b[Z":        "shift+tab",
+		"\x01":          "ctrl+a",
+		"\x1f":          "ctrl+_",
+		"\x7f":          "retroceso",
+		"\r":            "enter",
+		"\x1bx":         "alt+x",
+		"ñ":             "ñ",
+		"\x1b\x1b[A":    "alt+arriba",
+		"\x1b[1;13D":    "ctrl+meta+izquierda",
+		"\x1b[200;9~z":  "desconocida",
+		"\x1b[<0;3;5M":  "clic",
+		"\x1b[<2;3;5m":  "soltar-derecho",
+		"\x1b[<65;1;1M": "rueda-abajo",
+		"\x1b[<16;2;2M": "ctrl+clic",
+		"\x1b[<32;4;4M": "arrastrar",
+	}
+	for entrada, esperada := range casos {
+		tecla, _, completa := teclado.Decodificar([]byte(entrada), false)
+		assert.True(t, completa, entrada)
+		assert.Equal(t, esperada, tecla.String(), "%q", entrada)
+	}
+
+	_, _, completa := teclado.Decodificar([]byte("\x1b"), false)
+	assert.False(t, completa)
+	_, _, completa = teclado.Decodificar([]byte("\x1b[1;5"), false)
+	assert.False(t, completa)
+	_, _, completa = teclado.Decodificar([]byte("\xc3"), false)
+	assert.False(t, completa)
+
+	// El texto pegado llega como una sola tecla, con los saltos de línea normalizados.
+	pegado := "\x1b[200~uno\r\ndos\tx\x1b[201~a"
+	tecla, n, completa := teclado.Decodificar([]byte(pegado), false)
+	assert.True(t, completa)
+	assert.Equal(t, "pegado", tecla.String())
+	assert.Equal(t, "uno\ndos x", tecla.Texto)
+	assert.Equal(t, len(pegado)-1, n)
+	_, _, completa = teclado.Decodificar([]byte("\x1b[200~uno"), false)
+	assert.False(t, completa)
+}
+
+// TestDecodificador lee varias teclas de una sola lectura y resuelve un ESC solo al agotarse la espera
+func TestDecodificador(t *testing.T) {
+	lecturas := [][]byte{[]byte("a\x1b[Bñ"), []byte("\x1b")}
+	leer := func(b []byte) (int, error) {
+		if len(lecturas) == 0 {
+			return 0, io.EOF
+		}
+		n := copy(b, lecturas[0])
+		lecturas = lecturas[1:]
+		return n, nil
+	}
+	d := teclado.NuevoDecodificador(leer, func(time.Duration) bool { return false })
+
+	var teclas []string
+	for {
+		tecla, err := d.Leer()
+		if err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+		teclas = append(teclas, tecla.String())
+	}
+	assert.Equal(t, []string{"a", "abajo", "ñ", "esc"}, teclas)
+}
+
+// TestLeerBytes verifica que las lecturas crudas devuelvan primero los bytes que Leer dejó pendientes
+func TestLeerBytes(t *testing.T) {
+	leida := false
+	d := teclado.NuevoDecodificador(func(b []byte) (int, error) {
+		if leida {
+			return copy(b, "c"), nil
+		}
+		leida = true
+		return copy(b, "a\x1b[Bb"), nil
+	}, nil)
+
+	tecla, err := d.Leer()
+	require.NoError(t, err)
+	assert.Equal(t, "a", tecla.String())
+
+	b := make([]byte, 8)
+	n, err := d.LeerBytes(b)
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[Bb", string(b[:n]))
+	n, err = d.LeerBytes(b)
+	require.NoError(t, err)
+	assert.Equal(t, "c", string(b[:n]))
+}
+
+// TestMapa comprueba la normalización de teclas, la combinación de mapas y las personalizaciones desde la configuración
+func TestMapa(t *testing.T) {
+	for escrita, esperada := range map[string]string{
+		"Ctrl+A":       "ctrl+a",
+		"shift+g":      "G",
+		"alt+up":       "alt+arriba",
+		"ctrl+m":       "enter",
+		"ctrl+espacio": "ctrl+espacio",
+		"F5":           "f5",
+		"ctrl++":       "ctrl++",
+	} {
+		tecla, err := teclado.ParsearTecla(escrita)
+		require.NoError(t, err, escrita)
+		assert.Equal(t, esperada, tecla.String(), escrita)
+	}
+	_, err := teclado.ParsearTecla("hiper+x")
+	assert.Error(t, err)
+
+	base := teclado.NuevoMapa("enter", "aceptar", "q", "salir")
+	ctrlA, _, _ := teclado.Decodificar([]byte{0x01}, false)
+	assert.Equal(t, "aceptar", base.Accion(teclado.Tecla{Codigo: teclado.CODIGO_ENTER}))
+
+	err = teclado.LeerConfiguracion(strings.NewReader(`{"teclas": {"prueba": {"ctrl+a": "aceptar", "q": "ninguna", "zz": "x"}}}`))
+	assert.Error(t, err)
+	mapa := teclado.MapaDe("prueba", base)
+	assert.Equal(t, "aceptar", mapa.Accion(ctrlA))
+	assert.Equal(t, "", mapa.Accion(teclado.Tecla{Codigo: teclado.CODIGO_RUNA, Runa: 'q'}))
+	assert.Equal(t, []string{"enter", "ctrl+a"}, mapa.Teclas("aceptar"))
+	assert.Equal(t, "salir", base.Accion(teclado.Tecla{Codigo: teclado.CODIGO_RUNA, Runa: 'q'}))
+}
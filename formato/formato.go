@@ -0,0 +1,427 @@
+/*
+Registro de codificadores para presentar el resultado de un Comando en distintos formatos (texto, json, yaml, csv, tsv, markdown, html, tabla).
+*/
+
+package formato
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"gopkg.in/yaml.v3"
+)
+
+// Codificador escribe v en w en un formato determinado.
+type Codificador func(w io.Writer, v any) error
+
+const (
+	TEXTO    = "texto"
+	JSON     = "json"
+	YAML     = "yaml"
+	CSV      = "csv"
+	TSV      = "tsv"
+	MARKDOWN = "markdown"
+	HTML     = "html"
+	TABLA    = "tabla"
+)
+
+type registrado struct {
+	codificador Codificador
+	esMaquina   bool
+}
+
+var (
+	mu            sync.RWMutex
+	codificadores = map[string]registrado{
+		TEXTO:    {Texto, false},
+		TABLA:    {Tabla, false},
+		JSON:     {Json, true},
+		YAML:     {Yaml, true},
+		CSV:      {Csv, true},
+		TSV:      {Tsv, true},
+		MARKDOWN: {Markdown, true},
+		HTML:     {Html, true},
+	}
+	porTipo = map[string]map[reflect.Type]Codificador{}
+)
+
+// Registra (o reemplaza) el codificador para el formato nombre.
+// esMaquina indica que la salida está pensada para otros programas, por lo que la Aplicacion suprime la salida decorativa.
+func Registrar(nombre string, c Codificador, esMaquina bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	codificadores[nombre] = registrado{c, esMaquina}
+}
+
+// Registra un codificador específico para los valores de tipo T en el formato nombre. Tiene prioridad sobre el codificador general del formato.
+//
+// # Ejemplo:
+//
+//	formato.RegistrarTipo(formato.TEXTO, func(w io.Writer, u Usuario) error {
+//		_, err := fmt.Fprintf(w, "%s <%s>\n", u.Nombre, u.Correo)
+//		return err
+//	})
+func RegistrarTipo[T any](nombre string, f func(w io.Writer, v T) error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if porTipo[nombre] == nil {
+		porTipo[nombre] = make(map[reflect.Type]Codificador)
+	}
+	porTipo[nombre][reflect.TypeFor[T]()] = func(w io.Writer, v any) error {
+		return f(w, v.(T))
+	}
+}
+
+// Devuelve true si existe un codificador registrado con ese nombre.
+func Existe(nombre string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, existe := codificadores[nombre]
+	return existe
+}
+
+// Devuelve true si el formato está pensado para ser consumido por otros programas.
+func EsMaquina(nombre string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return codificadores[nombre].esMaquina
+}
+
+// Devuelve los nombres de los formatos registrados, ordenados.
+func Formatos() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	nombres := make([]string, 0, len(codificadores))
+	for n := range codificadores {
+		nombres = append(nombres, n)
+	}
+	slices.Sort(nombres)
+	return nombres
+}
+
+// Escribe v en w con el codificador del formato nombre, o con el registrado para el tipo de v si existe.
+func Codificar(w io.Writer, nombre string, v any) error {
+	mu.RLock()
+	r, existe := codificadores[nombre]
+	var especifico Codificador
+	if v != nil {
+		especifico = porTipo[nombre][reflect.TypeOf(v)]
+	}
+	mu.RUnlock()
+
+	switch {
+	case especifico != nil:
+		return especifico(w, v)
+	case !existe:
+		return fmt.Errorf("formato de salida desconocido: %q (disponibles: %s)", nombre, strings.Join(Formatos(), ", "))
+	}
+	return r.codificador(w, v)
+}
+
+// Codificador por defecto: cadenas y fmt.Stringer tal cual, colecciones como tabla y el resto con %v.
+func Texto(w io.Writer, v any) error {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return escribirLinea(w, t)
+	case fmt.Stringer:
+		return escribirLinea(w, t.String())
+	case error:
+		return escribirLinea(w, t.Error())
+	}
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Struct, reflect.Map:
+		return Tabla(w, v)
+	}
+	return escribirLinea(w, fmt.Sprintf("%v", v))
+}
+
+func escribirLinea(w io.Writer, s string) error {
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+func Json(w io.Writer, v any) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
+
+func Yaml(w io.Writer, v any) error {
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+	if err := e.Encode(v); err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+// Devuelve v como cadena.DatosTabla (ver Tabular).
+func datos(v any) cadena.DatosTabla {
+	encabezados, filas := Tabular(v)
+	return cadena.DatosTabla{Encabezados: encabezados, Filas: filas}
+}
+
+func Csv(w io.Writer, v any) error {
+	return datos(v).EscribirCSV(w)
+}
+
+func Tsv(w io.Writer, v any) error {
+	return datos(v).EscribirTSV(w)
+}
+
+func Markdown(w io.Writer, v any) error {
+	return datos(v).EscribirMarkdown(w)
+}
+
+func Html(w io.Writer, v any) error {
+	return datos(v).EscribirHTML(w)
+}
+
+// Presenta v como un cadena.Cuadro ajustado al ancho de la terminal de w (ver Tabular).
+func Tabla(w io.Writer, v any) error {
+	_, err := io.WriteString(w, datos(v).Cuadro().ConAncho(cadena.AnchoTerminalDe(archivoDe(w))).Renderizar())
+	return err
+}
+
+// Devuelve el archivo al que escribe w, si es un *os.File o una Consola, o os.Stdout.
+func archivoDe(w io.Writer) *os.File {
+	switch s := w.(type) {
+	case *os.File:
+		return s
+	case interface{ FSalida() *os.File }:
+		if f := s.FSalida(); f != nil {
+			return f
+		}
+	}
+	return os.Stdout
+}
+
+// Devuelve un Codificador que presenta v con la plantilla texto (ver cadena.FuncionesPlantilla), al estilo de docker --format.
+// Si v es un slice la plantilla se aplica a cada elemento por separado. Cada resultado termina en un salto de línea.
+// En la plantilla, tabla acepta además cualquier valor admitido por Tabular.
+//
+// # Ejemplo:
+//
+//	formato.Plantilla("{{.Nombre | negrita}}\t{{.Estado | colorear \"verde\"}}", cadena.TemaPredeterminado)(os.Stdout, servicios)
+func Plantilla(texto string, t cadena.Tema) Codificador {
+	return func(w io.Writer, v any) error {
+		funciones := cadena.FuncionesPlantillaEn(t, archivoDe(w))
+		tablaCadena := funciones["tabla"].(func(...any) (string, error))
+		funciones["tabla"] = func(args ...any) (string, error) {
+			if len(args) == 1 {
+				args = []any{datos(args[0])}
+			}
+			return tablaCadena(args...)
+		}
+		p, err := template.New("salida").Funcs(funciones).Option("missingkey=zero").Parse(texto)
+		if err != nil {
+			return fmt.Errorf("plantilla de salida: %w", err)
+		}
+		elementos := []any{v}
+		if valor := reflect.ValueOf(v); valor.Kind() == reflect.Slice && valor.Type().Elem().Kind() != reflect.Uint8 {
+			elementos = make([]any, valor.Len())
+			for i := range elementos {
+				elementos[i] = valor.Index(i).Interface()
+			}
+		}
+		for _, e := range elementos {
+			var sb strings.Builder
+			if err := p.Execute(&sb, e); err != nil {
+				return fmt.Errorf("plantilla de salida: %w", err)
+			}
+			if err := escribirLinea(w, sb.String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Convierte v en encabezados y filas:
+//   - un cadena.Tabulable (por ejemplo cadena.DatosTabla) se presenta a sí mismo;
+//   - un slice de structs usa los campos exportados como columnas (respetando la etiqueta json);
+//   - un slice de mapas usa la unión ordenada de sus claves;
+//   - un slice de interfaces (por ejemplo []any) se trata según el tipo de sus elementos, si todos son del mismo tipo
+//     o todos son mapas;
+//   - un struct es una única fila y un mapa se presenta como pares clave/valor;
+//   - cualquier otro valor es una única celda.
+func Tabular(v any) ([]string, [][]string) {
+	if t, ok := v.(cadena.Tabulable); ok {
+		return t.Tabular()
+	}
+	valor := reflect.Indirect(reflect.ValueOf(v))
+	if !valor.IsValid() {
+		return []string{}, [][]string{}
+	}
+	switch valor.Kind() {
+	case reflect.Slice, reflect.Array:
+		if valor.Kind() == reflect.Slice && valor.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		elementos := make([]reflect.Value, valor.Len())
+		for i := range elementos {
+			elementos[i] = reflect.Indirect(valor.Index(i))
+		}
+		return tabularElementos(valor.Type().Elem(), elementos)
+	case reflect.Struct:
+		return tabularElementos(valor.Type(), []reflect.Value{valor})
+	case reflect.Map:
+		filas := make([][]string, 0, valor.Len())
+		for _, k := range clavesOrdenadas(valor) {
+			filas = append(filas, []string{celda(k), celda(valor.MapIndex(k))})
+		}
+		return []string{"clave", "valor"}, filas
+	}
+	return []string{"valor"}, [][]string{{celda(valor)}}
+}
+
+func tabularElementos(tipo reflect.Type, elementos []reflect.Value) ([]string, [][]string) {
+	if tipo.Kind() == reflect.Interface {
+		tipo = tipoElementos(tipo, elementos)
+	}
+	for tipo.Kind() == reflect.Pointer {
+		tipo = tipo.Elem()
+	}
+	filas := make([][]string, 0, len(elementos))
+	switch tipo.Kind() {
+	case reflect.Struct:
+		campos := reflect.VisibleFields(tipo)
+		var encabezados []string
+		var indices [][]int
+		for _, c := range campos {
+			nombre, omitir := nombreCampo(c)
+			if omitir {
+				continue
+			}
+			encabezados = append(encabezados, nombre)
+			indices = append(indices, c.Index)
+		}
+		for _, e := range elementos {
+			fila := make([]string, len(indices))
+			if e.IsValid() {
+				for j, idx := range indices {
+					if f, err := e.FieldByIndexErr(idx); err == nil {
+						fila[j] = celda(f)
+					}
+				}
+			}
+			filas = append(filas, fila)
+		}
+		return encabezados, filas
+
+	case reflect.Map:
+		conjunto := map[string]bool{}
+		for _, e := range elementos {
+			if !e.IsValid() {
+				continue
+			}
+			for _, k := range e.MapKeys() {
+				conjunto[celda(k)] = true
+			}
+		}
+		encabezados := make([]string, 0, len(conjunto))
+		for k := range conjunto {
+			encabezados = append(encabezados, k)
+		}
+		sort.Strings(encabezados)
+		for _, e := range elementos {
+			fila := make([]string, len(encabezados))
+			if e.IsValid() {
+				for _, k := range e.MapKeys() {
+					fila[slices.Index(encabezados, celda(k))] = celda(e.MapIndex(k))
+				}
+			}
+			filas = append(filas, fila)
+		}
+		return encabezados, filas
+
+	case reflect.Slice, reflect.Array:
+		var ancho int
+		for _, e := range elementos {
+			fila := make([]string, 0)
+			if e.IsValid() {
+				for i := 0; i < e.Len(); i++ {
+					fila = append(fila, celda(e.Index(i)))
+				}
+			}
+			ancho = max(ancho, len(fila))
+			filas = append(filas, fila)
+		}
+		encabezados := make([]string, ancho)
+		for i := range encabezados {
+			encabezados[i] = fmt.Sprint(i + 1)
+		}
+		return encabezados, filas
+	}
+	for _, e := range elementos {
+		filas = append(filas, []string{celda(e)})
+	}
+	return []string{"valor"}, filas
+}
+
+// Reemplaza cada elemento por su valor dinámico y devuelve el tipo común a todos: el de los elementos si es el mismo, un
+// tipo de mapa si todos son mapas, o tipo si no tienen nada en común.
+func tipoElementos(tipo reflect.Type, elementos []reflect.Value) reflect.Type {
+	var comun reflect.Type
+	for i, e := range elementos {
+		if e.Kind() == reflect.Interface {
+			e = reflect.Indirect(e.Elem())
+			elementos[i] = e
+		}
+		switch {
+		case !e.IsValid():
+		case comun == nil:
+			comun = e.Type()
+		case e.Type() != comun && (e.Kind() != reflect.Map || comun.Kind() != reflect.Map):
+			return tipo
+		}
+	}
+	if comun == nil {
+		return tipo
+	}
+	return comun
+}
+
+func nombreCampo(c reflect.StructField) (string, bool) {
+	if !c.IsExported() || c.Anonymous {
+		return "", true
+	}
+	etiqueta := c.Tag.Get("json")
+	if etiqueta == "-" {
+		return "", true
+	}
+	if nombre, _, _ := strings.Cut(etiqueta, ","); nombre != "" {
+		return nombre, false
+	}
+	return c.Name, false
+}
+
+func clavesOrdenadas(m reflect.Value) []reflect.Value {
+	claves := m.MapKeys()
+	sort.Slice(claves, func(i, j int) bool { return celda(claves[i]) < celda(claves[j]) })
+	return claves
+}
+
+func celda(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
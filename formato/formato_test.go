@@ -0,0 +1,37 @@
+package formato_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/formato"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTabularInterfaces verifica que los elementos de un []any se presenten según su tipo y no en una única columna
+func TestTabularInterfaces(t *testing.T) {
+	type item struct {
+		Nombre   string `json:"nombre"`
+		Cantidad int    `json:"cantidad"`
+	}
+	encabezados, filas := formato.Tabular([]any{item{"uno", 1}, &item{"dos", 2}})
+	assert.Equal(t, []string{"nombre", "cantidad"}, encabezados)
+	assert.Equal(t, [][]string{{"uno", "1"}, {"dos", "2"}}, filas)
+
+	encabezados, filas = formato.Tabular([]any{map[string]any{"a": 1}, map[string]string{"b": "x"}})
+	assert.Equal(t, []string{"a", "b"}, encabezados)
+	assert.Equal(t, [][]string{{"1", ""}, {"", "x"}}, filas)
+
+	encabezados, filas = formato.Tabular([]any{1, "dos"})
+	assert.Equal(t, []string{"valor"}, encabezados)
+	assert.Equal(t, [][]string{{"1"}, {"dos"}}, filas)
+}
+
+// TestTabla verifica que el formato tabla se presente como un Cuadro, sin rellenar las celdas vacías
+func TestTabla(t *testing.T) {
+	var sb strings.Builder
+	require.NoError(t, formato.Tabla(&sb, []map[string]string{{"a": "1"}, {"b": "2"}}))
+	assert.Contains(t, sb.String(), "│ a │ b │")
+	assert.NotContains(t, sb.String(), "N/A")
+}
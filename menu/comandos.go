@@ -0,0 +1,64 @@
+package menu
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/hernanatn/aplicacion.go/comando"
+	"github.com/hernanatn/aplicacion.go/consola"
+)
+
+// Arma un Menu con los subcomandos visibles de c (por ejemplo, una Aplicacion) para usarlo sin escribir comandos.
+//
+// Los comandos con subcomandos abren submenús y el resto son opciones cuya Accion ejecuta, a través de c, la ruta
+// de nombres recorrida seguida de los argumentos y las opciones que recibe. Si un comando con subcomandos tiene una
+// Accion propia (ver comando.TieneAccion), su submenú empieza con una opción para ejecutarlo. El Valor de cada opción
+// que ejecuta un comando es su ruta de nombres ([]string). Se omiten los comandos ocultos y los de ayuda.
+//
+// # Ejemplo:
+//
+//	m := menu.DesdeComando(app, app)
+//	o, err := m.Correr()
+//	if err == nil {
+//		res, _, err = o.Accion(app, nil, nil)
+//	}
+func DesdeComando(con consola.Consola, c comando.Comando) *Menu {
+	m := NuevoMenu(con, '>')
+	m.Titulo = c.DevolverNombre()
+	m.Opciones = opcionesComando(c, c, nil)
+	return m
+}
+
+func opcionesComando(raiz comando.Comando, c comando.Comando, ruta []string) []*Opcion {
+	opciones := []*Opcion{}
+	for _, sc := range comando.Subcomandos(c) {
+		nombre := sc.DevolverNombre()
+		if sc.EsOculto() || nombre == "ayuda" {
+			continue
+		}
+		rutaSc := append(slices.Clone(ruta), nombre)
+		o := &Opcion{Nombre: nombre}
+		if sub := opcionesComando(raiz, sc, rutaSc); len(sub) > 0 {
+			if comando.TieneAccion(sc) {
+				ejecutar := &Opcion{Nombre: "Ejecutar " + nombre, Valor: rutaSc, Accion: accionRuta(raiz, rutaSc)}
+				sub = append([]*Opcion{ejecutar, Separador()}, sub...)
+			}
+			o.Subopciones = sub
+		} else {
+			o.Valor, o.Accion = rutaSc, accionRuta(raiz, rutaSc)
+		}
+		opciones = append(opciones, o)
+	}
+	return opciones
+}
+
+// Devuelve una Accion que ejecuta, a través de raiz, la ruta de nombres seguida de los argumentos y las opciones.
+func accionRuta(raiz comando.Comando, ruta []string) Accion {
+	return func(con comando.Consola, opciones comando.Opciones, parametros comando.Parametros, argumentos ...any) (any, comando.CodigoError, error) {
+		args := slices.Clone(ruta)
+		for _, a := range argumentos {
+			args = append(args, fmt.Sprint(a))
+		}
+		return raiz.Ejecutar(con, append(args, opciones...)...)
+	}
+}
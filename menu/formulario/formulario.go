@@ -0,0 +1,629 @@
+package formulario
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"github.com/hernanatn/aplicacion.go/pregunta"
+	"golang.org/x/term"
+)
+
+// Mapa de teclas de Formulario (nombre "formulario" en la configuración de teclado). Acciones: siguiente, anterior
+// (campo), enviar, cancelar, interrumpir, alternar (casillas y selecciones) y, en los campos de texto,
+// caracter-anterior, caracter-siguiente, inicio-linea, fin-linea, borrar-anterior, borrar-siguiente y nueva-linea
+// (sólo en los campos Multilinea).
+var MAPA_FORMULARIO = teclado.NuevoMapa(
+	"tab", "siguiente", "abajo", "siguiente",
+	"shift+tab", "anterior", "arriba", "anterior",
+	"enter", "enviar",
+	"esc", "cancelar",
+	"ctrl+c", "interrumpir",
+	"espacio", "alternar",
+	"izquierda", "caracter-anterior", "ctrl+b", "caracter-anterior",
+	"derecha", "caracter-siguiente", "ctrl+f", "caracter-siguiente",
+	"inicio", "inicio-linea", "ctrl+a", "inicio-linea",
+	"fin", "fin-linea", "ctrl+e", "fin-linea",
+	"retroceso", "borrar-anterior",
+	"suprimir", "borrar-siguiente", "ctrl+d", "borrar-siguiente",
+	"alt+enter", "nueva-linea",
+)
+
+type TipoCampo int
+
+const (
+	CAMPO_TEXTO TipoCampo = iota
+	CAMPO_CONTRASEÑA
+	CAMPO_SELECCION
+	CAMPO_CASILLA
+)
+
+// Un campo del Formulario. Su valor es un string, salvo en CAMPO_CASILLA, donde es un bool.
+type Campo struct {
+	Nombre         string // Clave del valor en el resultado
+	Etiqueta       string // Por defecto, Nombre
+	Tipo           TipoCampo
+	Opciones       []string // Para CAMPO_SELECCION
+	Predeterminado any
+	Requerido      bool
+	Multilinea     bool // Para CAMPO_TEXTO: el valor puede tener saltos de línea, que se conservan al pegar texto
+	Validar        func(valor any) error
+	Ayuda          string
+
+	texto     []rune
+	posicion  int // Posición del cursor en texto
+	eleccion  int
+	marcada   bool
+	err       error
+	convertir func(string) error // Comprueba que el texto se pueda asignar al campo de la estructura (ver NuevoFormularioDesde)
+}
+
+func (c *Campo) etiqueta() string {
+	if c.Etiqueta != "" {
+		return c.Etiqueta
+	}
+	return c.Nombre
+}
+
+func (c *Campo) valor() any {
+	switch c.Tipo {
+	case CAMPO_CASILLA:
+		return c.marcada
+	case CAMPO_SELECCION:
+		if c.eleccion >= 0 && c.eleccion < len(c.Opciones) {
+			return c.Opciones[c.eleccion]
+		}
+		return ""
+	}
+	return string(c.texto)
+}
+
+func (c *Campo) asignar(v any) {
+	switch c.Tipo {
+	case CAMPO_CASILLA:
+		c.marcada, _ = v.(bool)
+	case CAMPO_SELECCION:
+		c.eleccion = max(slices.Index(c.Opciones, fmt.Sprint(v)), 0)
+	default:
+		if v != nil {
+			c.texto = []rune(fmt.Sprint(v))
+		}
+		c.posicion = len(c.texto)
+	}
+}
+
+// Valida el campo y guarda el error para mostrarlo junto a él.
+func (c *Campo) validar() error {
+	c.err = nil
+	v := c.valor()
+	switch {
+	case c.Requerido && c.Tipo != CAMPO_CASILLA && v == "":
+		c.err = errors.New("obligatorio")
+	case c.convertir != nil && v != "":
+		c.err = c.convertir(v.(string))
+	}
+	if c.err == nil && c.Validar != nil {
+		c.err = c.Validar(v)
+	}
+	return c.err
+}
+
+// Formulario presenta varios campos a la vez y permite corregirlos antes de enviarlos.
+//
+// Teclas (ver MAPA_FORMULARIO): Tab/↓ pasan al campo siguiente y Shift+Tab/↑ al anterior; ←→ mueven el cursor o cambian la opción elegida;
+// Espacio marca las casillas; Alt+Enter agrega un salto de línea en los campos Multilinea; Enter envía el formulario
+// (si hay errores, se muestran junto a cada campo) y Esc lo cancela.
+// Si la entrada o la salida no son una terminal se pregunta campo por campo con el paquete pregunta; en las selecciones,
+// una respuesta vacía conserva la opción predeterminada.
+//
+// # Ejemplo:
+//
+//	f := formulario.NuevoFormulario(con, "Nuevo proyecto").
+//		Agregar(formulario.Campo{Nombre: "nombre", Requerido: true}).
+//		Agregar(formulario.Campo{Nombre: "licencia", Tipo: formulario.CAMPO_SELECCION, Opciones: []string{"MIT", "GPL"}}).
+//		Agregar(formulario.Campo{Nombre: "git", Etiqueta: "Inicializar git", Tipo: formulario.CAMPO_CASILLA, Predeterminado: true})
+//	valores, err := f.Correr()
+type Formulario struct {
+	Titulo  string
+	Campos  []*Campo
+	Consola consola.Consola
+	Validar func(valores map[string]any) error // Validación del formulario completo al enviarlo
+	Teclas  teclado.Mapa                       // Si es nil, MAPA_FORMULARIO con las personalizaciones de "formulario" (ver teclado.Personalizar)
+
+	enfocado   int
+	err        error
+	lineas     int
+	debeCerrar bool
+}
+
+func NuevoFormulario(con consola.Consola, titulo string) *Formulario {
+	return &Formulario{
+		Titulo:  titulo,
+		Consola: con,
+	}
+}
+
+func (f *Formulario) Agregar(c Campo) *Formulario {
+	campo := &c
+	campo.asignar(c.Predeterminado)
+	f.Campos = append(f.Campos, campo)
+	return f
+}
+
+func (f Formulario) teclas() teclado.Mapa {
+	if f.Teclas != nil {
+		return f.Teclas
+	}
+	return teclado.MapaDe("formulario", MAPA_FORMULARIO)
+}
+
+func (f Formulario) DebeCerrar() bool {
+	return f.debeCerrar
+}
+
+// Devuelve los valores de los campos por Nombre.
+func (f Formulario) valores() map[string]any {
+	valores := make(map[string]any, len(f.Campos))
+	for _, c := range f.Campos {
+		valores[c.Nombre] = c.valor()
+	}
+	return valores
+}
+
+// Valida todos los campos y el formulario. Enfoca el primer campo con error.
+func (f *Formulario) validar() bool {
+	primero := -1
+	for i, c := range f.Campos {
+		if c.validar() != nil && primero < 0 {
+			primero = i
+		}
+	}
+	if primero >= 0 {
+		f.enfocado = primero
+		return false
+	}
+	f.err = nil
+	if f.Validar != nil {
+		f.err = f.Validar(f.valores())
+	}
+	return f.err == nil
+}
+
+func (f Formulario) borrar() {
+	f.Consola.BorrarLinea()
+	for i := 0; i < f.lineas; i++ {
+		f.Consola.EscribirBytes(teclado.CURSOR_PRINCIPIO_ANTERIOR)
+		f.Consola.BorrarLinea()
+	}
+}
+
+func (f *Formulario) renderizar(final bool) {
+	tema := consola.Extender(f.Consola).Tema()
+	ancho := 0
+	for _, c := range f.Campos {
+		ancho = max(ancho, cadena.Ancho(c.etiqueta()))
+	}
+
+	var sb strings.Builder
+	if f.Titulo != "" {
+		sb.WriteString(tema.Titulo(f.Titulo).S())
+	}
+	for i, c := range f.Campos {
+		enfocado := i == f.enfocado && !final
+		etiqueta := cadena.Rellenar(c.etiqueta(), ancho, cadena.DERECHA)
+		if c.Requerido {
+			etiqueta += "*"
+		} else {
+			etiqueta += " "
+		}
+		if enfocado {
+			sb.WriteString(tema.Señalador("›").S() + tema.Aplicar(cadena.ROL_ENFASIS, etiqueta).S())
+		} else {
+			sb.WriteString("  " + etiqueta)
+		}
+		sb.WriteString(" " + c.presentar(enfocado, tema))
+		if c.err != nil {
+			sb.WriteString("  " + tema.Aplicar(cadena.ROL_ERROR, "✕ "+c.err.Error()).S())
+		} else if enfocado && c.Ayuda != "" {
+			sb.WriteString("  " + tema.Aplicar(cadena.ROL_SUGERENCIA, c.Ayuda).S())
+		}
+		sb.WriteString("\n")
+	}
+	if f.err != nil {
+		sb.WriteString(tema.Aplicar(cadena.ROL_ERROR, "✕ "+f.err.Error()).S() + "\n")
+	}
+	if !final {
+		sb.WriteString(tema.Aplicar(cadena.ROL_ATENUADO, "Tab/↑↓ campo · ←→ cursor u opción · espacio marcar · enter enviar · esc cancelar").S())
+	}
+	texto := strings.TrimSuffix(sb.String(), "\n")
+	f.lineas = strings.Count(texto, "\n") + 1
+	f.Consola.ImprimirLinea(cadena.Cadena(texto))
+}
+
+// Presenta el valor del campo. El campo enfocado muestra el cursor.
+func (c *Campo) presentar(enfocado bool, tema cadena.Tema) string {
+	switch c.Tipo {
+	case CAMPO_CASILLA:
+		marca := "[ ]"
+		if c.marcada {
+			marca = "[✓]"
+		}
+		if enfocado {
+			return tema.Aplicar(cadena.ROL_SELECCION, marca).S()
+		}
+		return marca
+	case CAMPO_SELECCION:
+		opcion := "(sin opciones)"
+		if c.eleccion < len(c.Opciones) {
+			opcion = c.Opciones[c.eleccion]
+		}
+		if enfocado {
+			return "‹ " + tema.Aplicar(cadena.ROL_SELECCION, opcion).S() + " ›"
+		}
+		return opcion
+	}
+	texto := []rune(strings.ReplaceAll(string(c.texto), "\n", "↵"))
+	if c.Tipo == CAMPO_CONTRASEÑA {
+		texto = []rune(strings.Repeat("•", len(c.texto)))
+	}
+	if !enfocado {
+		return string(texto)
+	}
+	cursor := " "
+	if c.posicion < len(texto) {
+		cursor = string(texto[c.posicion])
+	}
+	despues := ""
+	if c.posicion < len(texto) {
+		despues = string(texto[c.posicion+1:])
+	}
+	return string(texto[:c.posicion]) + tema.Aplicar(cadena.ROL_SELECCION, cursor).S() + despues
+}
+
+// Procesa una tecla sobre el campo enfocado. En los campos de texto las teclas imprimibles siempre se escriben y el
+// texto pegado se inserta completo; fuera de los campos Multilinea, sus saltos de línea pasan a ser espacios.
+func (c *Campo) editar(tecla teclado.Tecla, accion string) {
+	switch c.Tipo {
+	case CAMPO_CASILLA:
+		if accion == "alternar" {
+			c.marcada = !c.marcada
+		}
+		return
+	case CAMPO_SELECCION:
+		switch accion {
+		case "caracter-anterior":
+			c.eleccion = (c.eleccion + len(c.Opciones) - 1) % max(len(c.Opciones), 1)
+		case "caracter-siguiente", "alternar":
+			c.eleccion = (c.eleccion + 1) % max(len(c.Opciones), 1)
+		}
+		return
+	}
+	if tecla.Imprimible() {
+		c.texto = slices.Insert(c.texto, c.posicion, tecla.Runa)
+		c.posicion++
+		return
+	}
+	if tecla.Codigo == teclado.CODIGO_PEGADO && accion == "" {
+		pegado := tecla.Texto
+		if !c.Multilinea {
+			pegado = strings.ReplaceAll(pegado, "\n", " ")
+		}
+		c.texto = slices.Insert(c.texto, c.posicion, []rune(pegado)...)
+		c.posicion += len([]rune(pegado))
+		return
+	}
+	switch accion {
+	case "nueva-linea":
+		if c.Multilinea {
+			c.texto = slices.Insert(c.texto, c.posicion, '\n')
+			c.posicion++
+		}
+	case "caracter-anterior":
+		c.posicion = max(c.posicion-1, 0)
+	case "caracter-siguiente":
+		c.posicion = min(c.posicion+1, len(c.texto))
+	case "inicio-linea":
+		c.posicion = 0
+	case "fin-linea":
+		c.posicion = len(c.texto)
+	case "borrar-anterior":
+		if c.posicion > 0 {
+			c.texto = slices.Delete(c.texto, c.posicion-1, c.posicion)
+			c.posicion--
+		}
+	case "borrar-siguiente":
+		if c.posicion < len(c.texto) {
+			c.texto = slices.Delete(c.texto, c.posicion, c.posicion+1)
+		}
+	}
+}
+
+// Muestra el formulario hasta que se envíe sin errores o se cancele. Devuelve los valores por Nombre de campo:
+// string para los campos de texto, contraseña y selección, y bool para las casillas.
+func (f *Formulario) Correr() (map[string]any, error) {
+	if len(f.Campos) == 0 {
+		return map[string]any{}, nil
+	}
+	if !esInteractiva(f.Consola) {
+		return f.correrSinTerminal()
+	}
+	return f.correrInteractivo()
+}
+
+// Igual que en el paquete pregunta: hacen falta una entrada y una salida de terminal para leer teclas y redibujar.
+func esInteractiva(con consola.Consola) bool {
+	return con.EsTerminal() && term.IsTerminal(int(con.FEntrada().Fd())) && term.IsTerminal(int(con.FSalida().Fd()))
+}
+
+// Presenta el formulario completo y lo edita tecla por tecla.
+func (f *Formulario) correrInteractivo() (map[string]any, error) {
+	f.debeCerrar = false
+	f.enfocado = 0
+	f.Consola.EscribirBytes([]byte(consola.OCULTAR_CURSOR + teclado.ACTIVAR_PEGADO))
+	defer f.Consola.ImprimirBytes([]byte(teclado.DESACTIVAR_PEGADO + consola.MOSTRAR_CURSOR))
+	f.renderizar(false)
+	var errores []error
+	enviado := false
+	teclas := f.teclas()
+	con := consola.Extender(f.Consola)
+	for !f.DebeCerrar() {
+		tecla, err := con.LeerEvento()
+		if err != nil {
+			errores = append(errores, err)
+			f.debeCerrar = true
+		}
+		campo := f.Campos[f.enfocado]
+
+		accion := teclas.Accion(tecla)
+		switch {
+		case err != nil:
+		case accion == "interrumpir":
+			errores = append(errores, errors.New("programa cerrado por el usuario ^C"))
+			f.debeCerrar = true
+		case accion == "cancelar":
+			errores = append(errores, pregunta.ERROR_CANCELADO)
+			f.debeCerrar = true
+		case accion == "enviar":
+			if f.validar() {
+				enviado, f.debeCerrar = true, true
+			}
+		case accion == "siguiente":
+			campo.validar()
+			f.enfocado = (f.enfocado + 1) % len(f.Campos)
+		case accion == "anterior":
+			campo.validar()
+			f.enfocado = (f.enfocado + len(f.Campos) - 1) % len(f.Campos)
+		default:
+			campo.editar(tecla, accion)
+			if campo.err != nil {
+				campo.validar()
+			}
+		}
+		f.borrar()
+		f.renderizar(f.debeCerrar)
+	}
+	if !enviado {
+		return nil, errors.Join(errores...)
+	}
+	return f.valores(), errors.Join(errores...)
+}
+
+// Pregunta campo por campo leyendo líneas de texto plano.
+func (f *Formulario) correrSinTerminal() (map[string]any, error) {
+	if f.Titulo != "" {
+		f.Consola.ImprimirCadena(consola.Extender(f.Consola).Tema().Titulo(f.Titulo))
+	}
+	for {
+		for _, c := range f.Campos {
+			var err error
+			mensaje := cadena.Cadena(c.etiqueta())
+			switch c.Tipo {
+			case CAMPO_CASILLA:
+				c.marcada, err = pregunta.Confirmar(f.Consola, mensaje, c.marcada)
+			case CAMPO_SELECCION:
+				c.eleccion, err = c.elegirSinTerminal(f.Consola)
+			default:
+				var texto string
+				predeterminado := string(c.texto)
+				texto, err = pregunta.Texto(f.Consola, mensaje, func(s string) error {
+					if s == "" {
+						s = predeterminado
+					}
+					c.texto = []rune(s)
+					return c.validar()
+				})
+				if texto == "" {
+					texto = predeterminado
+				}
+				c.texto = []rune(texto)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		if f.validar() {
+			return f.valores(), nil
+		}
+		// Los errores del formulario completo no se pueden asociar a un campo: se informa y se vuelve a preguntar todo.
+		f.Consola.ImprimirLinea(consola.Extender(f.Consola).Tema().Aplicar(cadena.ROL_ERROR, "✕ "+errors.Join(f.err, f.erroresCampos()).Error()))
+	}
+}
+
+// Lista las opciones numeradas y lee el número o el nombre de la elegida. Una respuesta vacía conserva la opción
+// actual (el Predeterminado, si se asignó).
+func (c *Campo) elegirSinTerminal(con consola.Consola) (int, error) {
+	if len(c.Opciones) == 0 {
+		return -1, errors.New("no hay opciones para elegir")
+	}
+	con.ImprimirLinea(cadena.Cadena(c.etiqueta()))
+	for i, o := range c.Opciones {
+		con.ImprimirLinea(cadena.Cadena(fmt.Sprintf("  %d) %s", i+1, o)))
+	}
+	mensaje := "Opción" + consola.Extender(con).Tema().Aplicar(cadena.ROL_ATENUADO, fmt.Sprintf(" [%s]", c.valor()))
+	eleccion := c.eleccion
+	_, err := pregunta.Texto(con, mensaje, func(s string) error {
+		if s == "" {
+			return nil
+		}
+		if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(c.Opciones) {
+			eleccion = n - 1
+			return nil
+		}
+		if i := slices.Index(c.Opciones, s); i >= 0 {
+			eleccion = i
+			return nil
+		}
+		return fmt.Errorf("%q no es una opción (1-%d)", s, len(c.Opciones))
+	})
+	return eleccion, err
+}
+
+func (f Formulario) erroresCampos() error {
+	var errs []error
+	for _, c := range f.Campos {
+		if c.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.etiqueta(), c.err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Crea un Formulario con un campo por cada campo exportado de la estructura apuntada por v, con sus valores como predeterminados.
+//
+// Los campos se configuran con las etiquetas:
+//   - formulario:"nombre,requerido,contraseña,multilinea" (nombre del valor; "-" omite el campo);
+//   - etiqueta:"Texto a mostrar", ayuda:"Texto de ayuda" y opciones:"a,b,c" (convierte el campo en una selección).
+//
+// Los bool son casillas; los string, números enteros y decimales son campos de texto que se validan según su tipo.
+func NuevoFormularioDesde(con consola.Consola, titulo string, v any) (*Formulario, error) {
+	valor := reflect.ValueOf(v)
+	if valor.Kind() != reflect.Pointer || valor.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("se esperaba un puntero a una estructura, se recibió %T", v)
+	}
+	f := NuevoFormulario(con, titulo)
+	estructura := valor.Elem()
+	for _, sf := range reflect.VisibleFields(estructura.Type()) {
+		if !sf.IsExported() || sf.Anonymous {
+			continue
+		}
+		nombre, banderas, _ := strings.Cut(sf.Tag.Get("formulario"), ",")
+		if nombre == "-" {
+			continue
+		}
+		if nombre == "" {
+			nombre = sf.Name
+		}
+		c := Campo{
+			Nombre:         nombre,
+			Etiqueta:       sf.Tag.Get("etiqueta"),
+			Ayuda:          sf.Tag.Get("ayuda"),
+			Predeterminado: estructura.FieldByIndex(sf.Index).Interface(),
+		}
+		for _, b := range strings.Split(banderas, ",") {
+			switch strings.TrimSpace(b) {
+			case "requerido":
+				c.Requerido = true
+			case "contraseña":
+				c.Tipo = CAMPO_CONTRASEÑA
+			case "multilinea":
+				c.Multilinea = true
+			}
+		}
+		switch sf.Type.Kind() {
+		case reflect.Bool:
+			c.Tipo = CAMPO_CASILLA
+		case reflect.String:
+			if opciones := sf.Tag.Get("opciones"); opciones != "" {
+				c.Tipo = CAMPO_SELECCION
+				c.Opciones = strings.Split(opciones, ",")
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			c.convertir = func(s string) error {
+				_, err := strconv.ParseInt(s, 10, sf.Type.Bits())
+				if err != nil {
+					return errors.New("debe ser un número entero")
+				}
+				return nil
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			c.convertir = func(s string) error {
+				_, err := strconv.ParseUint(s, 10, sf.Type.Bits())
+				if err != nil {
+					return errors.New("debe ser un número entero positivo")
+				}
+				return nil
+			}
+		case reflect.Float32, reflect.Float64:
+			c.convertir = func(s string) error {
+				_, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), sf.Type.Bits())
+				if err != nil {
+					return errors.New("debe ser un número")
+				}
+				return nil
+			}
+		default:
+			return nil, fmt.Errorf("el campo %s es de un tipo no admitido: %s", sf.Name, sf.Type)
+		}
+		f.Agregar(c)
+	}
+	return f, nil
+}
+
+// Corre el formulario y asigna los valores a los campos de la estructura apuntada por v (ver NuevoFormularioDesde).
+//
+// # Ejemplo:
+//
+//	type Proyecto struct {
+//		Nombre   string `formulario:"nombre,requerido" etiqueta:"Nombre del proyecto"`
+//		Licencia string `opciones:"MIT,Apache-2.0,GPL-3.0"`
+//		Puerto   int    `ayuda:"Puerto del servidor de desarrollo"`
+//		Git      bool   `etiqueta:"Inicializar git"`
+//	}
+//	p := Proyecto{Licencia: "MIT", Puerto: 8080, Git: true}
+//	err := formulario.Llenar(con, "Nuevo proyecto", &p)
+func Llenar(con consola.Consola, titulo string, v any) error {
+	f, err := NuevoFormularioDesde(con, titulo, v)
+	if err != nil {
+		return err
+	}
+	valores, err := f.Correr()
+	if err != nil {
+		return err
+	}
+	return asignar(v, f, valores)
+}
+
+func asignar(v any, f *Formulario, valores map[string]any) error {
+	estructura := reflect.ValueOf(v).Elem()
+	i := 0
+	for _, sf := range reflect.VisibleFields(estructura.Type()) {
+		if nombre, _, _ := strings.Cut(sf.Tag.Get("formulario"), ","); !sf.IsExported() || sf.Anonymous || nombre == "-" {
+			continue
+		}
+		campo := estructura.FieldByIndex(sf.Index)
+		valor := valores[f.Campos[i].Nombre]
+		i++
+		switch campo.Kind() {
+		case reflect.Bool:
+			campo.SetBool(valor.(bool))
+		case reflect.String:
+			campo.SetString(valor.(string))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, _ := strconv.ParseInt(valor.(string), 10, sf.Type.Bits())
+			campo.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, _ := strconv.ParseUint(valor.(string), 10, sf.Type.Bits())
+			campo.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			n, _ := strconv.ParseFloat(strings.Replace(valor.(string), ",", ".", 1), sf.Type.Bits())
+			campo.SetFloat(n)
+		}
+	}
+	return nil
+}
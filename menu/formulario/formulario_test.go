@@ -0,0 +1,62 @@
+package formulario_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/menu/formulario"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type proyecto struct {
+	Nombre   string `formulario:"nombre,requerido" etiqueta:"Nombre del proyecto"`
+	Licencia string `opciones:"MIT,Apache-2.0,GPL-3.0"`
+	Puerto   int
+	Git      bool
+	Interno  string `formulario:"-"`
+}
+
+// TestLlenarSinTerminal llena una estructura respondiendo campo por campo, incluyendo valores inválidos que se vuelven a pedir
+func TestLlenarSinTerminal(t *testing.T) {
+	le, ee, err := os.Pipe()
+	require.NoError(t, err)
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer ls.Close()
+
+	_, err = io.WriteString(ee, "\nmiapp\n2\nochenta\n\nn\n")
+	require.NoError(t, err)
+	ee.Close()
+	con := consola.NuevaConsola(le, es)
+
+	p := proyecto{Licencia: "MIT", Puerto: 8080, Git: true, Interno: "x"}
+	require.NoError(t, formulario.Llenar(con, "Nuevo proyecto", &p))
+	assert.Equal(t, proyecto{Nombre: "miapp", Licencia: "Apache-2.0", Puerto: 8080, Git: false, Interno: "x"}, p)
+
+	_, err = formulario.NuevoFormularioDesde(con, "", p)
+	assert.Error(t, err)
+}
+
+// TestSeleccionPredeterminadaSinTerminal conserva la opción predeterminada de una selección cuando la respuesta está vacía
+func TestSeleccionPredeterminadaSinTerminal(t *testing.T) {
+	le, ee, err := os.Pipe()
+	require.NoError(t, err)
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer ls.Close()
+	go io.Copy(io.Discard, ls)
+
+	_, err = io.WriteString(ee, "\n")
+	require.NoError(t, err)
+	ee.Close()
+	con := consola.NuevaConsola(le, es)
+
+	valores, err := formulario.NuevoFormulario(con, "").
+		Agregar(formulario.Campo{Nombre: "licencia", Tipo: formulario.CAMPO_SELECCION, Opciones: []string{"MIT", "GPL"}, Predeterminado: "GPL"}).
+		Correr()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"licencia": "GPL"}, valores)
+}
@@ -0,0 +1,81 @@
+package formulario
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reproducir(t *testing.T, entradas ...string) *consola.Reproductora {
+	t.Helper()
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	t.Cleanup(func() { ls.Close() })
+	go io.Copy(io.Discard, ls)
+
+	g := &consola.Grabacion{}
+	for i, e := range entradas {
+		g.Eventos = append(g.Eventos, consola.EventoGrabacion{Tiempo: float64(i) / 10, Tipo: consola.EVENTO_ENTRADA, Datos: e})
+	}
+	con, err := consola.NuevaConsolaReproducida(g, es, false)
+	require.NoError(t, err)
+	t.Cleanup(func() { con.Cerrar() })
+	return con
+}
+
+// TestCorrerInteractivo recorre los campos con Tab y Shift+Tab, corrige los errores que se muestran junto a cada campo
+// y no envía el formulario hasta que pase la validación completa
+func TestCorrerInteractivo(t *testing.T) {
+	con := reproducir(t,
+		"\r",     // Enviar vacío: "nombre" es obligatorio y queda enfocado con el error
+		"a", "b", // Escribir en "nombre" corrige el error
+		"\t",     // Pasar a "licencia"
+		"\x1b[C", // Elegir GPL
+		"\t",     // Pasar a "git"
+		" ",      // Marcar la casilla
+		"\x1b[Z", // Volver a "licencia"
+		"\x1b[Z", // Volver a "nombre"
+		"c",      // Completar "abc"
+		"\r",     // Lo rechaza la validación del formulario
+		"\x7f",   // Dejar "ab"
+		"\r",     // Enviar
+	)
+
+	f := NuevoFormulario(con, "Proyecto").
+		Agregar(Campo{Nombre: "nombre", Requerido: true}).
+		Agregar(Campo{Nombre: "licencia", Tipo: CAMPO_SELECCION, Opciones: []string{"MIT", "GPL"}}).
+		Agregar(Campo{Nombre: "git", Tipo: CAMPO_CASILLA})
+	enviados := 0
+	f.Validar = func(valores map[string]any) error {
+		enviados++
+		if valores["nombre"] == "abc" {
+			return errors.New("nombre reservado")
+		}
+		return nil
+	}
+
+	valores, err := f.correrInteractivo()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"nombre": "ab", "licencia": "GPL", "git": true}, valores)
+	assert.Equal(t, 2, enviados)
+	assert.NoError(t, f.Campos[0].err)
+	assert.NoError(t, f.err)
+}
+
+// TestErroresEnLinea valida el campo al abandonarlo y muestra el error junto a él
+func TestErroresEnLinea(t *testing.T) {
+	con := reproducir(t, "\t", "\x1b[Z", "\x1b")
+
+	f := NuevoFormulario(con, "").
+		Agregar(Campo{Nombre: "nombre", Requerido: true}).
+		Agregar(Campo{Nombre: "puerto"})
+	_, err := f.correrInteractivo()
+	assert.Error(t, err)
+	assert.EqualError(t, f.Campos[0].err, "obligatorio")
+	assert.Equal(t, 0, f.enfocado)
+}
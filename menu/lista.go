@@ -0,0 +1,458 @@
+package menu
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+)
+
+// Mapa de teclas de Lista, base de los de Menu y MultiMenu. Acciones: arriba, abajo, pagina-anterior, pagina-siguiente,
+// primera, ultima, borrar-filtro, cancelar (vacía el filtro), subir y bajar (como arriba y abajo, pero sin pasar de un
+// extremo al otro) y apuntar (elige la opción bajo el ratón). Las teclas imprimibles sin acción filtran las opciones.
+var MAPA_LISTA = teclado.NuevoMapa(
+	"arriba", "arriba",
+	"abajo", "abajo",
+	"repag", "pagina-anterior",
+	"avpag", "pagina-siguiente",
+	"inicio", "primera",
+	"fin", "ultima",
+	"retroceso", "borrar-filtro",
+	"esc", "cancelar",
+	"rueda-arriba", "subir",
+	"rueda-abajo", "bajar",
+	"clic", "apuntar",
+)
+
+const (
+	ALTO_POR_DEFECTO = 10  // Opciones visibles cuando no se puede determinar el alto de la terminal
+	PROPORCION_ALTO  = 0.5 // Fracción del alto de la terminal que ocupa la lista si no se fija Lista.Alto
+)
+
+// Resultado de comparar el filtro con el texto de una opción.
+type Coincidencia struct {
+	Indice     int   // Índice de la opción
+	Puntaje    int   // Mayor es mejor
+	Posiciones []int // Posiciones (en runas) del texto que coinciden con el filtro
+}
+
+func inicioPalabra(texto []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	anterior := texto[i-1]
+	return !unicode.IsLetter(anterior) && !unicode.IsDigit(anterior) || unicode.IsLower(anterior) && unicode.IsUpper(texto[i])
+}
+
+// Busca las runas de patron en texto, en orden y sin distinguir mayúsculas, aunque no estén seguidas.
+// Se prefieren las coincidencias consecutivas, las que empiezan palabras y las que dejan menos huecos.
+//
+// # Ejemplo:
+//
+//	c, ok := menu.Coincidir("cfg", "Configuración general") // ok: las posiciones son 0, 3 y 5
+func Coincidir(patron string, texto string) (Coincidencia, bool) {
+	buscado := []rune(patron)
+	runas := []rune(texto)
+	if len(buscado) == 0 {
+		return Coincidencia{}, true
+	}
+	igual := func(a rune, b rune) bool {
+		return unicode.ToLower(a) == unicode.ToLower(b)
+	}
+	// Primero se busca dónde termina la primera aparición del patrón y luego, hacia atrás,
+	// la aparición más corta que termina ahí.
+	j, fin := 0, -1
+	for i, r := range runas {
+		if igual(r, buscado[j]) {
+			if j++; j == len(buscado) {
+				fin = i
+				break
+			}
+		}
+	}
+	if fin < 0 {
+		return Coincidencia{}, false
+	}
+	posiciones := make([]int, len(buscado))
+	j = len(buscado) - 1
+	for i := fin; i >= 0 && j >= 0; i-- {
+		if igual(runas[i], buscado[j]) {
+			posiciones[j] = i
+			j--
+		}
+	}
+
+	puntaje := -min(posiciones[0], 8)
+	for k, p := range posiciones {
+		puntaje += 16
+		if inicioPalabra(runas, p) {
+			puntaje += 10
+		}
+		if k > 0 {
+			if hueco := p - posiciones[k-1] - 1; hueco == 0 {
+				puntaje += 8
+			} else {
+				puntaje -= min(hueco, 8)
+			}
+		}
+	}
+	return Coincidencia{Puntaje: puntaje, Posiciones: posiciones}, true
+}
+
+// Devuelve las coincidencias de patron con textos, de mejor a peor (a igual puntaje, los textos más cortos primero).
+// Con patron vacío devuelve todos los textos en su orden original.
+func Filtrar(patron string, textos []string) []Coincidencia {
+	coincidencias := make([]Coincidencia, 0, len(textos))
+	for i, t := range textos {
+		if c, ok := Coincidir(patron, t); ok {
+			c.Indice = i
+			coincidencias = append(coincidencias, c)
+		}
+	}
+	if patron != "" {
+		slices.SortStableFunc(coincidencias, func(a, b Coincidencia) int {
+			if a.Puntaje != b.Puntaje {
+				return b.Puntaje - a.Puntaje
+			}
+			return utf8.RuneCountInString(textos[a.Indice]) - utf8.RuneCountInString(textos[b.Indice])
+		})
+	}
+	return coincidencias
+}
+
+// Aplica resaltado a las runas de texto en posiciones y normal al resto, agrupando las runas seguidas.
+func Resaltar(texto string, posiciones []int, normal cadena.Formateador, resaltado cadena.Formateador) string {
+	var sb, tramo strings.Builder
+	enTramo := false
+	cerrar := func() {
+		if tramo.Len() == 0 {
+			return
+		}
+		if enTramo {
+			sb.WriteString(resaltado(tramo.String()))
+		} else {
+			sb.WriteString(normal(tramo.String()))
+		}
+		tramo.Reset()
+	}
+	for i, r := range []rune(texto) {
+		if coincide := slices.Contains(posiciones, i); coincide != enTramo {
+			cerrar()
+			enTramo = coincide
+		}
+		tramo.WriteRune(r)
+	}
+	cerrar()
+	return sb.String()
+}
+
+// Lista guarda el estado que comparten Menu y multimenu.MultiMenu: el filtro que escribe el usuario, las opciones que
+// lo cumplen, la enfocada y la ventana de opciones visibles. La navegación saltea los encabezados y separadores.
+//
+// Teclas: escribir filtra (Retroceso borra y Esc limpia el filtro); ↑↓ mueven la opción enfocada, RePág/AvPág de a una
+// ventana e Inicio/Fin van a los extremos. Mientras el filtro está vacío, los dígitos 1-9 eligen la opción con ese número
+// en la ventana (salvo que el nombre de alguna opción visible empiece con ese dígito, que entonces filtra) y el Atajo
+// de una opción la elige. Si se habilitó el ratón (ver UbicarLista), un clic elige la opción
+// y la rueda mueve la enfocada.
+type Lista struct {
+	Alto       int     // Máximo de opciones visibles. Con 0 se usa Proporcion del alto de la terminal
+	Proporcion float64 // Por defecto PROPORCION_ALTO
+
+	filtro         []rune
+	visibles       []Coincidencia
+	omitidas       []bool // Por posición en visibles: encabezados y separadores
+	enfocada       int    // Posición en visibles
+	desplazamiento int    // Primera posición de visibles en la ventana
+	fila           int    // Fila de la terminal de la primera línea armada por Lineas; 0 si no se conoce
+	lineas         []int  // Posición en visibles de cada línea armada por Lineas, o -1
+	digitos        []rune // Dígitos con los que empieza el nombre de alguna opción visible: escribirlos filtra
+}
+
+// Cantidad de opciones visibles a la vez en con.
+func (l Lista) AltoVentana(con consola.Consola) int {
+	if l.Alto > 0 {
+		return l.Alto
+	}
+	proporcion := l.Proporcion
+	if proporcion <= 0 {
+		proporcion = PROPORCION_ALTO
+	}
+	_, alto, err := consola.Extender(con).DevolverTamaño()
+	if err != nil || alto <= 0 {
+		return ALTO_POR_DEFECTO
+	}
+	return max(int(float64(alto)*proporcion), 3)
+}
+
+func (l Lista) Filtro() string {
+	return string(l.filtro)
+}
+
+// Devuelve el índice de la opción enfocada, o -1 si ninguna opción cumple el filtro.
+func (l Lista) Enfocada() int {
+	if l.enfocada < len(l.visibles) && !l.omitidas[l.enfocada] {
+		return l.visibles[l.enfocada].Indice
+	}
+	return -1
+}
+
+// Devuelve los índices de las opciones que cumplen el filtro, sin los encabezados ni los separadores, en el orden presentado.
+func (l Lista) Visibles() []int {
+	indices := []int{}
+	for pos, c := range l.visibles {
+		if !l.omitidas[pos] {
+			indices = append(indices, c.Indice)
+		}
+	}
+	return indices
+}
+
+// Enfoca la opción con índice i, si cumple el filtro.
+func (l *Lista) Enfocar(i int) {
+	if pos := slices.IndexFunc(l.visibles, func(c Coincidencia) bool { return c.Indice == i }); pos >= 0 && !l.omitidas[pos] {
+		l.enfocada = pos
+	}
+}
+
+// Limpia el filtro y enfoca la primera opción.
+func (l *Lista) Reiniciar(opciones []*Opcion) {
+	l.filtro, l.visibles = l.filtro[:0], nil
+	l.enfocada, l.desplazamiento = 0, 0
+	l.Filtrar(opciones)
+}
+
+// Recalcula las opciones que cumplen el filtro, conservando la enfocada si lo sigue cumpliendo.
+// Mientras se filtra no se muestran los encabezados ni los separadores.
+func (l *Lista) Filtrar(opciones []*Opcion) {
+	anterior := l.Enfocada()
+	l.visibles = Filtrar(string(l.filtro), Nombres(opciones))
+	if len(l.filtro) > 0 {
+		l.visibles = slices.DeleteFunc(l.visibles, func(c Coincidencia) bool { return opciones[c.Indice].Tipo != OPCION_NORMAL })
+	}
+	l.omitidas = make([]bool, len(l.visibles))
+	l.digitos = l.digitos[:0]
+	for pos, c := range l.visibles {
+		l.omitidas[pos] = opciones[c.Indice].Tipo != OPCION_NORMAL
+		if r, _ := utf8.DecodeRuneInString(opciones[c.Indice].Nombre); !l.omitidas[pos] && unicode.IsDigit(r) {
+			l.digitos = append(l.digitos, r)
+		}
+	}
+	l.enfocada = 0
+	if len(l.filtro) == 0 {
+		l.Enfocar(anterior)
+	}
+	l.enfocada = l.proxima(l.enfocada, 1)
+}
+
+// Devuelve la primera posición desde pos, en la dirección de paso, que no sea un encabezado ni un separador.
+// Si no hay ninguna, la busca en la dirección contraria, y si tampoco, devuelve pos.
+func (l Lista) proxima(pos int, paso int) int {
+	for _, p := range []int{paso, -paso} {
+		for i := pos; i >= 0 && i < len(l.visibles); i += p {
+			if !l.omitidas[i] {
+				return i
+			}
+		}
+	}
+	return pos
+}
+
+// Mueve la opción enfocada delta posiciones, sin pasar de los extremos, y ajusta la ventana para que siga visible.
+func (l *Lista) Mover(delta int, alto int) {
+	paso := 1
+	if delta < 0 {
+		paso = -1
+	}
+	l.enfocada = l.proxima(min(max(l.enfocada+delta, 0), max(len(l.visibles)-1, 0)), paso)
+	if l.enfocada < l.desplazamiento {
+		l.desplazamiento = l.enfocada
+	}
+	if l.enfocada >= l.desplazamiento+alto {
+		l.desplazamiento = l.enfocada - alto + 1
+	}
+	// Los encabezados sobre la primera opción quedan a la vista.
+	if l.enfocada == l.proxima(0, 1) && l.enfocada < alto {
+		l.desplazamiento = 0
+	}
+	l.desplazamiento = min(l.desplazamiento, max(len(l.visibles)-alto, 0))
+}
+
+// Procesa una tecla de navegación o de filtro; accion es la que le asigna el mapa de teclas del componente (ver MAPA_LISTA).
+// Si la tecla es el número o el Atajo de una opción que se puede elegir, devuelve el índice de esa opción y, si no, -1.
+// procesada es false si la tecla no corresponde a la lista.
+func (l *Lista) ProcesarTecla(con consola.Consola, tecla teclado.Tecla, accion string, opciones []*Opcion) (elegida int, procesada bool) {
+	alto := l.AltoVentana(con)
+	primera, ultima := l.proxima(0, 1), l.proxima(len(l.visibles)-1, -1)
+	switch {
+	case accion == "arriba":
+		if l.enfocada == primera {
+			l.Mover(len(l.visibles), alto)
+		} else {
+			l.Mover(-1, alto)
+		}
+	case accion == "abajo":
+		if l.enfocada == ultima {
+			l.Mover(-len(l.visibles), alto)
+		} else {
+			l.Mover(1, alto)
+		}
+	case accion == "pagina-anterior":
+		l.Mover(-alto, alto)
+	case accion == "pagina-siguiente":
+		l.Mover(alto, alto)
+	case accion == "subir":
+		l.Mover(-1, alto)
+	case accion == "bajar":
+		l.Mover(1, alto)
+	case accion == "apuntar":
+		pos := l.apuntada(tecla)
+		if pos < 0 {
+			return -1, true
+		}
+		l.enfocada = pos
+		return l.elegible(opciones), true
+	case accion == "primera":
+		l.Mover(-len(l.visibles), alto)
+	case accion == "ultima":
+		l.Mover(len(l.visibles), alto)
+	case accion == "cancelar":
+		if len(l.filtro) == 0 {
+			return -1, false
+		}
+		l.filtro = l.filtro[:0]
+		l.Filtrar(opciones)
+		l.Mover(0, alto)
+	case accion == "borrar-filtro":
+		if len(l.filtro) > 0 {
+			l.filtro = l.filtro[:len(l.filtro)-1]
+			l.Filtrar(opciones)
+			l.Mover(0, alto)
+		}
+	case accion != "" || !tecla.Imprimible():
+		return -1, false
+	case len(l.filtro) == 0 && tecla.Runa >= '1' && tecla.Runa <= '9' && !slices.Contains(l.digitos, tecla.Runa):
+		pos, ok := l.numerada(int(tecla.Runa-'0'), alto)
+		if !ok {
+			return -1, true
+		}
+		l.enfocada = pos
+		return l.elegible(opciones), true
+	case len(l.filtro) == 0 && l.atajo(tecla.Runa, opciones) >= 0:
+		l.enfocada = l.atajo(tecla.Runa, opciones)
+		l.Mover(0, alto)
+		return l.elegible(opciones), true
+	default:
+		l.filtro = append(l.filtro, tecla.Runa)
+		l.desplazamiento = 0
+		l.Filtrar(opciones)
+		l.Mover(0, alto)
+	}
+	return -1, true
+}
+
+// Registra en qué fila de la terminal quedó la primera línea de la lista, para ubicar los clics. fila es la primera de
+// las total líneas que presentó el componente y antes las que presentó antes de la lista; 0 si no se conoce
+// (ver consola.UbicarComponente y consola.FilaPresentada).
+func (l *Lista) UbicarLista(fila int, antes int) {
+	l.fila = 0
+	if fila > 0 {
+		l.fila = fila + antes
+	}
+}
+
+// Devuelve la posición de la opción sobre la que ocurrió el evento del ratón, o -1 (también si es un encabezado
+// o un separador).
+func (l Lista) apuntada(tecla teclado.Tecla) int {
+	if tecla.Codigo != teclado.CODIGO_RATON || l.fila <= 0 {
+		return -1
+	}
+	linea := tecla.Raton.Fila - l.fila
+	if linea < 0 || linea >= len(l.lineas) {
+		return -1
+	}
+	if pos := l.lineas[linea]; pos >= 0 && !l.omitidas[pos] {
+		return pos
+	}
+	return -1
+}
+
+// Devuelve el índice de la opción enfocada si se puede elegir, o -1.
+func (l Lista) elegible(opciones []*Opcion) int {
+	if i := l.Enfocada(); i >= 0 && opciones[i].Elegible() {
+		return i
+	}
+	return -1
+}
+
+// Devuelve la posición de la opción con el número n en la ventana (los encabezados y separadores no se numeran).
+func (l Lista) numerada(n int, alto int) (int, bool) {
+	for pos := l.desplazamiento; pos < min(l.desplazamiento+alto, len(l.visibles)); pos++ {
+		if !l.omitidas[pos] {
+			if n--; n == 0 {
+				return pos, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// Devuelve la posición de la opción visible cuyo Atajo es r, o -1.
+func (l Lista) atajo(r rune, opciones []*Opcion) int {
+	return slices.IndexFunc(l.visibles, func(c Coincidencia) bool {
+		o := opciones[c.Indice]
+		return o.Atajo == r && o.Tipo == OPCION_NORMAL
+	})
+}
+
+// Arma las líneas de la ventana visible. presentar devuelve la línea (o líneas) de cada opción; atajo es su número
+// ("1 " a "9 "), o espacios si no lo tiene, y está vacío mientras se filtra y para los encabezados y separadores.
+// Debajo de las opciones se agrega una línea de estado si hay un filtro o más opciones que las que entran en la ventana.
+func (l *Lista) Lineas(con consola.Consola, total int, presentar func(c Coincidencia, enfocada bool, atajo string) string) []string {
+	tema := consola.Extender(con).Tema()
+	alto := l.AltoVentana(con)
+	l.Mover(0, alto)
+	ancho, _, err := consola.Extender(con).DevolverTamaño()
+	if err != nil || ancho <= 1 {
+		ancho = 80
+	}
+
+	lineas := []string{}
+	l.lineas = l.lineas[:0]
+	fin := min(l.desplazamiento+alto, len(l.visibles))
+	n := 0
+	for pos := l.desplazamiento; pos < fin; pos++ {
+		atajo := ""
+		if len(l.filtro) == 0 && !l.omitidas[pos] {
+			atajo = "  "
+			if n++; n <= 9 && !slices.Contains(l.digitos, rune('0'+n)) {
+				atajo = tema.Aplicar(cadena.ROL_ATENUADO, fmt.Sprintf("%d ", n)).S()
+			}
+		}
+		for _, linea := range strings.Split(presentar(l.visibles[pos], pos == l.enfocada, atajo), "\n") {
+			lineas = append(lineas, cadena.Truncar(linea, ancho-1, "…"))
+			l.lineas = append(l.lineas, pos)
+		}
+	}
+	if len(l.visibles) == 0 {
+		lineas = append(lineas, tema.Aplicar(cadena.ROL_ATENUADO, "  (sin resultados)").S())
+	}
+
+	if len(l.filtro) > 0 || len(l.visibles) > alto {
+		estado := fmt.Sprintf("  %d-%d de %d", min(l.desplazamiento+1, fin), fin, len(l.visibles))
+		if len(l.visibles) != total {
+			estado += fmt.Sprintf(" (%d en total)", total)
+		}
+		if len(l.filtro) > 0 {
+			estado += " · filtro: " + string(l.filtro) + "█"
+		} else {
+			estado += " · escriba para filtrar"
+		}
+		lineas = append(lineas, tema.Aplicar(cadena.ROL_ATENUADO, cadena.Truncar(estado, ancho-1, "…")).S())
+	}
+	return lineas
+}
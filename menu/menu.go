@@ -2,48 +2,107 @@ package menu
 
 import (
 	"errors"
+	"slices"
+	"strings"
 
 	"github.com/hernanatn/aplicacion.go/comando"
 	"github.com/hernanatn/aplicacion.go/consola"
 	"github.com/hernanatn/aplicacion.go/consola/cadena"
-	"github.com/hernanatn/aplicacion.go/consola/color"
 	"github.com/hernanatn/aplicacion.go/consola/teclado"
 )
 
 type Accion = comando.Accion
 
-type Opcion struct {
-	Nombre string
-	Accion Accion
-}
-
+// Mapa de teclas de Menu (nombre "menu" en la configuración de teclado): las acciones de MAPA_LISTA y elegir, abrir
+// (el submenú), volver e interrumpir. cancelar, si no hay filtro, vuelve al nivel anterior o cierra el menú.
+var MAPA_MENU = MAPA_LISTA.Combinar(teclado.NuevoMapa(
+	"enter", "elegir",
+	"derecha", "abrir",
+	"izquierda", "volver",
+	"ctrl+c", "interrumpir",
+))
+
+// Error que devuelven Menu.Correr y multimenu.MultiMenu.Correr cuando se cierra el menú con Esc desde el primer nivel
+// (con el filtro vacío). Antes Esc no cerraba el menú: quien lo corra en un ciclo debe tratar este error como el pedido
+// de salir.
+var ERROR_SALIDA = errors.New("menú cerrado por el usuario (Esc)")
+
+// Menu presenta las opciones de a una por línea y devuelve la elegida. Con muchas opciones muestra sólo una ventana
+// (ver Lista.Alto) y permite filtrarlas escribiendo parte de su nombre.
+//
+// Las opciones con Subopciones abren un submenú; el encabezado muestra la ruta recorrida a partir del Titulo
+// (por ejemplo "Ajustes › Red › Proxy") y Esc o ← vuelven al nivel anterior. Esc en el primer nivel cierra el menú.
+// Al volver a correr el menú se retoma el último submenú abierto.
+//
+// Las opciones deshabilitadas se pueden enfocar pero no elegir.
+//
+// Teclas (ver MAPA_MENU): las de Lista, Enter para elegir la opción enfocada, → para abrir su submenú y Esc o ← para volver.
+// Si la Consola usa el ratón (ver consola.ConsolaExtendida.UsarRaton), un clic elige la opción y la rueda la enfoca.
 type Menu struct {
+	Titulo   string
 	Opciones []*Opcion
 	Consola  consola.Consola
-
-	cursor       rune
-	seleccionada int
-	largo        int
-	debeCerrar   bool
+	Teclas   teclado.Mapa // Si es nil, MAPA_MENU con las personalizaciones de "menu" (ver teclado.Personalizar)
+	Lista
+
+	cursor     rune
+	ruta       []*Opcion // Opciones cuyos submenús están abiertos
+	focos      []int     // Opción enfocada en cada nivel de la ruta, para recuperarla al volver
+	largo      int       // Líneas presentadas
+	fila       int       // Fila de la terminal de la primera línea presentada; 0 si no se conoce
+	debeCerrar bool
 }
 
 func NuevoMenu(con consola.Consola, cur rune) *Menu {
 	return &Menu{
-		[]*Opcion{},
-		con,
-		cur,
-		0,
-		0,
-		false,
+		Opciones: []*Opcion{},
+		Consola:  con,
+		cursor:   cur,
 	}
 }
 
 func (m *Menu) RegistrarOpcion(o *Opcion) *Menu {
 	m.Opciones = append(m.Opciones, o)
-	m.largo++
 	return m
 }
 
+// Devuelve las opciones cuyos submenús llevan a la última opción elegida (o al submenú abierto), desde el primer nivel.
+func (m Menu) Ruta() []*Opcion {
+	return slices.Clone(m.ruta)
+}
+
+// Opciones del nivel abierto.
+func (m Menu) opciones() []*Opcion {
+	if len(m.ruta) > 0 {
+		return m.ruta[len(m.ruta)-1].Subopciones
+	}
+	return m.Opciones
+}
+
+// Elige la opción i del nivel abierto: si tiene subopciones abre su submenú y devuelve nil; si no, la devuelve.
+func (m *Menu) elegir(i int) *Opcion {
+	o := m.opciones()[i]
+	if len(o.Subopciones) == 0 {
+		return o
+	}
+	m.ruta = append(m.ruta, o)
+	m.focos = append(m.focos, i)
+	m.Reiniciar(m.opciones())
+	return nil
+}
+
+// Vuelve al nivel anterior, enfocando la opción que abrió el submenú. Devuelve false si ya estaba en el primer nivel.
+func (m *Menu) volver() bool {
+	if len(m.ruta) == 0 {
+		return false
+	}
+	foco := m.focos[len(m.focos)-1]
+	m.ruta, m.focos = m.ruta[:len(m.ruta)-1], m.focos[:len(m.focos)-1]
+	m.Reiniciar(m.opciones())
+	m.Enfocar(foco)
+	return true
+}
+
 func (m Menu) borrarMenu() *Menu {
 	m.Consola.BorrarLinea()
 	for i := 0; i < m.largo; i++ {
@@ -53,24 +112,60 @@ func (m Menu) borrarMenu() *Menu {
 	return &m
 }
 
-func (m Menu) imprimirOpcion(o *Opcion, seleccionada bool) {
+// Encabezado con la ruta de submenús abiertos, o "" si no hay título ni submenús.
+func (m Menu) migas() string {
+	tema := consola.Extender(m.Consola).Tema()
+	partes := []string{}
+	if m.Titulo != "" {
+		partes = append(partes, m.Titulo)
+	}
+	for _, o := range m.ruta {
+		partes = append(partes, o.Nombre)
+	}
+	if len(partes) == 0 {
+		return ""
+	}
+	migas := tema.Aplicar(cadena.ROL_ATENUADO, strings.Join(partes[:len(partes)-1], " › ")).S()
+	if len(partes) > 1 {
+		migas += tema.Aplicar(cadena.ROL_ATENUADO, " › ").S()
+	}
+	migas += tema.Aplicar(cadena.ROL_ENFASIS, partes[len(partes)-1]).S()
+	if len(m.ruta) > 0 {
+		migas += tema.Aplicar(cadena.ROL_ATENUADO, "  (← volver)").S()
+	}
+	return migas
+}
 
-	var c cadena.Cadena
+func (m Menu) presentarOpcion(c Coincidencia, seleccionada bool, atajo string) string {
+	tema := consola.Extender(m.Consola).Tema()
 	if seleccionada {
-		c = cadena.Cadena(string(m.cursor) + "\t" + o.Nombre).Negrita().Colorear(color.CyanFondo)
-	} else {
-		c = cadena.Cadena(" \t" + o.Nombre)
+		seleccion := tema.Formateador(cadena.ROL_SELECCION)
+		return m.opciones()[c.Indice].Presentar(tema, c, string(m.cursor)+" ", atajo, seleccion)
 	}
+	return m.opciones()[c.Indice].Presentar(tema, c, "  ", atajo, func(s string) string { return s })
+}
 
-	m.Consola.ImprimirLinea(c)
-
+func (m *Menu) renderizar() *Menu {
+	lineas := m.Lineas(m.Consola, len(m.opciones()), m.presentarOpcion)
+	antes := 0
+	if migas := m.migas(); migas != "" {
+		lineas = append([]string{migas}, lineas...)
+		antes = 1
+	}
+	for _, l := range lineas {
+		m.Consola.ImprimirLinea(cadena.Cadena(l))
+	}
+	m.largo = len(lineas)
+	m.fila = consola.FilaPresentada(m.Consola, m.fila, m.largo)
+	m.UbicarLista(m.fila, antes)
+	return m
 }
 
-func (m Menu) renderizar() *Menu {
-	for i, o := range m.Opciones {
-		m.imprimirOpcion(o, i == m.seleccionada)
+func (m Menu) teclas() teclado.Mapa {
+	if m.Teclas != nil {
+		return m.Teclas
 	}
-	return &m
+	return teclado.MapaDe("menu", MAPA_MENU)
 }
 
 func (m Menu) DebeCerrar() bool {
@@ -79,53 +174,75 @@ func (m Menu) DebeCerrar() bool {
 
 func (m *Menu) abrir() {
 	m.debeCerrar = false
-	m.seleccionada = 0
+	m.Reiniciar(m.opciones())
 	m.renderizar()
 }
+
+// Corre el menú e invoca la Accion de la opción elegida con la Consola del menú y argumentos, devolviendo su resultado.
+// Si la opción no tiene Accion, devuelve su Valor.
+func (m *Menu) CorrerAccion(argumentos ...any) (res any, cod comando.CodigoError, err error) {
+	o, err := m.Correr()
+	switch {
+	case o == nil:
+		return nil, comando.ERROR, err
+	case o.Accion == nil:
+		return o.Valor, comando.EXITO, err
+	}
+	return o.Accion(m.Consola, comando.Opciones{}, comando.Parametros{}, argumentos...)
+}
+
+// Presenta el menú hasta que se elija una opción y la devuelve. Si se lo cierra con Esc desde el primer nivel devuelve
+// nil y ERROR_SALIDA.
 func (m *Menu) Correr() (*Opcion, error) {
+	con := consola.Extender(m.Consola)
+	fila, raton := consola.UbicarComponente(con)
+	if raton {
+		defer con.DeshabilitarRaton()
+	}
+	m.fila = fila
 	m.abrir()
 	var opcionRelevante *Opcion
 	var errores []error
+	teclas := m.teclas()
 	for !m.DebeCerrar() {
-		var tecla []byte = make([]byte, 3)
-		_, err := m.Consola.LeerTecla(&tecla)
+		tecla, err := con.LeerEvento()
 		if err != nil {
-			m.Consola.ImprimirError("menu.go / 93 > m.Consola.LeerTecla(&tecla)", err)
+			m.Consola.ImprimirError("menu.go > m.Consola.LeerEvento()", err)
 			errores = append(errores, err)
+			m.debeCerrar = true
 		}
-		switch tecla[0] {
-		case teclado.CTRL_C:
+		accion := teclas.Accion(tecla)
+		switch {
+		case err != nil:
+
+		case accion == "interrumpir":
 			errores = append(errores, errors.New("programa cerrado por el usuario ^C"))
 			m.debeCerrar = true
 
-		case teclado.ENTER:
-			opcionRelevante = m.Opciones[m.seleccionada]
-			m.debeCerrar = true
+		case accion == "elegir":
+			if i := m.Enfocada(); i >= 0 && m.opciones()[i].Elegible() {
+				opcionRelevante = m.elegir(i)
+				m.debeCerrar = opcionRelevante != nil
+			}
 
-		case teclado.ESC:
-			switch tecla[1] {
-			case teclado.CSI:
-				switch tecla[2] {
-				case teclado.FLECHA_ARRIBA[2]:
-					if m.seleccionada > 0 {
-						m.seleccionada--
-					} else {
-						m.seleccionada = len(m.Opciones) - 1
-					}
-				case teclado.FLECHA_ABAJO[2]:
-					if m.seleccionada < len(m.Opciones)-1 {
-						m.seleccionada++
-					} else {
-						m.seleccionada = 0
-					}
-				}
-			default:
-
-				m.borrarMenu()
-				m.abrir()
-				//m.debeCerrar = true
+		case accion == "abrir":
+			if i := m.Enfocada(); i >= 0 && len(m.opciones()[i].Subopciones) > 0 {
+				m.elegir(i)
 			}
+
+		case accion == "volver":
+			m.volver()
+
 		default:
+			i, procesada := m.ProcesarTecla(m.Consola, tecla, accion, m.opciones())
+			switch {
+			case i >= 0:
+				opcionRelevante = m.elegir(i)
+				m.debeCerrar = opcionRelevante != nil
+			case !procesada && accion == "cancelar" && !m.volver():
+				errores = append(errores, ERROR_SALIDA)
+				m.debeCerrar = true
+			}
 		}
 		m.borrarMenu()
 		m.renderizar()
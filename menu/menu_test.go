@@ -0,0 +1,157 @@
+package menu_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/comando"
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/menu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFiltrar comprueba el orden de las coincidencias y las posiciones resaltadas
+func TestFiltrar(t *testing.T) {
+	c, ok := menu.Coincidir("cfg", "Configuración general")
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 3, 5}, c.Posiciones)
+
+	_, ok = menu.Coincidir("xyz", "Configuración general")
+	assert.False(t, ok)
+
+	textos := []string{"Borrar configuración", "Config", "Cifrado general", "Salir"}
+	var indices []int
+	for _, c := range menu.Filtrar("conf", textos) {
+		indices = append(indices, c.Indice)
+	}
+	assert.Equal(t, []int{1, 0}, indices)
+
+	assert.Len(t, menu.Filtrar("", textos), len(textos))
+
+	resaltado := menu.Resaltar("Config", []int{0, 1, 3}, func(s string) string { return s }, func(s string) string { return "[" + s + "]" })
+	assert.Equal(t, "[Co]n[f]ig", resaltado)
+}
+
+// TestDesdeComando arma el menú de un árbol de comandos y ejecuta una hoja con argumentos
+func TestDesdeComando(t *testing.T) {
+	var recibidos comando.Opciones
+	raiz := comando.NuevoComando("app", "app", nil, "", nil, nil)
+	red := comando.NuevoComando("red", "red", nil, "", nil, nil)
+	red.RegistrarComando(comando.NuevoComando("proxy", "proxy <url>", nil, "", func(con comando.Consola, opciones comando.Opciones, parametros comando.Parametros, argumentos ...any) (any, comando.CodigoError, error) {
+		for _, a := range argumentos {
+			recibidos = append(recibidos, a.(string))
+		}
+		return nil, comando.EXITO, nil
+	}, nil))
+	raiz.RegistrarComando(red)
+	raiz.RegistrarComando(comando.NuevoComando("oculto", "oculto", nil, "", nil, nil, comando.Config{EsOculto: true}))
+
+	m := menu.DesdeComando(consola.NuevaConsola(nil, nil), raiz)
+	assert.Equal(t, "app", m.Titulo)
+	require.Len(t, m.Opciones, 1)
+	assert.Equal(t, "red", m.Opciones[0].Nombre)
+	require.Len(t, m.Opciones[0].Subopciones, 1)
+	proxy := m.Opciones[0].Subopciones[0]
+	assert.Nil(t, proxy.Subopciones)
+
+	_, _, err := proxy.Accion(nil, nil, nil, "http://localhost")
+	require.NoError(t, err)
+	assert.Equal(t, comando.Opciones{"http://localhost"}, recibidos)
+	ruta, _ := menu.ValorDe[[]string](proxy)
+	assert.Equal(t, []string{"red", "proxy"}, ruta)
+
+	// Un comando con Accion propia y subcomandos se puede ejecutar desde su submenú.
+	var ejecutado bool
+	estado := comando.NuevoComando("estado", "estado", nil, "", func(con comando.Consola, opciones comando.Opciones, parametros comando.Parametros, argumentos ...any) (any, comando.CodigoError, error) {
+		ejecutado = true
+		return nil, comando.EXITO, nil
+	}, nil)
+	estado.RegistrarComando(comando.NuevoComando("detalle", "detalle", nil, "", nil, nil))
+	raiz.RegistrarComando(estado)
+
+	m = menu.DesdeComando(consola.NuevaConsola(nil, nil), raiz)
+	require.Len(t, m.Opciones, 2)
+	sub := m.Opciones[1].Subopciones
+	require.Len(t, sub, 3)
+	assert.Equal(t, "Ejecutar estado", sub[0].Nombre)
+	assert.Equal(t, menu.OPCION_SEPARADOR, sub[1].Tipo)
+	_, _, err = sub[0].Accion(nil, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, ejecutado)
+}
+
+// TestOpcion comprueba qué opciones se pueden elegir y la conversión de su Valor
+func TestOpcion(t *testing.T) {
+	assert.True(t, menu.Opcion{Nombre: "Abrir"}.Elegible())
+	assert.False(t, menu.Opcion{Nombre: "Guardar", Deshabilitada: true}.Elegible())
+	assert.False(t, menu.Encabezado("Archivo").Elegible())
+	assert.False(t, menu.Separador().Elegible())
+
+	puerto, ok := menu.ValorDe[int](&menu.Opcion{Valor: 8080})
+	assert.True(t, ok)
+	assert.Equal(t, 8080, puerto)
+	_, ok = menu.ValorDe[string](&menu.Opcion{Valor: 8080})
+	assert.False(t, ok)
+	_, ok = menu.ValorDe[int](nil)
+	assert.False(t, ok)
+}
+
+// TestDigitosQueFiltran verifica que un dígito con el que empieza el nombre de una opción filtre en lugar de elegir
+// la opción con ese número
+func TestDigitosQueFiltran(t *testing.T) {
+	g := &consola.Grabacion{
+		Encabezado: consola.EncabezadoGrabacion{Version: 2, Ancho: 80, Alto: 24},
+		Eventos: []consola.EventoGrabacion{
+			{Tiempo: 0.1, Tipo: consola.EVENTO_ENTRADA, Datos: "1"},
+			{Tiempo: 0.2, Tipo: consola.EVENTO_ENTRADA, Datos: "\r"},
+		},
+	}
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer es.Close()
+	go io.Copy(io.Discard, ls)
+	con, err := consola.NuevaConsolaReproducida(g, es, false)
+	require.NoError(t, err)
+	defer con.Cerrar()
+
+	m := menu.NuevoMenu(con, '>')
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "alfa"})
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "1password"})
+	elegida, err := m.Correr()
+	require.NoError(t, err)
+	require.NotNil(t, elegida)
+	assert.Equal(t, "1password", elegida.Nombre)
+}
+
+// TestMenuConConsolaBasica verifica que el menú funcione con una Consola que no implementa consola.ConsolaExtendida
+func TestMenuConConsolaBasica(t *testing.T) {
+	g := &consola.Grabacion{
+		Encabezado: consola.EncabezadoGrabacion{Version: 2, Ancho: 80, Alto: 24},
+		Eventos: []consola.EventoGrabacion{
+			{Tiempo: 0.1, Tipo: consola.EVENTO_ENTRADA, Datos: "\x1b[B"},
+			{Tiempo: 0.2, Tipo: consola.EVENTO_ENTRADA, Datos: "\r"},
+		},
+	}
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer es.Close()
+	go io.Copy(io.Discard, ls)
+	con, err := consola.NuevaConsolaReproducida(g, es, false)
+	require.NoError(t, err)
+	defer con.Cerrar()
+
+	// Sólo los métodos de consola.Consola.
+	basica := struct{ consola.Consola }{con}
+	_, extendida := any(basica).(consola.ConsolaExtendida)
+	require.False(t, extendida)
+
+	m := menu.NuevoMenu(basica, '>')
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "uno"})
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "dos"})
+	elegida, err := m.Correr()
+	require.NoError(t, err)
+	require.NotNil(t, elegida)
+	assert.Equal(t, "dos", elegida.Nombre)
+}
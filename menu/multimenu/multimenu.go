@@ -2,11 +2,12 @@ package multimenu
 
 import (
 	"errors"
+	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/hernanatn/aplicacion.go/consola"
 	"github.com/hernanatn/aplicacion.go/consola/cadena"
-	"github.com/hernanatn/aplicacion.go/consola/color"
 	"github.com/hernanatn/aplicacion.go/consola/teclado"
 	"github.com/hernanatn/aplicacion.go/menu"
 )
@@ -14,35 +15,108 @@ import (
 type Accion = menu.Accion
 type Opcion = menu.Opcion
 
+// Mapa de teclas de MultiMenu (nombre "multimenu" en la configuración de teclado): las acciones de menu.MAPA_LISTA y
+// alternar (la marca de la opción enfocada), marcar-todas, desmarcar-todas, invertir, aceptar e interrumpir.
+var MAPA_MULTIMENU = menu.MAPA_LISTA.Combinar(teclado.NuevoMapa(
+	"espacio", "alternar",
+	"ctrl+a", "marcar-todas",
+	"ctrl+n", "desmarcar-todas",
+	"ctrl+r", "invertir",
+	"enter", "aceptar",
+	"ctrl+c", "interrumpir",
+))
+
+// MultiMenu presenta las opciones de a una por línea y devuelve las marcadas, en el orden de Opciones. Como menu.Menu,
+// muestra sólo una ventana de las opciones y permite filtrarlas escribiendo parte de su nombre.
+//
+// Con Minimo y Maximo se limita la cantidad de opciones marcadas: no se pueden marcar más de Maximo (si no es 0)
+// ni aceptar menos de Minimo, y en ambos casos se muestra un aviso debajo de las opciones.
+//
+// Teclas (ver MAPA_MULTIMENU): las de menu.Lista (los dígitos y los atajos marcan su opción), Espacio para marcar la
+// opción enfocada, ^A para marcar todas las opciones visibles, ^R para invertir su marca, ^N para desmarcarlas y Enter
+// para aceptar. Esc con el filtro vacío cierra el menú sin elegir nada, como en menu.Menu: Correr devuelve
+// menu.ERROR_SALIDA. Si la Consola usa el ratón (ver consola.ConsolaExtendida.UsarRaton), un clic alterna la marca de
+// la opción.
 type MultiMenu struct {
 	Opciones []*Opcion
 	Consola  consola.Consola
+	Minimo   int
+	Maximo   int          // Sin límite si es 0
+	Teclas   teclado.Mapa // Si es nil, MAPA_MULTIMENU con las personalizaciones de "multimenu" (ver teclado.Personalizar)
+	menu.Lista
 
 	cursor        rune
-	enfocada      int
-	seleccionadas []int
-	largo         int
+	seleccionadas []int // Índices en Opciones, ordenados
+	aviso         string
+	largo         int // Líneas presentadas
+	fila          int // Fila de la terminal de la primera línea presentada; 0 si no se conoce
 	debeCerrar    bool
 }
 
 func NuevoMultiMenu(con consola.Consola, cur rune) *MultiMenu {
 	return &MultiMenu{
-		[]*Opcion{},
-		con,
-		cur,
-		0,
-		[]int{},
-		0,
-		false,
+		Opciones:      []*Opcion{},
+		Consola:       con,
+		cursor:        cur,
+		seleccionadas: []int{},
 	}
 }
 
 func (m *MultiMenu) RegistrarOpcion(o *Opcion) *MultiMenu {
 	m.Opciones = append(m.Opciones, o)
-	m.largo++
 	return m
 }
 
+// Marca de antemano las opciones con los índices dados, como si se las marcara al correr: se ignoran las que no son
+// elegibles y las que excedan Maximo, que por eso se debe asignar antes.
+func (m *MultiMenu) Preseleccionar(indices ...int) *MultiMenu {
+	for _, i := range indices {
+		if i >= 0 && i < len(m.Opciones) {
+			m.marcar(i)
+		}
+	}
+	return m
+}
+
+// Marca la opción i si no lo estaba, sin pasar de Maximo.
+func (m *MultiMenu) marcar(i int) {
+	switch {
+	case slices.Contains(m.seleccionadas, i) || !m.Opciones[i].Elegible():
+	case m.Maximo > 0 && len(m.seleccionadas) >= m.Maximo:
+		m.aviso = "Se pueden elegir hasta " + cantidad(m.Maximo, "opción", "opciones")
+	default:
+		m.seleccionadas = append(m.seleccionadas, i)
+		slices.Sort(m.seleccionadas)
+	}
+}
+
+func (m *MultiMenu) desmarcar(i int) {
+	if j := slices.Index(m.seleccionadas, i); j >= 0 {
+		m.seleccionadas = slices.Delete(m.seleccionadas, j, j+1)
+	}
+}
+
+func (m *MultiMenu) alternar(i int) {
+	if slices.Contains(m.seleccionadas, i) {
+		m.desmarcar(i)
+	} else {
+		m.marcar(i)
+	}
+}
+
+func (m *MultiMenu) invertir() {
+	visibles := m.Visibles()
+	marcadas := slices.DeleteFunc(slices.Clone(visibles), func(i int) bool { return !slices.Contains(m.seleccionadas, i) })
+	for _, i := range marcadas {
+		m.desmarcar(i)
+	}
+	for _, i := range visibles {
+		if !slices.Contains(marcadas, i) {
+			m.marcar(i)
+		}
+	}
+}
+
 func (m MultiMenu) borrarMenu() *MultiMenu {
 	m.Consola.BorrarLinea()
 	for i := 0; i < m.largo; i++ {
@@ -52,33 +126,72 @@ func (m MultiMenu) borrarMenu() *MultiMenu {
 	return &m
 }
 
-func (m MultiMenu) imprimirOpcion(o *Opcion, enfocada bool) {
+func (m MultiMenu) presentarOpcion(c menu.Coincidencia, enfocada bool, atajo string) string {
 
-	var esEnfocada cadena.Cadena = " "
-	var esSeleccionada cadena.Cadena = " "
+	var esEnfocada string = " "
+	var esSeleccionada string = " "
 	var formatos []cadena.Formateador = make([]cadena.Formateador, 0)
-	var c cadena.Cadena
+	tema := consola.Extender(m.Consola).Tema()
 
 	if enfocada {
-		esEnfocada = cadena.Cadena(string(m.cursor))
-		formatos = append(formatos, cadena.Negrita)
+		esEnfocada = string(m.cursor)
+		formatos = append(formatos, tema.Formateador(cadena.ROL_ENFASIS))
 	}
-	if slices.Contains(m.seleccionadas, slices.Index(m.Opciones, o)) {
-		esSeleccionada = cadena.Cadena("■")
-		formatos = append(formatos, cadena.Negrita)
-		formatos = append(formatos, cadena.Coloreador(color.CyanFondo))
+	if slices.Contains(m.seleccionadas, c.Indice) {
+		esSeleccionada = "■"
+		formatos = append(formatos, tema.Formateador(cadena.ROL_SELECCION))
 	}
 
-	c = (esEnfocada + esSeleccionada + "\t" + cadena.Cadena(o.Nombre)).Formatear(formatos...)
-	m.Consola.ImprimirLinea(c)
+	normal := func(s string) string { return cadena.Cadena(s).Formatear(formatos...).S() }
+	return m.Opciones[c.Indice].Presentar(tema, c, esEnfocada+esSeleccionada+" ", atajo, normal)
 
 }
 
-func (m MultiMenu) renderizar() *MultiMenu {
-	for i, o := range m.Opciones {
-		m.imprimirOpcion(o, i == m.enfocada)
+func cantidad(n int, singular string, plural string) string {
+	if n == 1 {
+		return "1 " + singular
 	}
-	return &m
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// Línea con la cantidad de opciones marcadas y los límites, o el aviso pendiente. Vacía si no hay límites ni aviso.
+func (m MultiMenu) estado() string {
+	tema := consola.Extender(m.Consola).Tema()
+	if m.aviso != "" {
+		return tema.Aplicar(cadena.ROL_ADVERTENCIA, "  "+m.aviso).S()
+	}
+	limites := []string{}
+	if m.Minimo > 0 {
+		limites = append(limites, fmt.Sprintf("mín. %d", m.Minimo))
+	}
+	if m.Maximo > 0 {
+		limites = append(limites, fmt.Sprintf("máx. %d", m.Maximo))
+	}
+	if len(limites) == 0 {
+		return ""
+	}
+	return tema.Aplicar(cadena.ROL_ATENUADO, fmt.Sprintf("  %s (%s)", cantidad(len(m.seleccionadas), "elegida", "elegidas"), strings.Join(limites, ", "))).S()
+}
+
+func (m *MultiMenu) renderizar() *MultiMenu {
+	lineas := m.Lineas(m.Consola, len(m.Opciones), m.presentarOpcion)
+	if estado := m.estado(); estado != "" {
+		lineas = append(lineas, estado)
+	}
+	for _, l := range lineas {
+		m.Consola.ImprimirLinea(cadena.Cadena(l))
+	}
+	m.largo = len(lineas)
+	m.fila = consola.FilaPresentada(m.Consola, m.fila, m.largo)
+	m.UbicarLista(m.fila, 0)
+	return m
+}
+
+func (m MultiMenu) teclas() teclado.Mapa {
+	if m.Teclas != nil {
+		return m.Teclas
+	}
+	return teclado.MapaDe("multimenu", MAPA_MULTIMENU)
 }
 
 func (m MultiMenu) DebeCerrar() bool {
@@ -87,68 +200,92 @@ func (m MultiMenu) DebeCerrar() bool {
 
 func (m *MultiMenu) abrir() {
 	m.debeCerrar = false
-	m.enfocada = 0
+	m.aviso = ""
+	m.Reiniciar(m.Opciones)
 	m.renderizar()
 }
+
+// Devuelve las opciones marcadas, en el orden de Opciones.
 func (m *MultiMenu) Correr() ([]*Opcion, error) {
+	_, opciones, err := m.CorrerIndices()
+	return opciones, err
+}
+
+// Como Correr, pero devuelve también los índices de las opciones marcadas, en orden.
+func (m *MultiMenu) CorrerIndices() ([]int, []*Opcion, error) {
+	con := consola.Extender(m.Consola)
+	fila, raton := consola.UbicarComponente(con)
+	if raton {
+		defer con.DeshabilitarRaton()
+	}
+	m.fila = fila
 	m.abrir()
-	var opcionesSeleccionadas []*Opcion
 	var errores []error
+	cancelado := false
+	teclas := m.teclas()
 	for !m.DebeCerrar() {
-		var tecla []byte = make([]byte, 3)
-		_, err := m.Consola.LeerTecla(&tecla)
+		tecla, err := con.LeerEvento()
 		if err != nil {
-			m.Consola.ImprimirError("multimenu.go / 99 > m.Consola.LeerTecla(&tecla)", err)
+			m.Consola.ImprimirError("multimenu.go > m.Consola.LeerEvento()", err)
 			errores = append(errores, err)
+			m.debeCerrar = true
 		}
-		switch tecla[0] {
-		case teclado.CTRL_C:
+		m.aviso = ""
+		accion := teclas.Accion(tecla)
+		switch {
+		case err != nil:
+
+		case accion == "interrumpir":
 			errores = append(errores, errors.New("programa cerrado por el usuario ^C"))
 			m.debeCerrar = true
 
-		case teclado.ESPACIO:
-			i := slices.Index(m.seleccionadas, m.enfocada)
-			if i >= 0 {
-				m.seleccionadas = slices.Concat(m.seleccionadas[:i], m.seleccionadas[i+1:])
-			} else {
-				m.seleccionadas = append(m.seleccionadas, m.enfocada)
+		case accion == "alternar":
+			if i := m.Enfocada(); i >= 0 {
+				m.alternar(i)
 			}
-			opcionesSeleccionadas = append(opcionesSeleccionadas, m.Opciones[m.enfocada])
 
-		case teclado.ENTER:
-			m.debeCerrar = true
+		case accion == "marcar-todas":
+			for _, i := range m.Visibles() {
+				m.marcar(i)
+			}
+
+		case accion == "desmarcar-todas":
+			for _, i := range m.Visibles() {
+				m.desmarcar(i)
+			}
+
+		case accion == "invertir":
+			m.invertir()
 
-		case teclado.ESC:
-			switch tecla[1] {
-			case teclado.CSI:
-				switch tecla[2] {
-				case teclado.FLECHA_ARRIBA[2]:
-					if m.enfocada > 0 {
-						m.enfocada--
-					} else {
-						m.enfocada = len(m.Opciones) - 1
-					}
-				case teclado.FLECHA_ABAJO[2]:
-					if m.enfocada < len(m.Opciones)-1 {
-						m.enfocada++
-					} else {
-						m.enfocada = 0
-					}
-				}
-			default:
-
-				m.borrarMenu()
-				m.abrir()
-				//m.debeCerrar = true
+		case accion == "aceptar":
+			if len(m.seleccionadas) < m.Minimo {
+				m.aviso = "Elija al menos " + cantidad(m.Minimo, "opción", "opciones")
+			} else {
+				m.debeCerrar = true
 			}
+
 		default:
+			i, procesada := m.ProcesarTecla(m.Consola, tecla, accion, m.Opciones)
+			switch {
+			case i >= 0:
+				m.alternar(i)
+			case !procesada && accion == "cancelar":
+				errores = append(errores, menu.ERROR_SALIDA)
+				cancelado = true
+				m.debeCerrar = true
+			}
 		}
 		m.borrarMenu()
 		m.renderizar()
 	}
 
-	for _, i := range m.seleccionadas {
-		opcionesSeleccionadas = append(opcionesSeleccionadas, m.Opciones[i])
+	if cancelado {
+		return nil, nil, errors.Join(errores...)
+	}
+	indices := slices.Clone(m.seleccionadas)
+	opcionesSeleccionadas := make([]*Opcion, len(indices))
+	for j, i := range indices {
+		opcionesSeleccionadas[j] = m.Opciones[i]
 	}
-	return opcionesSeleccionadas, errors.Join(errores...)
+	return indices, opcionesSeleccionadas, errors.Join(errores...)
 }
@@ -0,0 +1,87 @@
+package multimenu_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/menu"
+	"github.com/hernanatn/aplicacion.go/menu/multimenu"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	espacio  = " "
+	abajo    = "\x1b[B"
+	enter    = "\r"
+	todas    = "\x01" // ^A
+	ninguna  = "\x0e" // ^N
+	invertir = "\x12" // ^R
+)
+
+// Corre un MultiMenu de cuatro opciones (la tercera deshabilitada) con las teclas dadas y devuelve los índices marcados.
+func correr(t *testing.T, preparar func(m *multimenu.MultiMenu), teclas ...string) ([]int, error) {
+	g := &consola.Grabacion{Encabezado: consola.EncabezadoGrabacion{Version: 2, Ancho: 80, Alto: 24}}
+	for i, tecla := range teclas {
+		g.Eventos = append(g.Eventos, consola.EventoGrabacion{Tiempo: float64(i), Tipo: consola.EVENTO_ENTRADA, Datos: tecla})
+	}
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer es.Close()
+	go io.Copy(io.Discard, ls)
+	con, err := consola.NuevaConsolaReproducida(g, es, false)
+	require.NoError(t, err)
+	defer con.Cerrar()
+
+	m := multimenu.NuevoMultiMenu(con, '>')
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "uno"})
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "dos"})
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "tres", Deshabilitada: true})
+	m.RegistrarOpcion(&menu.Opcion{Nombre: "cuatro"})
+	if preparar != nil {
+		preparar(m)
+	}
+	indices, opciones, err := m.CorrerIndices()
+	require.Len(t, opciones, len(indices))
+	return indices, err
+}
+
+// TestMultiMenu recorre las acciones de marcado, los límites y la preselección
+func TestMultiMenu(t *testing.T) {
+	casos := []struct {
+		nombre   string
+		preparar func(m *multimenu.MultiMenu)
+		teclas   []string
+		esperado []int
+	}{
+		{"alternar varias veces no duplica", nil, []string{espacio, espacio, espacio, enter}, []int{0}},
+		{"alternar y mover", nil, []string{espacio, abajo, espacio, enter}, []int{0, 1}},
+		{"los dígitos marcan su opción", nil, []string{"4", "1", enter}, []int{0, 3}},
+		{"marcar todas omite las deshabilitadas", nil, []string{todas, enter}, []int{0, 1, 3}},
+		{"invertir", nil, []string{espacio, invertir, enter}, []int{1, 3}},
+		{"desmarcar todas", nil, []string{todas, ninguna, enter}, []int{}},
+		{"máximo", func(m *multimenu.MultiMenu) { m.Maximo = 2 }, []string{todas, enter}, []int{0, 1}},
+		{"mínimo", func(m *multimenu.MultiMenu) { m.Minimo = 1 }, []string{enter, espacio, enter}, []int{0}},
+		{"preseleccionar", func(m *multimenu.MultiMenu) { m.Preseleccionar(3, 0, 9) }, []string{enter}, []int{0, 3}},
+		{"preseleccionar respeta Elegible y Maximo", func(m *multimenu.MultiMenu) {
+			m.Maximo = 1
+			m.Preseleccionar(2, 3, 0)
+		}, []string{enter}, []int{3}},
+	}
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			indices, err := correr(t, c.preparar, c.teclas...)
+			require.NoError(t, err)
+			assert.Equal(t, c.esperado, indices)
+		})
+	}
+}
+
+// TestCancelarMultiMenu verifica que Esc con el filtro vacío cierre el menú sin devolver opciones
+func TestCancelarMultiMenu(t *testing.T) {
+	indices, err := correr(t, nil, espacio, "\x1b")
+	assert.ErrorIs(t, err, menu.ERROR_SALIDA)
+	assert.Empty(t, indices)
+}
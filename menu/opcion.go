@@ -0,0 +1,99 @@
+package menu
+
+import (
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+)
+
+type TipoOpcion int
+
+const (
+	OPCION_NORMAL     TipoOpcion = iota
+	OPCION_ENCABEZADO            // Título de un grupo de opciones; no se puede enfocar
+	OPCION_SEPARADOR             // Línea divisoria; no se puede enfocar
+)
+
+type Opcion struct {
+	Nombre        string
+	Descripcion   string // Se presenta atenuada, en una segunda línea
+	Accion        Accion
+	Valor         any    // Valor asociado, ver ValorDe
+	Atajo         rune   // Tecla que elige la opción directamente mientras el filtro está vacío
+	Deshabilitada bool   // Se presenta atenuada y no se puede elegir
+	Motivo        string // Por qué la opción está deshabilitada
+	Tipo          TipoOpcion
+	Subopciones   []*Opcion // Si hay subopciones, elegir la opción abre un submenú con ellas
+}
+
+// Devuelve un encabezado para agrupar las opciones que lo siguen.
+func Encabezado(nombre string) *Opcion {
+	return &Opcion{Nombre: nombre, Tipo: OPCION_ENCABEZADO}
+}
+
+// Devuelve un separador entre grupos de opciones.
+func Separador() *Opcion {
+	return &Opcion{Tipo: OPCION_SEPARADOR}
+}
+
+// Devuelve true si la opción se puede elegir: no es un encabezado, un separador ni está deshabilitada.
+func (o Opcion) Elegible() bool {
+	return o.Tipo == OPCION_NORMAL && !o.Deshabilitada
+}
+
+// Devuelve el Valor de o como T. ok es false si o es nil o su Valor no es de tipo T.
+//
+// # Ejemplo:
+//
+//	o, err := m.Correr()
+//	puerto, ok := menu.ValorDe[int](o)
+func ValorDe[T any](o *Opcion) (v T, ok bool) {
+	if o == nil {
+		return v, false
+	}
+	v, ok = o.Valor.(T)
+	return v, ok
+}
+
+// Devuelve los nombres de las opciones, sobre los que se aplica el filtro.
+func Nombres(opciones []*Opcion) []string {
+	nombres := make([]string, len(opciones))
+	for i, o := range opciones {
+		nombres[i] = o.Nombre
+	}
+	return nombres
+}
+
+// Presenta la opción en una Lista: prefijo (el cursor o las marcas) y atajo van antes del Nombre, en el que se resaltan
+// las posiciones de c. normal se aplica al prefijo y al Nombre. La Descripcion va en una segunda línea.
+func (o Opcion) Presentar(tema cadena.Tema, c Coincidencia, prefijo string, atajo string, normal cadena.Formateador) string {
+	switch o.Tipo {
+	case OPCION_ENCABEZADO:
+		return tema.Aplicar(cadena.ROL_SUBTITULO, o.Nombre).S()
+	case OPCION_SEPARADOR:
+		return tema.Aplicar(cadena.ROL_ATENUADO, "  "+strings.Repeat("─", 24)).S()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(normal(prefijo) + atajo)
+	if o.Deshabilitada {
+		sb.WriteString(tema.Aplicar(cadena.ROL_ATENUADO, o.Nombre).S())
+		if o.Motivo != "" {
+			sb.WriteString(tema.Aplicar(cadena.ROL_ATENUADO, " ("+o.Motivo+")").S())
+		}
+	} else {
+		resaltado := func(s string) string { return normal(tema.Estilo(cadena.ROL_ENFASIS).ConNegrita().Aplicar(s)) }
+		sb.WriteString(Resaltar(o.Nombre, c.Posiciones, normal, resaltado))
+	}
+	if o.Atajo != 0 {
+		sb.WriteString(tema.Aplicar(cadena.ROL_ATENUADO, " ["+string(o.Atajo)+"]").S())
+	}
+	if len(o.Subopciones) > 0 {
+		sb.WriteString(tema.Aplicar(cadena.ROL_ATENUADO, " ›").S())
+	}
+	if o.Descripcion != "" {
+		sangria := strings.Repeat(" ", cadena.Ancho(prefijo)+cadena.Ancho(atajo))
+		sb.WriteString("\n" + sangria + tema.Aplicar(cadena.ROL_ATENUADO, o.Descripcion).S())
+	}
+	return sb.String()
+}
@@ -0,0 +1,377 @@
+package visor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"golang.org/x/term"
+)
+
+// Filas visibles cuando no se puede determinar el alto de la terminal.
+const ALTO_POR_DEFECTO = 20
+
+// Mapa de teclas de Visor (nombre "visor" en la configuración de teclado). Acciones: arriba, abajo, pagina-anterior,
+// pagina-siguiente, primera, ultima, columna-anterior, columna-siguiente, ordenar, filtrar, marcar, aceptar, salir,
+// interrumpir y apuntar (enfoca la fila bajo el ratón). Mientras se escribe el filtro sólo se aplica interrumpir.
+var MAPA_VISOR = teclado.NuevoMapa(
+	"arriba", "arriba",
+	"abajo", "abajo",
+	"repag", "pagina-anterior",
+	"avpag", "pagina-siguiente",
+	"inicio", "primera",
+	"fin", "ultima",
+	"izquierda", "columna-anterior",
+	"derecha", "columna-siguiente",
+	"s", "ordenar",
+	"/", "filtrar",
+	"espacio", "marcar",
+	"enter", "aceptar",
+	"q", "salir",
+	"esc", "salir",
+	"ctrl+c", "interrumpir",
+	"rueda-arriba", "arriba",
+	"rueda-abajo", "abajo",
+	"clic", "apuntar",
+)
+
+// Líneas del cuadro antes de la primera fila: borde superior, encabezado y separador.
+const lineasEncabezado = 3
+
+// Visor presenta una tabla de forma interactiva: muestra una ventana de filas con el encabezado fijo,
+// se desplaza con las flechas, Inicio/Fin y RePág/AvPág, ordena por la columna enfocada y filtra filas.
+//
+// Teclas (ver MAPA_VISOR): ↑↓ mueven la fila enfocada, ←→ la columna, "s" ordena (ascendente, descendente, original),
+// "/" filtra, Espacio marca filas, Enter acepta y Esc o "q" cierran sin seleccionar. Si la Consola usa el ratón
+// (ver consola.ConsolaExtendida.UsarRaton), la rueda desplaza las filas y un clic enfoca la fila.
+//
+// # Ejemplo:
+//
+//	datos := cadena.NuevosDatosTabla("Nombre", "Tamaño")
+//	...
+//	filas, err := visor.NuevoVisor(con, *datos).Correr()
+type Visor struct {
+	Datos   cadena.DatosTabla
+	Consola consola.Consola
+	Alto    int          // Cantidad de filas visibles; con 0 se usa el alto de la terminal
+	Teclas  teclado.Mapa // Si es nil, MAPA_VISOR con las personalizaciones de "visor" (ver teclado.Personalizar)
+
+	filas          []int // Índices en Datos.Filas que pasan el filtro, en el orden presentado
+	enfocada       int   // Posición en filas
+	desplazamiento int   // Primera posición de filas en la ventana
+	columna        int
+	columnaOrden   int
+	orden          int // 0: original, 1: ascendente, -1: descendente
+	filtro         string
+	filtrando      bool
+	marcadas       []int // Índices en Datos.Filas
+	lineas         int
+	fila           int // Fila de la terminal de la primera línea presentada; 0 si no se conoce
+	debeCerrar     bool
+}
+
+func NuevoVisor(con consola.Consola, datos cadena.DatosTabla) *Visor {
+	return &Visor{
+		Datos:   datos,
+		Consola: con,
+	}
+}
+
+func (v Visor) teclas() teclado.Mapa {
+	if v.Teclas != nil {
+		return v.Teclas
+	}
+	return teclado.MapaDe("visor", MAPA_VISOR)
+}
+
+func (v Visor) DebeCerrar() bool {
+	return v.debeCerrar
+}
+
+func (v Visor) alto() int {
+	if v.Alto > 0 {
+		return v.Alto
+	}
+	if f := v.Consola.FSalida(); f != nil {
+		if _, alto, err := term.GetSize(int(f.Fd())); err == nil && alto > 8 {
+			// Bordes, encabezado y línea de estado
+			return alto - 6
+		}
+	}
+	return ALTO_POR_DEFECTO
+}
+
+func (v Visor) celda(fila int, columna int) string {
+	if columna < len(v.Datos.Filas[fila]) {
+		return cadena.SinEstilos(v.Datos.Filas[fila][columna])
+	}
+	return ""
+}
+
+// Compara numéricamente si ambos valores son números y, si no, como texto sin distinguir mayúsculas.
+func comparar(a string, b string) int {
+	na, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	nb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// Recalcula las filas visibles según el filtro y el orden, conservando la fila enfocada si sigue visible.
+func (v *Visor) actualizar() {
+	anterior := -1
+	if v.enfocada < len(v.filas) {
+		anterior = v.filas[v.enfocada]
+	}
+	filtro := strings.ToLower(v.filtro)
+	v.filas = v.filas[:0]
+	for i, fila := range v.Datos.Filas {
+		if filtro == "" || slices.ContainsFunc(fila, func(c string) bool {
+			return strings.Contains(strings.ToLower(cadena.SinEstilos(c)), filtro)
+		}) {
+			v.filas = append(v.filas, i)
+		}
+	}
+	if v.orden != 0 {
+		slices.SortStableFunc(v.filas, func(a, b int) int {
+			return v.orden * comparar(v.celda(a, v.columnaOrden), v.celda(b, v.columnaOrden))
+		})
+	}
+	v.enfocada = max(0, slices.Index(v.filas, anterior))
+	v.mover(0)
+}
+
+// Mueve la fila enfocada delta posiciones y ajusta la ventana para que siga visible.
+func (v *Visor) mover(delta int) {
+	v.enfocada = min(max(v.enfocada+delta, 0), max(len(v.filas)-1, 0))
+	alto := v.alto()
+	if v.enfocada < v.desplazamiento {
+		v.desplazamiento = v.enfocada
+	}
+	if v.enfocada >= v.desplazamiento+alto {
+		v.desplazamiento = v.enfocada - alto + 1
+	}
+	v.desplazamiento = min(v.desplazamiento, max(len(v.filas)-alto, 0))
+}
+
+// Enfoca la fila sobre la que ocurrió el evento del ratón, si hay una.
+func (v *Visor) apuntar(tecla teclado.Tecla) {
+	if v.fila <= 0 {
+		return
+	}
+	pos := v.desplazamiento + tecla.Raton.Fila - v.fila - lineasEncabezado
+	if pos >= v.desplazamiento && pos < min(v.desplazamiento+v.alto(), len(v.filas)) {
+		v.enfocada = pos
+	}
+}
+
+func (v *Visor) ordenar() {
+	switch {
+	case v.columnaOrden != v.columna || v.orden == 0:
+		v.columnaOrden, v.orden = v.columna, 1
+	case v.orden == 1:
+		v.orden = -1
+	default:
+		v.orden = 0
+	}
+	v.actualizar()
+}
+
+func (v *Visor) marcar() {
+	if len(v.filas) == 0 {
+		return
+	}
+	fila := v.filas[v.enfocada]
+	if i := slices.Index(v.marcadas, fila); i >= 0 {
+		v.marcadas = slices.Delete(v.marcadas, i, i+1)
+	} else {
+		v.marcadas = append(v.marcadas, fila)
+	}
+}
+
+func (v Visor) borrar() {
+	v.Consola.BorrarLinea()
+	for i := 0; i < v.lineas; i++ {
+		v.Consola.EscribirBytes(teclado.CURSOR_PRINCIPIO_ANTERIOR)
+		v.Consola.BorrarLinea()
+	}
+}
+
+func (v *Visor) renderizar() {
+	tema := consola.Extender(v.Consola).Tema()
+	// La primera columna muestra las filas marcadas.
+	ancho := cadena.AnchoTerminalDe(v.Consola.FSalida())
+	cuadro := cadena.NuevoCuadro(" ").ConTema(tema, false).ConAncho(ancho)
+	for i, t := range v.Datos.Encabezados {
+		switch {
+		case i == v.columnaOrden && v.orden == 1:
+			t += " ▲"
+		case i == v.columnaOrden && v.orden == -1:
+			t += " ▼"
+		}
+		if i == v.columna {
+			t = "›" + t
+		}
+		*cuadro.Columna(i + 1) = cadena.Columna{Titulo: t, Ajuste: cadena.TRUNCAR}
+	}
+
+	fin := min(v.desplazamiento+v.alto(), len(v.filas))
+	for pos := v.desplazamiento; pos < fin; pos++ {
+		fila := v.filas[pos]
+		marca := " "
+		if slices.Contains(v.marcadas, fila) {
+			marca = "■"
+		}
+		celdas := append([]string{marca}, v.Datos.Filas[fila]...)
+		if pos == v.enfocada {
+			for j, c := range celdas {
+				celdas[j] = tema.Aplicar(cadena.ROL_SELECCION, cadena.SinEstilos(c)).S()
+			}
+		}
+		cuadro.AgregarFila(celdas...)
+	}
+	if len(v.filas) == 0 {
+		cuadro.AgregarFila("", "(sin resultados)")
+	}
+
+	estado := fmt.Sprintf("%d-%d de %d", min(v.desplazamiento+1, fin), fin, len(v.filas))
+	if len(v.filas) != len(v.Datos.Filas) {
+		estado += fmt.Sprintf(" (%d en total)", len(v.Datos.Filas))
+	}
+	if len(v.marcadas) > 0 {
+		estado += fmt.Sprintf(" · %d marcadas", len(v.marcadas))
+	}
+	if v.filtrando || v.filtro != "" {
+		estado += " · filtro: " + v.filtro
+		if v.filtrando {
+			estado += "█"
+		}
+	} else {
+		estado += " · ↑↓ ←→ mover · s ordenar · / filtrar · espacio marcar · enter aceptar · q salir"
+	}
+
+	texto := cuadro.Renderizar() + tema.Aplicar(cadena.ROL_ATENUADO, cadena.Truncar(estado, ancho-1, "…")).S()
+	v.lineas = strings.Count(texto, "\n") + 1
+	v.Consola.ImprimirLinea(cadena.Cadena(texto))
+	v.fila = consola.FilaPresentada(v.Consola, v.fila, v.lineas)
+}
+
+func (v *Visor) abrir() {
+	v.debeCerrar = false
+	v.filtrando = false
+	v.actualizar()
+	v.renderizar()
+}
+
+// Procesa una tecla mientras se escribe el filtro.
+func (v *Visor) editarFiltro(tecla teclado.Tecla) {
+	switch {
+	case tecla.Codigo == teclado.CODIGO_ENTER:
+		v.filtrando = false
+	case tecla.Codigo == teclado.CODIGO_ESCAPE:
+		v.filtrando = false
+		v.filtro = ""
+	case tecla.Codigo == teclado.CODIGO_RETROCESO:
+		if _, largo := utf8.DecodeLastRuneInString(v.filtro); largo > 0 {
+			v.filtro = v.filtro[:len(v.filtro)-largo]
+		}
+	case tecla.Imprimible():
+		v.filtro += string(tecla.Runa)
+	default:
+		return
+	}
+	v.enfocada = 0
+	v.actualizar()
+}
+
+// Muestra el visor hasta que se acepte o se cierre. Devuelve las filas marcadas o, si no se marcó ninguna, la enfocada.
+// Al cerrar con Esc o "q" no devuelve filas.
+func (v *Visor) Correr() ([][]string, error) {
+	con := consola.Extender(v.Consola)
+	fila, raton := consola.UbicarComponente(con)
+	if raton {
+		defer con.DeshabilitarRaton()
+	}
+	v.fila = fila
+	v.abrir()
+	var seleccion [][]string
+	var errores []error
+	teclas := v.teclas()
+	for !v.DebeCerrar() {
+		tecla, err := con.LeerEvento()
+		if err != nil {
+			v.Consola.ImprimirError("visor.go > v.Consola.LeerEvento()", err)
+			errores = append(errores, err)
+			v.debeCerrar = true
+		}
+
+		accion := teclas.Accion(tecla)
+		switch {
+		case err != nil:
+
+		case accion == "interrumpir":
+			errores = append(errores, errors.New("programa cerrado por el usuario ^C"))
+			v.debeCerrar = true
+
+		case v.filtrando:
+			v.editarFiltro(tecla)
+
+		case accion == "aceptar":
+			for _, fila := range v.marcadas {
+				seleccion = append(seleccion, v.Datos.Filas[fila])
+			}
+			if len(seleccion) == 0 && len(v.filas) > 0 {
+				seleccion = append(seleccion, v.Datos.Filas[v.filas[v.enfocada]])
+			}
+			v.debeCerrar = true
+
+		case accion == "salir":
+			v.debeCerrar = true
+
+		case accion == "filtrar":
+			v.filtrando = true
+
+		case accion == "ordenar":
+			v.ordenar()
+
+		case accion == "marcar":
+			v.marcar()
+			v.mover(1)
+
+		case accion == "apuntar":
+			v.apuntar(tecla)
+		case accion == "arriba":
+			v.mover(-1)
+		case accion == "abajo":
+			v.mover(1)
+		case accion == "pagina-anterior":
+			v.mover(-v.alto())
+		case accion == "pagina-siguiente":
+			v.mover(v.alto())
+		case accion == "primera":
+			v.mover(-len(v.filas))
+		case accion == "ultima":
+			v.mover(len(v.filas))
+		case accion == "columna-anterior":
+			v.columna = max(v.columna-1, 0)
+		case accion == "columna-siguiente":
+			v.columna = min(v.columna+1, max(len(v.Datos.Encabezados)-1, 0))
+		}
+		v.borrar()
+		v.renderizar()
+	}
+	return seleccion, errors.Join(errores...)
+}
@@ -0,0 +1,190 @@
+package pregunta
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+)
+
+const (
+	FORMATO_FECHA      = "2006-01-02"
+	FORMATO_FECHA_HORA = "2006-01-02 15:04"
+)
+
+// Valor por defecto, límites y precisión de Fecha.
+type OpcionesFecha struct {
+	Predeterminada time.Time // Por defecto, el momento actual
+	Minima, Maxima time.Time // Sin límite si son cero
+	ConHora        bool      // Pide también la hora y los minutos
+}
+
+func (o OpcionesFecha) formato() string {
+	if o.ConHora {
+		return FORMATO_FECHA_HORA
+	}
+	return FORMATO_FECHA
+}
+
+// Devuelve un error si f está fuera de los límites.
+func (o OpcionesFecha) validar(f time.Time) error {
+	switch {
+	case !o.Minima.IsZero() && f.Before(o.Minima):
+		return fmt.Errorf("la fecha no puede ser anterior a %s", o.Minima.Format(o.formato()))
+	case !o.Maxima.IsZero() && f.After(o.Maxima):
+		return fmt.Errorf("la fecha no puede ser posterior a %s", o.Maxima.Format(o.formato()))
+	}
+	return nil
+}
+
+// Ajusta f a los límites.
+func (o OpcionesFecha) acotar(f time.Time) time.Time {
+	if !o.Minima.IsZero() && f.Before(o.Minima) {
+		return o.Minima
+	}
+	if !o.Maxima.IsZero() && f.After(o.Maxima) {
+		return o.Maxima
+	}
+	return f
+}
+
+// Un campo editable de la fecha: su ancho en dígitos, cómo se lo suma y cómo se lo reemplaza.
+type campoFecha struct {
+	ancho     int
+	sumar     func(f time.Time, n int) time.Time
+	reemplazo func(f time.Time, v int) (time.Time, bool)
+}
+
+func fechaValida(a, m, d, h, min int, loc *time.Location) (time.Time, bool) {
+	f := time.Date(a, time.Month(m), d, h, min, 0, 0, loc)
+	return f, f.Year() == a && int(f.Month()) == m && f.Day() == d && f.Hour() == h && f.Minute() == min
+}
+
+var camposFecha = []campoFecha{
+	{4, func(f time.Time, n int) time.Time { return f.AddDate(n, 0, 0) }, func(f time.Time, v int) (time.Time, bool) {
+		return fechaValida(v, int(f.Month()), f.Day(), f.Hour(), f.Minute(), f.Location())
+	}},
+	{2, func(f time.Time, n int) time.Time { return f.AddDate(0, n, 0) }, func(f time.Time, v int) (time.Time, bool) {
+		return fechaValida(f.Year(), v, f.Day(), f.Hour(), f.Minute(), f.Location())
+	}},
+	{2, func(f time.Time, n int) time.Time { return f.AddDate(0, 0, n) }, func(f time.Time, v int) (time.Time, bool) {
+		return fechaValida(f.Year(), int(f.Month()), v, f.Hour(), f.Minute(), f.Location())
+	}},
+	{2, func(f time.Time, n int) time.Time { return f.Add(time.Duration(n) * time.Hour) }, func(f time.Time, v int) (time.Time, bool) {
+		return fechaValida(f.Year(), int(f.Month()), f.Day(), v, f.Minute(), f.Location())
+	}},
+	{2, func(f time.Time, n int) time.Time { return f.Add(time.Duration(n) * time.Minute) }, func(f time.Time, v int) (time.Time, bool) {
+		return fechaValida(f.Year(), int(f.Month()), f.Day(), f.Hour(), v, f.Location())
+	}},
+}
+
+// Mapa de teclas de Fecha (nombre "fecha" en la configuración de teclado). Acciones: siguiente y anterior (campo),
+// sumar y restar (uno al campo), aceptar e interrumpir.
+var MAPA_FECHA = teclado.NuevoMapa(
+	"tab", "siguiente", "derecha", "siguiente",
+	"shift+tab", "anterior", "izquierda", "anterior",
+	"arriba", "sumar",
+	"abajo", "restar",
+	"enter", "aceptar",
+	"ctrl+c", "interrumpir",
+)
+
+// Pregunta una fecha (y opcionalmente una hora) dentro de los límites de o.
+//
+// En una terminal se edita en el lugar (ver MAPA_FECHA): ←/→ o Tab cambian de campo, ↑/↓ suman o restan uno, los dígitos
+// reemplazan el campo y Enter acepta. Sin terminal se lee una línea con el formato FORMATO_FECHA (o FORMATO_FECHA_HORA si o.ConHora);
+// una línea vacía acepta la fecha predeterminada.
+func Fecha(con Consola, mensaje Cadena, o OpcionesFecha) (time.Time, error) {
+	f := o.Predeterminada
+	if f.IsZero() {
+		f = time.Now()
+	}
+	if !o.ConHora {
+		f = time.Date(f.Year(), f.Month(), f.Day(), 0, 0, 0, 0, f.Location())
+	}
+	f = o.acotar(f.Truncate(time.Minute))
+
+	if !esInteractiva(con) {
+		indicacion := mensaje + consola.Extender(con).Tema().Aplicar(cadena.ROL_ATENUADO, " ["+f.Format(o.formato())+"]")
+		return leerValidado(con, indicacion, func(s string) (time.Time, error) {
+			if s == "" {
+				return f, nil
+			}
+			leida, err := time.ParseInLocation(o.formato(), s, f.Location())
+			if err != nil {
+				return leida, fmt.Errorf("%q no tiene el formato %s", s, strings.NewReplacer("2006", "AAAA", "01", "MM", "02", "DD", "15", "hh", "04", "mm").Replace(o.formato()))
+			}
+			return leida, o.validar(leida)
+		})
+	}
+
+	campos := camposFecha[:3]
+	if o.ConHora {
+		campos = camposFecha
+	}
+	actual, digitos := 0, ""
+	dibujar := func(final bool) {
+		tema := consola.Extender(con).Tema()
+		valores := []string{fmt.Sprintf("%04d", f.Year()), fmt.Sprintf("%02d", f.Month()), fmt.Sprintf("%02d", f.Day()),
+			fmt.Sprintf("%02d", f.Hour()), fmt.Sprintf("%02d", f.Minute())}
+		separadores := []string{"", "-", "-", " ", ":"}
+		var sb strings.Builder
+		for i := range campos {
+			sb.WriteString(separadores[i])
+			v := valores[i]
+			if i == actual && digitos != "" {
+				v = digitos + strings.Repeat("_", campos[i].ancho-len(digitos))
+			}
+			if i == actual && !final {
+				v = tema.Aplicar(cadena.ROL_SELECCION, v).S()
+			}
+			sb.WriteString(v)
+		}
+		linea := "\r" + tema.Señalador(">").S() + mensaje.S() + ": " + sb.String()
+		if !final {
+			linea += "  " + tema.Aplicar(cadena.ROL_ATENUADO, "←→ campo · ↑↓ cambiar · Enter aceptar").S()
+		}
+		con.ImprimirCadena(Cadena(linea + consola.BORRAR_HASTA_FIN))
+	}
+
+	teclas := teclado.MapaDe("fecha", MAPA_FECHA)
+	for {
+		dibujar(false)
+		tecla, err := consola.Extender(con).LeerEvento()
+		if err != nil {
+			return f, err
+		}
+		switch accion := teclas.Accion(tecla); {
+		case accion == "interrumpir":
+			con.ImprimirLinea("")
+			return f, ERROR_CANCELADO
+		case accion == "aceptar":
+			dibujar(true)
+			con.ImprimirLinea("")
+			return f, nil
+		case accion == "siguiente":
+			actual, digitos = (actual+1)%len(campos), ""
+		case accion == "anterior":
+			actual, digitos = (actual+len(campos)-1)%len(campos), ""
+		case accion == "sumar":
+			f, digitos = o.acotar(campos[actual].sumar(f, 1)), ""
+		case accion == "restar":
+			f, digitos = o.acotar(campos[actual].sumar(f, -1)), ""
+		case accion == "" && tecla.Imprimible() && tecla.Runa >= '0' && tecla.Runa <= '9':
+			digitos += string(tecla.Runa)
+			if len(digitos) < campos[actual].ancho {
+				continue
+			}
+			var v int
+			fmt.Sscanf(digitos, "%d", &v)
+			if nueva, ok := campos[actual].reemplazo(f, v); ok && o.validar(nueva) == nil {
+				f = nueva
+				actual = min(actual+1, len(campos)-1)
+			}
+			digitos = ""
+		}
+	}
+}
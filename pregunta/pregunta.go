@@ -0,0 +1,274 @@
+/*
+Preguntas tipadas para las acciones: confirmación, números, texto validado, selección simple o múltiple y fechas.
+
+Si la entrada no es una terminal, cada pregunta lee líneas de texto plano, de modo que se pueden responder desde un archivo o una tubería.
+*/
+
+package pregunta
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/consola/cadena"
+	"github.com/hernanatn/aplicacion.go/consola/teclado"
+	"github.com/hernanatn/aplicacion.go/menu"
+	"github.com/hernanatn/aplicacion.go/menu/multimenu"
+	"golang.org/x/term"
+)
+
+type Consola = consola.Consola
+type Cadena = cadena.Cadena
+
+// Error que devuelven las preguntas interactivas cuando el usuario las cancela con ^C.
+var ERROR_CANCELADO = errors.New("pregunta cancelada por el usuario ^C")
+
+// Devuelve true si se puede interactuar tecla a tecla: la entrada y la salida de con son terminales.
+func esInteractiva(con Consola) bool {
+	return con.EsTerminal() && term.IsTerminal(int(con.FEntrada().Fd())) && term.IsTerminal(int(con.FSalida().Fd()))
+}
+
+// Pregunta hasta que la respuesta se pueda convertir sin error. Los errores de conversión se muestran y se vuelve a preguntar;
+// los de lectura (por ejemplo, el fin de la entrada) se devuelven.
+func leerValidado[T any](con Consola, mensaje Cadena, convertir func(string) (T, error)) (T, error) {
+	for {
+		s, err := con.Leer(mensaje)
+		if errors.Is(err, consola.ERROR_INTERRUMPIDO) {
+			err = ERROR_CANCELADO
+		}
+		if err != nil {
+			var cero T
+			return cero, err
+		}
+		// Las respuestas son de una línea: las líneas de un texto pegado se unen con espacios.
+		v, err := convertir(strings.ReplaceAll(s.S(), "\n", " "))
+		if err == nil {
+			return v, nil
+		}
+		con.ImprimirLinea(consola.Extender(con).Tema().Aplicar(cadena.ROL_ERROR, "  "+err.Error()))
+	}
+}
+
+// Pregunta un texto libre. Si validar no es nil, se vuelve a preguntar mientras devuelva un error.
+//
+// # Ejemplo:
+//
+//	nombre, err := pregunta.Texto(con, "Nombre del proyecto", func(s string) error {
+//		if s == "" {
+//			return errors.New("el nombre no puede estar vacío")
+//		}
+//		return nil
+//	})
+func Texto(con Consola, mensaje Cadena, validar func(string) error) (string, error) {
+	return leerValidado(con, mensaje, func(s string) (string, error) {
+		if validar != nil {
+			if err := validar(s); err != nil {
+				return "", err
+			}
+		}
+		return s, nil
+	})
+}
+
+// Límites y valor por defecto de Entero y Decimal.
+type OpcionesNumero[T int | float64] struct {
+	Minimo, Maximo T  // Sólo se aplican si Minimo < Maximo
+	Predeterminado *T // Se usa si la respuesta está vacía
+}
+
+func numero[T int | float64](con Consola, mensaje Cadena, o OpcionesNumero[T], interpretar func(string) (T, error)) (T, error) {
+	if o.Minimo < o.Maximo {
+		mensaje += Cadena(fmt.Sprintf(" (%v-%v)", o.Minimo, o.Maximo))
+	}
+	if o.Predeterminado != nil {
+		mensaje += consola.Extender(con).Tema().Aplicar(cadena.ROL_ATENUADO, fmt.Sprintf(" [%v]", *o.Predeterminado))
+	}
+	return leerValidado(con, mensaje, func(s string) (T, error) {
+		if s == "" && o.Predeterminado != nil {
+			return *o.Predeterminado, nil
+		}
+		v, err := interpretar(s)
+		switch {
+		case err != nil:
+			return v, fmt.Errorf("%q no es un número válido", s)
+		case o.Minimo < o.Maximo && (v < o.Minimo || v > o.Maximo):
+			return v, fmt.Errorf("el valor debe estar entre %v y %v", o.Minimo, o.Maximo)
+		}
+		return v, nil
+	})
+}
+
+// Pregunta un número entero, opcionalmente dentro de un rango.
+func Entero(con Consola, mensaje Cadena, o OpcionesNumero[int]) (int, error) {
+	return numero(con, mensaje, o, strconv.Atoi)
+}
+
+// Pregunta un número decimal, opcionalmente dentro de un rango. Se acepta la coma como separador decimal.
+func Decimal(con Consola, mensaje Cadena, o OpcionesNumero[float64]) (float64, error) {
+	return numero(con, mensaje, o, func(s string) (float64, error) {
+		return strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	})
+}
+
+// Interpreta una respuesta afirmativa o negativa. ok es false si s no es ninguna de las dos.
+func interpretarConfirmacion(s string) (respuesta bool, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "s", "si", "sí", "y", "yes":
+		return true, true
+	case "n", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// Mapa de teclas de Confirmar (nombre "confirmar" en la configuración de teclado). Acciones: si, no, aceptar (la
+// respuesta predeterminada) e interrumpir.
+var MAPA_CONFIRMAR = teclado.NuevoMapa(
+	"s", "si", "S", "si", "y", "si", "Y", "si",
+	"n", "no", "N", "no",
+	"enter", "aceptar",
+	"ctrl+c", "interrumpir",
+)
+
+// Pregunta sí o no. Con una respuesta vacía (o Enter) se devuelve predeterminado.
+// En una terminal basta con pulsar s/y o n, sin Enter (ver MAPA_CONFIRMAR).
+func Confirmar(con Consola, mensaje Cadena, predeterminado bool) (bool, error) {
+	opciones := " [s/N] "
+	if predeterminado {
+		opciones = " [S/n] "
+	}
+	if !esInteractiva(con) {
+		return leerValidado(con, mensaje+Cadena(opciones), func(s string) (bool, error) {
+			if s == "" {
+				return predeterminado, nil
+			}
+			if r, ok := interpretarConfirmacion(s); ok {
+				return r, nil
+			}
+			return false, errors.New("responda s (sí) o n (no)")
+		})
+	}
+
+	con.ImprimirCadena(consola.Extender(con).Tema().Señalador(">") + mensaje + consola.Extender(con).Tema().Aplicar(cadena.ROL_ATENUADO, opciones))
+	teclas := teclado.MapaDe("confirmar", MAPA_CONFIRMAR)
+	for {
+		tecla, err := consola.Extender(con).LeerEvento()
+		if err != nil {
+			return false, err
+		}
+		var respuesta bool
+		switch teclas.Accion(tecla) {
+		case "interrumpir":
+			con.ImprimirLinea("")
+			return false, ERROR_CANCELADO
+		case "si":
+			respuesta = true
+		case "no":
+			respuesta = false
+		case "aceptar":
+			respuesta = predeterminado
+		default:
+			continue
+		}
+		if respuesta {
+			con.ImprimirLinea("sí")
+		} else {
+			con.ImprimirLinea("no")
+		}
+		return respuesta, nil
+	}
+}
+
+// Interpreta una respuesta a Elegir o ElegirVarios sin terminal: el número de la opción (desde 1) o su nombre exacto.
+func interpretarOpcion(s string, opciones []string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(opciones) {
+		return n - 1, nil
+	}
+	if i := slices.Index(opciones, s); i >= 0 {
+		return i, nil
+	}
+	return -1, fmt.Errorf("%q no es una opción (1-%d)", s, len(opciones))
+}
+
+func listarOpciones(con Consola, mensaje Cadena, opciones []string) {
+	con.ImprimirLinea(mensaje)
+	for i, o := range opciones {
+		con.ImprimirLinea(Cadena(fmt.Sprintf("  %d) %s", i+1, o)))
+	}
+}
+
+func opcionesMenu(opciones []string) []*menu.Opcion {
+	lista := make([]*menu.Opcion, len(opciones))
+	for i, o := range opciones {
+		lista[i] = &menu.Opcion{Nombre: o}
+	}
+	return lista
+}
+
+// Pregunta por una de las opciones con un menu.Menu y devuelve su índice.
+// Sin terminal se listan las opciones numeradas y se lee el número o el nombre de la elegida.
+func Elegir(con Consola, mensaje Cadena, opciones ...string) (int, error) {
+	if len(opciones) == 0 {
+		return -1, errors.New("no hay opciones para elegir")
+	}
+	if !esInteractiva(con) {
+		listarOpciones(con, mensaje, opciones)
+		return leerValidado(con, "Opción", func(s string) (int, error) {
+			return interpretarOpcion(s, opciones)
+		})
+	}
+
+	con.ImprimirLinea(consola.Extender(con).Tema().Señalador(">") + mensaje)
+	m := menu.NuevoMenu(con, '>')
+	for _, o := range opcionesMenu(opciones) {
+		m.RegistrarOpcion(o)
+	}
+	elegida, err := m.Correr()
+	if elegida == nil {
+		return -1, errors.Join(err, ERROR_CANCELADO)
+	}
+	return slices.Index(m.Opciones, elegida), err
+}
+
+// Pregunta por cualquier cantidad de las opciones con un multimenu.MultiMenu y devuelve sus índices, en orden.
+// Sin terminal se listan las opciones numeradas y se leen números o nombres separados por comas.
+func ElegirVarios(con Consola, mensaje Cadena, opciones ...string) ([]int, error) {
+	if len(opciones) == 0 {
+		return nil, errors.New("no hay opciones para elegir")
+	}
+	if !esInteractiva(con) {
+		listarOpciones(con, mensaje, opciones)
+		return leerValidado(con, "Opciones (separadas por comas)", func(s string) ([]int, error) {
+			elegidas := []int{}
+			for _, parte := range strings.Split(s, ",") {
+				if parte = strings.TrimSpace(parte); parte == "" {
+					continue
+				}
+				i, err := interpretarOpcion(parte, opciones)
+				if err != nil {
+					return nil, err
+				}
+				if !slices.Contains(elegidas, i) {
+					elegidas = append(elegidas, i)
+				}
+			}
+			slices.Sort(elegidas)
+			return elegidas, nil
+		})
+	}
+
+	con.ImprimirLinea(consola.Extender(con).Tema().Señalador(">") + mensaje + consola.Extender(con).Tema().Aplicar(cadena.ROL_ATENUADO, " (Espacio marca, ^A todas, ^N ninguna, Enter acepta)"))
+	m := multimenu.NuevoMultiMenu(con, '>')
+	for _, o := range opcionesMenu(opciones) {
+		m.RegistrarOpcion(o)
+	}
+	elegidas, _, err := m.CorrerIndices()
+	if errors.Is(err, menu.ERROR_SALIDA) {
+		return nil, errors.Join(err, ERROR_CANCELADO)
+	}
+	return elegidas, err
+}
@@ -0,0 +1,77 @@
+package pregunta_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hernanatn/aplicacion.go/consola"
+	"github.com/hernanatn/aplicacion.go/pregunta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPreguntasSinTerminal responde las preguntas con líneas de texto plano, incluyendo respuestas inválidas que se vuelven a pedir
+func TestPreguntasSinTerminal(t *testing.T) {
+	le, ee, err := os.Pipe()
+	require.NoError(t, err)
+	ls, es, err := os.Pipe()
+	require.NoError(t, err)
+	defer ls.Close()
+
+	_, err = io.WriteString(ee, "\nproyecto\n42\n7\n\n2,5\nverde\n3, rojo\n2024-02-30\n2024-02-29\n")
+	require.NoError(t, err)
+	ee.Close()
+	con := consola.NuevaConsola(le, es)
+
+	texto, err := pregunta.Texto(con, "Nombre", func(s string) error {
+		if s == "" {
+			return errors.New("no puede estar vacío")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "proyecto", texto)
+
+	n, err := pregunta.Entero(con, "Cantidad", pregunta.OpcionesNumero[int]{Minimo: 1, Maximo: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	ok, err := pregunta.Confirmar(con, "¿Continuar?", true)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	d, err := pregunta.Decimal(con, "Factor", pregunta.OpcionesNumero[float64]{})
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, d)
+
+	colores := []string{"rojo", "verde", "azul"}
+	i, err := pregunta.Elegir(con, "Color", colores...)
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	varios, err := pregunta.ElegirVarios(con, "Colores", colores...)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 2}, varios)
+
+	f, err := pregunta.Fecha(con, "Fecha", pregunta.OpcionesFecha{})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local), f)
+
+	_, err = pregunta.Elegir(con, "Nada")
+	assert.Error(t, err)
+	_, err = pregunta.ElegirVarios(con, "Nada")
+	assert.Error(t, err)
+
+	_, err = pregunta.Texto(con, "Otra", nil)
+	assert.ErrorIs(t, err, io.EOF)
+
+	es.Close()
+	salida, _ := io.ReadAll(ls)
+	assert.Contains(t, string(salida), "no puede estar vacío")
+	assert.Contains(t, string(salida), "el valor debe estar entre 1 y 10")
+	assert.Contains(t, string(salida), "  2) verde")
+	assert.Contains(t, string(salida), "no tiene el formato AAAA-MM-DD")
+}